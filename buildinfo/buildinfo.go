@@ -0,0 +1,76 @@
+package buildinfo
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// These are populated at build time through -ldflags, see the Makefile.
+var (
+	version       string
+	buildDateTime string
+	branch        string
+	revision      string
+)
+
+const unset = "UNSET"
+
+// Info describes the build of the running binary.
+type Info struct {
+	Version       string `json:"version" xml:"version"`
+	BuildDateTime string `json:"buildDateTime" xml:"buildDateTime"`
+	Branch        string `json:"branch" xml:"branch"`
+	Revision      string `json:"revision" xml:"revision"`
+	GoVersion     string `json:"goVersion" xml:"goVersion"`
+	Modified      bool   `json:"modified,omitempty" xml:"modified,omitempty"`
+}
+
+// Get returns the build information injected by the linker, falling back to the
+// module and VCS information embedded by the go toolchain for any value not set.
+func Get() Info {
+	info := Info{
+		Version:       version,
+		BuildDateTime: buildDateTime,
+		Branch:        branch,
+		Revision:      revision,
+		GoVersion:     runtime.Version(),
+	}
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if isUnset(info.Version) && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+			info.Version = bi.Main.Version
+		}
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				if isUnset(info.Revision) {
+					info.Revision = s.Value
+				}
+			case "vcs.time":
+				if isUnset(info.BuildDateTime) {
+					info.BuildDateTime = s.Value
+				}
+			case "vcs.modified":
+				info.Modified = s.Value == "true"
+			}
+		}
+	}
+
+	for _, v := range []*string{&info.Version, &info.BuildDateTime, &info.Branch, &info.Revision} {
+		if *v == "" {
+			*v = unset
+		}
+	}
+	return info
+}
+
+// String returns a single line summary of the build.
+func (i Info) String() string {
+	return fmt.Sprintf("version=%s revision=%s branch=%s built=%s go=%s",
+		i.Version, i.Revision, i.Branch, i.BuildDateTime, i.GoVersion)
+}
+
+func isUnset(s string) bool {
+	return s == "" || s == unset
+}
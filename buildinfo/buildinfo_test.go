@@ -0,0 +1,46 @@
+package buildinfo
+
+import "testing"
+
+func TestGet(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		version  string
+		revision string
+		// then
+		expectedVersion  string
+		expectedRevision string
+	}{
+		{
+			description:      "values injected by the linker are used",
+			version:          "v1.2.3",
+			revision:         "abc123",
+			expectedVersion:  "v1.2.3",
+			expectedRevision: "abc123",
+		},
+		{
+			description:      "missing values fall back to UNSET",
+			expectedVersion:  unset,
+			expectedRevision: unset,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			version, revision = tt.version, tt.revision
+			defer func() { version, revision = "", "" }()
+
+			got := Get()
+			// test binaries carry no module version or vcs settings, so nothing is filled in from them
+			if got.Version != tt.expectedVersion {
+				t.Errorf("unexpected version: got %s, expected %s", got.Version, tt.expectedVersion)
+			}
+			if got.Revision != tt.expectedRevision {
+				t.Errorf("unexpected revision: got %s, expected %s", got.Revision, tt.expectedRevision)
+			}
+			if got.GoVersion == "" {
+				t.Error("expected go version to be set")
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package bulk
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/waikco/cats-v1/model"
+)
+
+func readAll(t *testing.T, r Reader) []Record {
+	t.Helper()
+	var records []Record
+	for {
+		record, err := r.Next()
+		if err == io.EOF {
+			return records
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if record.Err != nil {
+			// only the presence of an error is compared
+			record.Err = io.ErrUnexpectedEOF
+		}
+		records = append(records, record)
+	}
+}
+
+func TestReader(t *testing.T) {
+	neutered := true
+	tests := []struct {
+		description string
+		// given
+		format  Format
+		mapping Mapping
+		input   string
+		// then
+		expectedRecords []Record
+	}{
+		{
+			description: "csv",
+			format:      CSV,
+			input:       "name,color,age\ncat-1,black,1\ncat-2,white,two\n",
+			expectedRecords: []Record{
+				{Line: 2, Cat: model.Cat{Name: "cat-1", Color: "black", Age: 1}},
+				{Line: 3, Cat: model.Cat{Name: "cat-2", Color: "white"}, Err: io.ErrUnexpectedEOF},
+			},
+		},
+		{
+			description: "csv with details",
+			format:      CSV,
+			input: "name,color,breed,birth_date,sex,neutered,weight_kg,microchip,tags\n" +
+				"cat-1,black,siamese,2020-01-02,female,true,4.5,985112345678901,indoor; shy\n" +
+				"cat-2,white,,,,maybe,,,\n",
+			expectedRecords: []Record{
+				{Line: 2, Cat: model.Cat{Name: "cat-1", Color: "black", Breed: "siamese", BirthDate: "2020-01-02", Sex: "female",
+					Neutered: &neutered, WeightKg: 4.5, Microchip: "985112345678901", Tags: []string{"indoor", "shy"}}},
+				{Line: 3, Cat: model.Cat{Name: "cat-2", Color: "white", Neutered: new(bool)}, Err: io.ErrUnexpectedEOF},
+			},
+		},
+		{
+			description: "csv with header mapping",
+			format:      CSV,
+			mapping:     Mapping{"name": "Cat Name", "color": "Colour"},
+			input:       "Colour,Cat Name,Notes\nginger,cat-1,friendly\n",
+			expectedRecords: []Record{
+				{Line: 2, Cat: model.Cat{Name: "cat-1", Color: "ginger"}},
+			},
+		},
+		{
+			description: "ndjson skips blank lines",
+			format:      NDJSON,
+			input:       "{\"name\":\"cat-1\",\"color\":\"black\",\"age\":1}\n\n{\"name\":\"cat-2\",\"age\":\"two\"}\n",
+			expectedRecords: []Record{
+				{Line: 1, Cat: model.Cat{Name: "cat-1", Color: "black", Age: 1}},
+				{Line: 3, Cat: model.Cat{Name: "cat-2"}, Err: io.ErrUnexpectedEOF},
+			},
+		},
+		{
+			description: "json array",
+			format:      JSON,
+			input:       `[{"name":"cat-1","color":"black","age":1},{"name":"cat-2","age":"two"},{"name":"cat-3"}]`,
+			expectedRecords: []Record{
+				{Line: 1, Cat: model.Cat{Name: "cat-1", Color: "black", Age: 1}},
+				{Line: 2, Cat: model.Cat{Name: "cat-2"}, Err: io.ErrUnexpectedEOF},
+				{Line: 3, Cat: model.Cat{Name: "cat-3"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			r, err := NewReader(strings.NewReader(tt.input), tt.format, tt.mapping)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			got := readAll(t, r)
+
+			if !reflect.DeepEqual(got, tt.expectedRecords) {
+				t.Errorf("unexpected records: got %+v, expected %+v", got, tt.expectedRecords)
+			}
+		})
+	}
+}
+
+func TestReader_CSVMissingColumn(t *testing.T) {
+	_, err := NewReader(strings.NewReader("name,age\ncat-1,1\n"), CSV, nil)
+	if err == nil {
+		t.Error("expected an error for a header without a color column")
+	}
+}
+
+func TestWriter(t *testing.T) {
+	neutered := true
+	cats := []model.Cat{
+		{ID: "1", Name: "cat-1", Color: "black", Age: 1},
+		{ID: "2", Name: "cat, 2", Color: "white", Age: 2, Breed: "siamese", Neutered: &neutered, WeightKg: 4.5,
+			Tags: []string{"indoor", "shy"}},
+	}
+
+	tests := []struct {
+		description string
+		// given
+		format Format
+		cats   []model.Cat
+		// then
+		expectedOutput string
+	}{
+		{
+			description: "csv",
+			format:      CSV,
+			cats:        cats,
+			expectedOutput: "id,name,color,age,breed,birth_date,sex,neutered,weight_kg,microchip,tags\n" +
+				"1,cat-1,black,1,,,,,,,\n2,\"cat, 2\",white,2,siamese,,,true,4.5,,indoor;shy\n",
+		},
+		{
+			description:    "csv without cats",
+			format:         CSV,
+			expectedOutput: "id,name,color,age,breed,birth_date,sex,neutered,weight_kg,microchip,tags\n",
+		},
+		{
+			description:    "ndjson",
+			format:         NDJSON,
+			cats:           cats,
+			expectedOutput: "{\"id\":\"1\",\"name\":\"cat-1\",\"color\":\"black\",\"age\":1}\n{\"id\":\"2\",\"name\":\"cat, 2\",\"color\":\"white\",\"age\":2,\"breed\":\"siamese\",\"neutered\":true,\"weightKg\":4.5,\"tags\":[\"indoor\",\"shy\"]}\n",
+		},
+		{
+			description:    "json",
+			format:         JSON,
+			cats:           cats,
+			expectedOutput: "[\n{\"id\":\"1\",\"name\":\"cat-1\",\"color\":\"black\",\"age\":1},\n{\"id\":\"2\",\"name\":\"cat, 2\",\"color\":\"white\",\"age\":2,\"breed\":\"siamese\",\"neutered\":true,\"weightKg\":4.5,\"tags\":[\"indoor\",\"shy\"]}\n]\n",
+		},
+		{
+			description:    "json without cats",
+			format:         JSON,
+			expectedOutput: "[]\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var b bytes.Buffer
+			w, err := NewWriter(&b, tt.format)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, c := range tt.cats {
+				if err := w.Write(c); err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			if err := w.Close(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if b.String() != tt.expectedOutput {
+				t.Errorf("unexpected output: got %q, expected %q", b.String(), tt.expectedOutput)
+			}
+
+			// everything written must be readable again
+			r, err := NewReader(&b, tt.format, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := readAll(t, r); len(got) != len(tt.cats) {
+				t.Errorf("unexpected records read back: got %d, expected %d", len(got), len(tt.cats))
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package bulk
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// Format is an encoding of a sequence of cats.
+type Format string
+
+const (
+	CSV    Format = "csv"
+	JSON   Format = "json"
+	NDJSON Format = "ndjson"
+)
+
+// ParseFormat parses a format name.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case CSV, JSON, NDJSON:
+		return f, nil
+	case "jsonl":
+		return NDJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q, use one of csv, json or ndjson", s)
+	}
+}
+
+// FormatFromPath guesses the format of a file from its extension.
+func FormatFromPath(path string) (Format, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return "", fmt.Errorf("unable to tell the format of %q from its extension", path)
+	}
+	return ParseFormat(ext)
+}
+
+// ContentType returns the media type of the format.
+func (f Format) ContentType() string {
+	switch f {
+	case CSV:
+		return "text/csv"
+	case NDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/json"
+	}
+}
+
+// Columns are the cat fields held in csv files, in their default order. Tags are held in a
+// single column, separated by TagSeparator.
+var Columns = []string{"id", "name", "color", "age", "breed", "birth_date", "sex", "neutered", "weight_kg", "microchip", "tags"}
+
+// TagSeparator separates the tags of a cat in csv files.
+const TagSeparator = ";"
+
+// Mapping maps cat fields to csv column names.
+type Mapping map[string]string
+
+// ParseMapping parses a mapping such as "name=Cat Name,color=Colour".
+func ParseMapping(s string) (Mapping, error) {
+	m := Mapping{}
+	if strings.TrimSpace(s) == "" {
+		return m, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || strings.TrimSpace(kv[1]) == "" {
+			return nil, fmt.Errorf("invalid mapping %q, expected field=column", pair)
+		}
+		field := strings.ToLower(strings.TrimSpace(kv[0]))
+		if !isColumn(field) {
+			return nil, fmt.Errorf("invalid mapping %q, unknown field %s", pair, field)
+		}
+		m[field] = strings.TrimSpace(kv[1])
+	}
+	return m, nil
+}
+
+// column returns the csv column name of a cat field.
+func (m Mapping) column(field string) string {
+	if c, ok := m[field]; ok {
+		return c
+	}
+	return field
+}
+
+func isColumn(field string) bool {
+	for _, c := range Columns {
+		if c == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package bulk
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/waikco/cats-v1/model"
+)
+
+// Record is a single cat read from an import. Err is set when the record could not be
+// parsed, in which case reading can continue with the next record.
+type Record struct {
+	// Line is the line the record was read from for csv and ndjson, and the
+	// position of the record in the array for json, starting at 1.
+	Line int
+	Cat  model.Cat
+	Err  error
+}
+
+// Reader reads cats one at a time.
+type Reader interface {
+	// Next returns the next record, or io.EOF when there are none left.
+	// Any other error means the input is unreadable and reading must stop.
+	Next() (Record, error)
+}
+
+// NewReader returns a Reader for input in the given format. The mapping
+// is only used for csv, whose first line must be a header.
+func NewReader(r io.Reader, format Format, mapping Mapping) (Reader, error) {
+	switch format {
+	case CSV:
+		return newCSVReader(r, mapping)
+	case JSON:
+		return newJSONReader(r)
+	case NDJSON:
+		s := bufio.NewScanner(r)
+		s.Buffer(make([]byte, 64*1024), 10*1024*1024)
+		return &ndjsonReader{scanner: s}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+type csvReader struct {
+	reader  *csv.Reader
+	columns map[string]int
+}
+
+func newCSVReader(r io.Reader, mapping Mapping) (*csvReader, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading csv header: %v", err)
+	}
+	index := map[string]int{}
+	for i, h := range header {
+		index[strings.TrimSpace(h)] = i
+	}
+
+	columns := map[string]int{}
+	for _, field := range Columns {
+		if i, ok := index[mapping.column(field)]; ok {
+			columns[field] = i
+		} else if _, mapped := mapping[field]; mapped {
+			return nil, fmt.Errorf("csv header has no column %q mapped to %s", mapping[field], field)
+		}
+	}
+	for _, field := range []string{"name", "color"} {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("csv header has no column for %s", field)
+		}
+	}
+	return &csvReader{reader: reader, columns: columns}, nil
+}
+
+func (c *csvReader) Next() (Record, error) {
+	row, err := c.reader.Read()
+	if err == io.EOF {
+		return Record{}, io.EOF
+	}
+	if err != nil {
+		if parseErr, ok := err.(*csv.ParseError); ok {
+			return Record{Line: parseErr.StartLine, Err: err}, nil
+		}
+		return Record{}, err
+	}
+	line, _ := c.reader.FieldPos(0)
+
+	value := func(field string) string {
+		if i, ok := c.columns[field]; ok && i < len(row) {
+			return strings.TrimSpace(row[i])
+		}
+		return ""
+	}
+
+	record := Record{Line: line}
+	record.Cat.ID = value("id")
+	record.Cat.Name = value("name")
+	record.Cat.Color = value("color")
+	record.Cat.Breed = value("breed")
+	record.Cat.BirthDate = value("birth_date")
+	record.Cat.Sex = value("sex")
+	record.Cat.Microchip = value("microchip")
+	if tags := value("tags"); tags != "" {
+		for _, tag := range strings.Split(tags, TagSeparator) {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				record.Cat.Tags = append(record.Cat.Tags, tag)
+			}
+		}
+	}
+	if age := value("age"); age != "" {
+		if record.Cat.Age, err = strconv.Atoi(age); err != nil {
+			record.Err = fmt.Errorf("invalid age %q", age)
+		}
+	}
+	if neutered := value("neutered"); neutered != "" {
+		b, err := strconv.ParseBool(neutered)
+		if err != nil {
+			record.Err = fmt.Errorf("invalid neutered %q", neutered)
+		}
+		record.Cat.Neutered = &b
+	}
+	if weight := value("weight_kg"); weight != "" {
+		if record.Cat.WeightKg, err = strconv.ParseFloat(weight, 64); err != nil {
+			record.Err = fmt.Errorf("invalid weight_kg %q", weight)
+		}
+	}
+	return record, nil
+}
+
+type ndjsonReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+func (n *ndjsonReader) Next() (Record, error) {
+	for n.scanner.Scan() {
+		n.line++
+		b := n.scanner.Bytes()
+		if len(strings.TrimSpace(string(b))) == 0 {
+			continue
+		}
+		record := Record{Line: n.line}
+		record.Err = json.Unmarshal(b, &record.Cat)
+		return record, nil
+	}
+	if err := n.scanner.Err(); err != nil {
+		return Record{}, err
+	}
+	return Record{}, io.EOF
+}
+
+type jsonReader struct {
+	decoder *json.Decoder
+	index   int
+}
+
+func newJSONReader(r io.Reader) (*jsonReader, error) {
+	decoder := json.NewDecoder(r)
+	if t, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("reading json: %v", err)
+	} else if d, ok := t.(json.Delim); !ok || d != '[' {
+		return nil, fmt.Errorf("reading json: expected an array of cats")
+	}
+	return &jsonReader{decoder: decoder}, nil
+}
+
+func (j *jsonReader) Next() (Record, error) {
+	if !j.decoder.More() {
+		if _, err := j.decoder.Token(); err != nil {
+			return Record{}, fmt.Errorf("reading json: %v", err)
+		}
+		return Record{}, io.EOF
+	}
+	j.index++
+	record := Record{Line: j.index}
+	if err := j.decoder.Decode(&record.Cat); err != nil {
+		// type errors leave the decoder positioned after the value, anything else is fatal
+		if _, ok := err.(*json.UnmarshalTypeError); !ok {
+			return Record{}, fmt.Errorf("reading json: %v", err)
+		}
+		record.Err = err
+	}
+	return record, nil
+}
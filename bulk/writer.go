@@ -0,0 +1,119 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	json "github.com/json-iterator/go"
+	"github.com/waikco/cats-v1/model"
+)
+
+// Writer writes cats one at a time, so any number of cats can be written
+// without holding them in memory.
+type Writer interface {
+	Write(model.Cat) error
+	// Close completes the output, such as closing a json array. It does not close the
+	// underlying writer.
+	Close() error
+}
+
+// NewWriter returns a Writer producing output in the given format.
+func NewWriter(w io.Writer, format Format) (Writer, error) {
+	switch format {
+	case CSV:
+		return &csvWriter{writer: csv.NewWriter(w)}, nil
+	case JSON:
+		return &jsonWriter{w: w}, nil
+	case NDJSON:
+		return &ndjsonWriter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unsupported format %q", format)
+	}
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	headerWritten bool
+}
+
+func (c *csvWriter) Write(cat model.Cat) error {
+	if !c.headerWritten {
+		if err := c.writer.Write(Columns); err != nil {
+			return err
+		}
+		c.headerWritten = true
+	}
+	var neutered, weight string
+	if cat.Neutered != nil {
+		neutered = strconv.FormatBool(*cat.Neutered)
+	}
+	if cat.WeightKg != 0 {
+		weight = strconv.FormatFloat(cat.WeightKg, 'f', -1, 64)
+	}
+	row := []string{cat.ID, cat.Name, cat.Color, strconv.Itoa(cat.Age), cat.Breed, cat.BirthDate, cat.Sex,
+		neutered, weight, cat.Microchip, strings.Join(cat.Tags, TagSeparator)}
+	if err := c.writer.Write(row); err != nil {
+		return err
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+func (c *csvWriter) Close() error {
+	if !c.headerWritten {
+		if err := c.writer.Write(Columns); err != nil {
+			return err
+		}
+		c.headerWritten = true
+	}
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type ndjsonWriter struct {
+	w io.Writer
+}
+
+func (n *ndjsonWriter) Write(cat model.Cat) error {
+	b, err := json.Marshal(cat)
+	if err != nil {
+		return err
+	}
+	_, err = n.w.Write(append(b, '\n'))
+	return err
+}
+
+func (n *ndjsonWriter) Close() error {
+	return nil
+}
+
+type jsonWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (j *jsonWriter) Write(cat model.Cat) error {
+	b, err := json.Marshal(cat)
+	if err != nil {
+		return err
+	}
+	prefix := []byte(",\n")
+	if !j.written {
+		prefix = []byte("[\n")
+		j.written = true
+	}
+	_, err = j.w.Write(append(prefix, b...))
+	return err
+}
+
+func (j *jsonWriter) Close() error {
+	if !j.written {
+		_, err := j.w.Write([]byte("[]\n"))
+		return err
+	}
+	_, err := j.w.Write([]byte("\n]\n"))
+	return err
+}
@@ -0,0 +1,352 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	json "github.com/json-iterator/go"
+	uuid "github.com/satori/go.uuid"
+	"github.com/waikco/cats-v1/buildinfo"
+	"github.com/waikco/cats-v1/healthcheck"
+	"github.com/waikco/cats-v1/model"
+)
+
+const (
+	DefaultMaxRetries = 3
+	DefaultMinBackoff = 100 * time.Millisecond
+	DefaultMaxBackoff = 2 * time.Second
+	DefaultTimeout    = 30 * time.Second
+
+	basePath = "/cats/v1"
+
+	// IdempotencyKeyHeader makes the server create a cat only once however often the
+	// request creating it is sent.
+	IdempotencyKeyHeader = "Idempotency-Key"
+)
+
+// Client is a client for the cats api.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	userAgent  string
+
+	token              string
+	username, password string
+
+	maxRetries int
+	minBackoff time.Duration
+	maxBackoff time.Duration
+}
+
+// Option configures a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the http client used to make requests.
+func WithHTTPClient(h *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = h
+	}
+}
+
+// WithToken authenticates every request with the given bearer token.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithBasicAuth authenticates every request with the given username and password.
+func WithBasicAuth(username, password string) Option {
+	return func(c *Client) {
+		c.username, c.password = username, password
+	}
+}
+
+// WithRetries sets how often an idempotent request failing with a 5xx or 429 response is
+// retried, and the bounds of the exponential backoff between attempts.
+func WithRetries(maxRetries int, minBackoff, maxBackoff time.Duration) Option {
+	return func(c *Client) {
+		c.maxRetries = maxRetries
+		c.minBackoff = minBackoff
+		c.maxBackoff = maxBackoff
+	}
+}
+
+// WithUserAgent overrides the User-Agent header sent with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// New creates a client for the cats api served at baseURL, such as http://localhost:8080.
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid base url %q: %v", baseURL, err)
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("invalid base url %q: scheme and host are required", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: &http.Client{Timeout: DefaultTimeout},
+		userAgent:  "cats-v1-client/" + buildinfo.Get().Version,
+		maxRetries: DefaultMaxRetries,
+		minBackoff: DefaultMinBackoff,
+		maxBackoff: DefaultMaxBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// GetCat retrieves a single cat.
+func (c *Client) GetCat(ctx context.Context, id string) (*model.Cat, error) {
+	var cat model.Cat
+	if err := c.do(ctx, http.MethodGet, "/cats/"+url.PathEscape(id), nil, nil, nil, &cat); err != nil {
+		return nil, err
+	}
+	return &cat, nil
+}
+
+// ListOptions selects a page of cats, optionally filtered.
+type ListOptions struct {
+	// Start is the offset of the first cat.
+	Start int
+	// Count is the number of cats per page, the server returns at most 10.
+	Count int
+	// Filter selects the cats listed, the server applying it before paging.
+	Filter model.Filter
+}
+
+func (o ListOptions) values() url.Values {
+	v := url.Values{}
+	if o.Start > 0 {
+		v.Set("start", strconv.Itoa(o.Start))
+	}
+	if o.Count > 0 {
+		v.Set("count", strconv.Itoa(o.Count))
+	}
+	f := o.Filter
+	for param, value := range map[string]string{
+		"name": f.Name, "color": f.Color, "breed": f.Breed, "sex": f.Sex, "microchip": f.Microchip, "status": f.Status,
+	} {
+		if value != "" {
+			v.Set(param, value)
+		}
+	}
+	if f.MinAge != nil {
+		v.Set("minAge", strconv.Itoa(*f.MinAge))
+	}
+	if f.MaxAge != nil {
+		v.Set("maxAge", strconv.Itoa(*f.MaxAge))
+	}
+	if f.Neutered != nil {
+		v.Set("neutered", strconv.FormatBool(*f.Neutered))
+	}
+	if len(f.Tags) > 0 {
+		v.Set("tags", strings.Join(f.Tags, ","))
+	}
+	return v
+}
+
+// ListCats retrieves a single page of cats.
+func (c *Client) ListCats(ctx context.Context, opts ListOptions) ([]model.Cat, error) {
+	cats := []model.Cat{}
+	if err := c.do(ctx, http.MethodGet, "/cats", opts.values(), nil, nil, &cats); err != nil {
+		return nil, err
+	}
+	return cats, nil
+}
+
+// CreateCat creates a cat, returning its id. The request carries an idempotency key, so
+// is retried like any idempotent request without risking creating the cat twice.
+func (c *Client) CreateCat(ctx context.Context, cat model.Cat) (string, error) {
+	var result struct {
+		Result string `json:"result"`
+	}
+	header := http.Header{}
+	header.Set(IdempotencyKeyHeader, uuid.NewV4().String())
+	if err := c.do(ctx, http.MethodPost, "/", nil, header, cat, &result); err != nil {
+		return "", err
+	}
+	return result.Result, nil
+}
+
+// CreateCats creates several cats in a single request, returning their ids in the order
+// given. Like CreateCat, the request carries an idempotency key.
+func (c *Client) CreateCats(ctx context.Context, cats []model.Cat) ([]string, error) {
+	var result struct {
+		Result []string `json:"result"`
+	}
+	header := http.Header{}
+	header.Set(IdempotencyKeyHeader, uuid.NewV4().String())
+	if err := c.do(ctx, http.MethodPost, "/bulkcatadd", nil, header, cats, &result); err != nil {
+		return nil, err
+	}
+	return result.Result, nil
+}
+
+// UpdateCat replaces the cat with the given id.
+func (c *Client) UpdateCat(ctx context.Context, id string, cat model.Cat) error {
+	return c.do(ctx, http.MethodPut, "/"+url.PathEscape(id), nil, nil, cat, nil)
+}
+
+// DeleteCat deletes the cat with the given id.
+func (c *Client) DeleteCat(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, "/cats/"+url.PathEscape(id), nil, nil, nil, nil)
+}
+
+// Ready runs the server's readiness checks. The report is returned alongside
+// an *Error when the server reports itself as unavailable.
+func (c *Client) Ready(ctx context.Context) (*healthcheck.Report, error) {
+	var report healthcheck.Report
+	err := c.do(ctx, http.MethodGet, "/ready", nil, nil, nil, &report)
+	if apiErr, ok := err.(*Error); ok && apiErr.StatusCode == http.StatusServiceUnavailable {
+		_ = json.Unmarshal(apiErr.Body, &report)
+		return &report, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// Version retrieves the build information of the server.
+func (c *Client) Version(ctx context.Context) (*buildinfo.Info, error) {
+	var info buildinfo.Info
+	if err := c.do(ctx, http.MethodGet, "/version", nil, nil, nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// do sends a request to the api along with header, retrying idempotent requests on 5xx and
+// 429 responses, and decodes a successful response into out. Requests carrying an
+// idempotency key are idempotent whatever their method.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, header http.Header, in, out interface{}) error {
+	var body []byte
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %v", err)
+		}
+		body = b
+	}
+
+	u := *c.baseURL
+	u.Path = u.Path + basePath + path
+	u.RawQuery = query.Encode()
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req = req.WithContext(ctx)
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		c.setHeaders(req, body != nil)
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return err
+		}
+		respBody, err := ioutil.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("reading response body: %v", err)
+		}
+
+		if resp.StatusCode < 300 {
+			if out == nil || len(respBody) == 0 {
+				return nil
+			}
+			if err := json.Unmarshal(respBody, out); err != nil {
+				return fmt.Errorf("decoding response body: %v", err)
+			}
+			return nil
+		}
+
+		apiErr := newError(resp, respBody)
+		if !idempotent(method, header) || !retryable(resp.StatusCode) || attempt >= c.maxRetries {
+			return apiErr
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.backoff(attempt, resp)):
+		}
+	}
+}
+
+func (c *Client) setHeaders(req *http.Request, hasBody bool) {
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", c.userAgent)
+	if hasBody {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case c.token != "":
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	case c.username != "":
+		req.SetBasicAuth(c.username, c.password)
+	}
+}
+
+// idempotent reports whether a request is safe to retry, as sending it again has no other
+// effect than sending it once. A create failing with a 5xx may well have been committed, so
+// retrying it could create the cat twice unless it carries an idempotency key.
+func idempotent(method string, header http.Header) bool {
+	if header.Get(IdempotencyKeyHeader) != "" {
+		return true
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// backoff returns how long to wait before the next attempt, honouring any Retry-After
+// header and otherwise backing off exponentially with jitter.
+func (c *Client) backoff(attempt int, resp *http.Response) time.Duration {
+	if s := resp.Header.Get("Retry-After"); s != "" {
+		if seconds, err := strconv.Atoi(s); err == nil && seconds >= 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		if t, err := http.ParseTime(s); err == nil {
+			if d := time.Until(t); d > 0 {
+				return d
+			}
+			return 0
+		}
+	}
+
+	d := c.minBackoff << uint(attempt)
+	if d <= 0 || d > c.maxBackoff {
+		d = c.maxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	// full jitter between half and all of the backoff
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
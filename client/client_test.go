@@ -0,0 +1,325 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/waikco/cats-v1/model"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	c, err := New(server.URL, WithRetries(2, time.Millisecond, 5*time.Millisecond))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return c
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		statuses []int
+		// then
+		expectedAttempts int
+		expectedStatus   int
+	}{
+		{
+			description:      "succeeds after server errors",
+			statuses:         []int{http.StatusInternalServerError, http.StatusServiceUnavailable, http.StatusOK},
+			expectedAttempts: 3,
+		},
+		{
+			description:      "retries when rate limited",
+			statuses:         []int{http.StatusTooManyRequests, http.StatusOK},
+			expectedAttempts: 2,
+		},
+		{
+			description:      "gives up after max retries",
+			statuses:         []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway, http.StatusOK},
+			expectedAttempts: 3,
+			expectedStatus:   http.StatusBadGateway,
+		},
+		{
+			description:      "does not retry client errors",
+			statuses:         []int{http.StatusNotFound, http.StatusOK},
+			expectedAttempts: 1,
+			expectedStatus:   http.StatusNotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			attempts := 0
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[attempts]
+				attempts++
+				if status == http.StatusTooManyRequests {
+					w.Header().Set("Retry-After", "0")
+				}
+				w.WriteHeader(status)
+				_, _ = w.Write([]byte(`{"name":"cat-1","color":"color-1","age":1}`))
+			})
+
+			_, err := c.GetCat(context.Background(), "fe271e7e-83ca-477b-92fc-d0c3fa602d7d")
+
+			if attempts != tt.expectedAttempts {
+				t.Errorf("unexpected attempts: got %d, expected %d", attempts, tt.expectedAttempts)
+			}
+			if StatusCode(err) != tt.expectedStatus {
+				t.Errorf("unexpected error: got %v, expected status %d", err, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestClient_RetriesCreatesWithTheSameKey(t *testing.T) {
+	var keys []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"result":"fe271e7e-83ca-477b-92fc-d0c3fa602d7d"}`))
+	})
+
+	if _, err := c.CreateCat(context.Background(), model.Cat{Name: "cat-1", Color: "color-1", Age: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(keys) != 2 || keys[0] == "" || keys[0] != keys[1] {
+		t.Errorf("unexpected idempotency keys: got %q, expected the same key on both attempts", keys)
+	}
+
+	first := keys[0]
+	keys = nil
+	if _, err := c.CreateCat(context.Background(), model.Cat{Name: "cat-2", Color: "color-2", Age: 2}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if keys[0] == first {
+		t.Errorf("unexpected idempotency key: got %q again for another cat", first)
+	}
+}
+
+func TestIdempotent(t *testing.T) {
+	keyed := http.Header{}
+	keyed.Set(IdempotencyKeyHeader, "key-1")
+	tests := []struct {
+		method   string
+		header   http.Header
+		expected bool
+	}{
+		{method: http.MethodGet, expected: true},
+		{method: http.MethodPut, expected: true},
+		{method: http.MethodDelete, expected: true},
+		{method: http.MethodPost, expected: false},
+		{method: http.MethodPost, header: keyed, expected: true},
+	}
+	for _, tt := range tests {
+		if got := idempotent(tt.method, tt.header); got != tt.expected {
+			t.Errorf("unexpected idempotent(%s, %v): got %t, expected %t", tt.method, tt.header, got, tt.expected)
+		}
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		status int
+		body   string
+		// then
+		expectedError *Error
+	}{
+		{
+			description: "error envelope",
+			status:      http.StatusBadRequest,
+			body:        `{"error":{"status":400,"message":"request does not match api specification","details":[{"location":"body","field":"age","reason":"value must be an integer"}]}}`,
+			expectedError: &Error{
+				StatusCode: http.StatusBadRequest,
+				Message:    "request does not match api specification",
+				Details:    []ErrorDetail{{Location: "body", Field: "age", Reason: "value must be an integer"}},
+			},
+		},
+		{
+			description: "error reported through result",
+			status:      http.StatusNotFound,
+			body:        `{"result":"cat not found","error":{}}`,
+			expectedError: &Error{
+				StatusCode: http.StatusNotFound,
+				Message:    "cat not found",
+			},
+		},
+		{
+			description: "body that is not an envelope",
+			status:      http.StatusBadRequest,
+			body:        `bad request`,
+			expectedError: &Error{
+				StatusCode: http.StatusBadRequest,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			err := c.DeleteCat(context.Background(), "fe271e7e-83ca-477b-92fc-d0c3fa602d7d")
+
+			got, ok := err.(*Error)
+			if !ok {
+				t.Fatalf("unexpected error type: got %T, expected *Error", err)
+			}
+			got.Body = nil
+			if !reflect.DeepEqual(got, tt.expectedError) {
+				t.Errorf("unexpected error: got %+v, expected %+v", got, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestClient_Requests(t *testing.T) {
+	var gotMethod, gotPath, gotBody, gotAuth string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotAuth = r.Method, r.URL.Path, r.Header.Get("Authorization")
+		b, _ := ioutil.ReadAll(r.Body)
+		gotBody = string(b)
+		_, _ = w.Write([]byte(`{"result":"fe271e7e-83ca-477b-92fc-d0c3fa602d7d"}`))
+	})
+	WithToken("secret")(c)
+	ctx := context.Background()
+	cat := model.Cat{Name: "cat-1", Color: "color-1", Age: 1}
+
+	tests := []struct {
+		description string
+		// given
+		call func() error
+		// then
+		expectedMethod string
+		expectedPath   string
+		expectedBody   string
+	}{
+		{
+			description:    "create",
+			call:           func() error { _, err := c.CreateCat(ctx, cat); return err },
+			expectedMethod: http.MethodPost,
+			expectedPath:   "/cats/v1/",
+			expectedBody:   `{"name":"cat-1","color":"color-1","age":1}`,
+		},
+		{
+			description:    "update",
+			call:           func() error { return c.UpdateCat(ctx, "1", cat) },
+			expectedMethod: http.MethodPut,
+			expectedPath:   "/cats/v1/1",
+			expectedBody:   `{"name":"cat-1","color":"color-1","age":1}`,
+		},
+		{
+			description:    "delete",
+			call:           func() error { return c.DeleteCat(ctx, "1") },
+			expectedMethod: http.MethodDelete,
+			expectedPath:   "/cats/v1/cats/1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if err := tt.call(); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if gotMethod != tt.expectedMethod || gotPath != tt.expectedPath {
+				t.Errorf("unexpected request: got %s %s, expected %s %s", gotMethod, gotPath, tt.expectedMethod, tt.expectedPath)
+			}
+			if gotBody != tt.expectedBody {
+				t.Errorf("unexpected body: got %s, expected %s", gotBody, tt.expectedBody)
+			}
+			if gotAuth != "Bearer secret" {
+				t.Errorf("unexpected authorization: got %s, expected Bearer secret", gotAuth)
+			}
+		})
+	}
+}
+
+func TestClient_CreateCats(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		if r.Method != http.MethodPost || r.URL.Path != "/cats/v1/bulkcatadd" || r.Header.Get(IdempotencyKeyHeader) == "" ||
+			string(b) != `[{"name":"cat-1","color":"black","age":1},{"name":"cat-2","color":"white","age":2}]` {
+			t.Errorf("unexpected request: %s %s %v %s", r.Method, r.URL.Path, r.Header, b)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"result":["1","2"]}`))
+	})
+
+	ids, err := c.CreateCats(context.Background(), []model.Cat{
+		{Name: "cat-1", Color: "black", Age: 1},
+		{Name: "cat-2", Color: "white", Age: 2},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(ids, []string{"1", "2"}) {
+		t.Errorf("unexpected ids: %v", ids)
+	}
+}
+
+func TestListOptions_Values(t *testing.T) {
+	minAge, neutered := 2, true
+	opts := ListOptions{Start: 10, Count: 5, Filter: model.Filter{
+		Name: "tom", Color: "ginger", Breed: "siamese", Sex: "male", Microchip: "123456789", Status: model.StatusAvailable,
+		MinAge: &minAge, Neutered: &neutered, Tags: []string{"indoor", "shy"},
+	}}
+
+	expected := "breed=siamese&color=ginger&count=5&microchip=123456789&minAge=2&name=tom&neutered=true&" +
+		"sex=male&start=10&status=available&tags=indoor%2Cshy"
+	if got := opts.values().Encode(); got != expected {
+		t.Errorf("unexpected query: got %s, expected %s", got, expected)
+	}
+}
+
+func TestCatIterator(t *testing.T) {
+	var all []model.Cat
+	for i := 0; i < 23; i++ {
+		all = append(all, model.Cat{Name: fmt.Sprintf("cat-%d", i)})
+	}
+	requests := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		start, _ := strconv.Atoi(r.FormValue("start"))
+		count, _ := strconv.Atoi(r.FormValue("count"))
+		end := start + count
+		if end > len(all) {
+			end = len(all)
+		}
+		b, _ := json.Marshal(all[start:end])
+		_, _ = w.Write(b)
+	})
+
+	var got []model.Cat
+	it := c.Cats(context.Background(), ListOptions{})
+	for it.Next() {
+		got = append(got, it.Cat())
+	}
+
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, all) {
+		t.Errorf("unexpected cats: got %d cats, expected %d", len(got), len(all))
+	}
+	if requests != 3 {
+		t.Errorf("unexpected requests: got %d, expected 3", requests)
+	}
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+
+	json "github.com/json-iterator/go"
+)
+
+// Error is returned for any non 2xx response, decoded from the server's error envelope.
+type Error struct {
+	StatusCode int
+	Message    string
+	Details    []ErrorDetail
+	// Body is the raw response body.
+	Body []byte
+}
+
+// ErrorDetail describes a single problem with a request, such as a field failing validation.
+type ErrorDetail struct {
+	Location string `json:"location,omitempty"`
+	Field    string `json:"field,omitempty"`
+	Reason   string `json:"reason"`
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("cats api responded %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+	}
+	return fmt.Sprintf("cats api responded %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// envelope mirrors the Response written by the server.
+type envelope struct {
+	Result interface{} `json:"result"`
+	Error  struct {
+		Status  int           `json:"status"`
+		Message interface{}   `json:"message"`
+		Details []ErrorDetail `json:"details"`
+	} `json:"error"`
+}
+
+func newError(resp *http.Response, body []byte) *Error {
+	e := &Error{
+		StatusCode: resp.StatusCode,
+		Body:       body,
+	}
+
+	var env envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return e
+	}
+	e.Details = env.Error.Details
+	switch {
+	case env.Error.Message != nil:
+		e.Message = fmt.Sprint(env.Error.Message)
+	case env.Result != nil:
+		// some endpoints report errors through the result
+		e.Message = fmt.Sprint(env.Result)
+	}
+	return e
+}
+
+// StatusCode returns the http status code of an *Error, or 0 for any other error.
+func StatusCode(err error) int {
+	if e, ok := err.(*Error); ok {
+		return e.StatusCode
+	}
+	return 0
+}
+
+// IsNotFound reports whether err is a 404 response.
+func IsNotFound(err error) bool {
+	return StatusCode(err) == http.StatusNotFound
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+
+	"github.com/waikco/cats-v1/model"
+)
+
+const maxPageSize = 10
+
+// CatIterator pages through every cat, fetching a page at a time.
+//
+//	it := c.Cats(ctx, client.ListOptions{})
+//	for it.Next() {
+//		cat := it.Cat()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type CatIterator struct {
+	ctx    context.Context
+	client *Client
+	opts   ListOptions
+
+	page []model.Cat
+	cat  model.Cat
+	done bool
+	err  error
+}
+
+// Cats returns an iterator over every cat starting at opts.Start, fetching opts.Count cats per page.
+func (c *Client) Cats(ctx context.Context, opts ListOptions) *CatIterator {
+	if opts.Count < 1 || opts.Count > maxPageSize {
+		opts.Count = maxPageSize
+	}
+	return &CatIterator{ctx: ctx, client: c, opts: opts}
+}
+
+// Next advances to the next cat, fetching the next page when needed. It returns false
+// when there are no more cats or an error occurred.
+func (it *CatIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if len(it.page) == 0 {
+		if it.done {
+			return false
+		}
+		page, err := it.client.ListCats(it.ctx, it.opts)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		// a short page is the last one
+		it.done = len(page) < it.opts.Count
+		it.opts.Start += len(page)
+		it.page = page
+		if len(page) == 0 {
+			return false
+		}
+	}
+	it.cat, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Cat returns the current cat.
+func (it *CatIterator) Cat() model.Cat {
+	return it.cat
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *CatIterator) Err() error {
+	return it.err
+}
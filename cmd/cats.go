@@ -0,0 +1,389 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+	"github.com/waikco/cats-v1/client"
+	"github.com/waikco/cats-v1/model"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	outputTable = "table"
+	outputJson  = "json"
+	outputYaml  = "yaml"
+)
+
+// catsCmd groups the subcommands managing cats through a running server
+var catsCmd = &cobra.Command{
+	Use:   "cats",
+	Short: "Manage cats through a running cats-v1 server",
+	Long: `Manage cats through a running cats-v1 server, reached through client.url.
+
+Exit codes: 0 on success, 3 when a cat is not found, 4 for any other 4xx
+response, 5 for 5xx responses and 1 for any other error.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		switch o, _ := cmd.Flags().GetString("output"); o {
+		case outputTable, outputJson, outputYaml:
+			return nil
+		default:
+			return fmt.Errorf("unsupported output format %q, use one of table, json or yaml", o)
+		}
+	},
+}
+
+var catsListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cats",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		flags := cmd.Flags()
+		start, _ := flags.GetInt("start")
+		count, _ := flags.GetInt("count")
+		all, _ := flags.GetBool("all")
+		filter, err := listFilter(flags)
+		if err != nil {
+			return err
+		}
+		opts := client.ListOptions{Start: start, Count: count, Filter: filter}
+
+		if !all {
+			cats, err := c.ListCats(ctx, opts)
+			if err != nil {
+				return err
+			}
+			return printCats(cmd, cats, false)
+		}
+
+		cats := []model.Cat{}
+		it := c.Cats(ctx, opts)
+		for it.Next() {
+			cats = append(cats, it.Cat())
+		}
+		if err := it.Err(); err != nil {
+			return err
+		}
+		return printCats(cmd, cats, false)
+	},
+}
+
+// listFilter reads the filter of the cats listed from the flags, which the server applies
+// before paging.
+func listFilter(flags *pflag.FlagSet) (model.Filter, error) {
+	var filter model.Filter
+	filter.Name, _ = flags.GetString("name")
+	filter.Color, _ = flags.GetString("color")
+	filter.Breed, _ = flags.GetString("breed")
+	filter.Sex, _ = flags.GetString("sex")
+	filter.Microchip, _ = flags.GetString("microchip")
+	filter.Status, _ = flags.GetString("status")
+	filter.Tags, _ = flags.GetStringSlice("tags")
+	if filter.Status != "" && !model.ValidStatus(filter.Status) {
+		return filter, fmt.Errorf("unsupported status %q, use one of %s", filter.Status, strings.Join(model.Statuses, ", "))
+	}
+	if flags.Changed("min-age") {
+		age, _ := flags.GetInt("min-age")
+		filter.MinAge = &age
+	}
+	if flags.Changed("max-age") {
+		age, _ := flags.GetInt("max-age")
+		filter.MaxAge = &age
+	}
+	if flags.Changed("neutered") {
+		neutered, _ := flags.GetBool("neutered")
+		filter.Neutered = &neutered
+	}
+	return filter, nil
+}
+
+var catsGetCmd = &cobra.Command{
+	Use:   "get <id>",
+	Short: "Get a cat",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		cat, err := c.GetCat(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		if cat.ID == "" {
+			cat.ID = args[0]
+		}
+		return printCats(cmd, []model.Cat{*cat}, true)
+	},
+}
+
+var catsCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a cat from flags or a json file",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cat, err := catFromFlags(cmd, model.Cat{})
+		if err != nil {
+			return err
+		}
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		id, err := c.CreateCat(ctx, cat)
+		if err != nil {
+			return err
+		}
+		cat.ID = id
+		return printCats(cmd, []model.Cat{cat}, true)
+	},
+}
+
+var catsUpdateCmd = &cobra.Command{
+	Use:   "update <id>",
+	Short: "Update a cat from flags or a json file",
+	Long:  "Update a cat. Flags are applied on top of the current cat, so only changed fields need to be given.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		current, err := c.GetCat(ctx, args[0])
+		if err != nil {
+			return err
+		}
+		cat, err := catFromFlags(cmd, *current)
+		if err != nil {
+			return err
+		}
+		if err := c.UpdateCat(ctx, args[0], writable(cat)); err != nil {
+			return err
+		}
+		cat.ID = args[0]
+		return printCats(cmd, []model.Cat{cat}, true)
+	},
+}
+
+// writable returns a cat without its read only fields, which are changed through other
+// resources and rejected in the bodies of updates.
+func writable(cat model.Cat) model.Cat {
+	cat.ID, cat.OwnerID, cat.Status, cat.Photos = "", "", "", nil
+	return cat
+}
+
+var catsDeleteCmd = &cobra.Command{
+	Use:   "delete <id>...",
+	Short: "Delete one or more cats",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newClient()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := commandContext(cmd)
+		defer cancel()
+
+		for _, id := range args {
+			if err := c.DeleteCat(ctx, id); err != nil {
+				return fmt.Errorf("deleting cat %s: %w", id, err)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "deleted %s\n", id)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(catsCmd)
+	catsCmd.AddCommand(catsListCmd, catsGetCmd, catsCreateCmd, catsUpdateCmd, catsDeleteCmd)
+	for _, c := range catsCmd.Commands() {
+		// api errors are reported by Execute, and are not usage errors
+		c.SilenceUsage = true
+		c.SilenceErrors = true
+	}
+
+	pf := catsCmd.PersistentFlags()
+	pf.String("url", "", "base url of the cats-v1 server (default client.url from config)")
+	pf.String("token", "", "bearer token used to authenticate (default client.token from config)")
+	pf.String("username", "", "username used to authenticate (default client.username from config)")
+	pf.String("password", "", "password used to authenticate (default client.password from config)")
+	pf.StringP("output", "o", outputTable, "output format, one of table, json or yaml")
+	pf.Duration("timeout", 30*time.Second, "timeout for the whole command")
+	_ = viper.BindPFlag("client.url", pf.Lookup("url"))
+	_ = viper.BindPFlag("client.token", pf.Lookup("token"))
+	_ = viper.BindPFlag("client.username", pf.Lookup("username"))
+	_ = viper.BindPFlag("client.password", pf.Lookup("password"))
+
+	lf := catsListCmd.Flags()
+	lf.Int("start", 0, "offset of the first cat")
+	lf.Int("count", 10, "number of cats to list, at most 10 per page")
+	lf.Bool("all", false, "list every cat, fetching all pages")
+	lf.String("name", "", "only list cats with this name")
+	lf.String("color", "", "only list cats with this color")
+	lf.String("breed", "", "only list cats of this breed")
+	lf.String("sex", "", "only list cats of this sex, female or male")
+	lf.String("microchip", "", "only list the cat with this microchip number")
+	lf.String("status", "", "only list cats at this adoption status")
+	lf.Int("min-age", 0, "only list cats at least this old")
+	lf.Int("max-age", 0, "only list cats at most this old")
+	lf.Bool("neutered", false, "only list cats that are neutered, or with --neutered=false that are not")
+	lf.StringSlice("tags", nil, "only list cats with every one of these comma separated tags")
+
+	for _, c := range []*cobra.Command{catsCreateCmd, catsUpdateCmd} {
+		f := c.Flags()
+		f.StringP("file", "f", "", "json file holding the cat, - reads from stdin")
+		f.String("name", "", "name of the cat")
+		f.String("color", "", "color of the cat")
+		f.Int("age", 0, "age of the cat")
+		f.String("breed", "", "breed of the cat")
+		f.String("birth-date", "", "birth date of the cat, as YYYY-MM-DD")
+		f.String("sex", "", "sex of the cat, female or male")
+		f.Bool("neutered", false, "whether the cat is neutered or spayed")
+		f.Float64("weight", 0, "weight of the cat in kg")
+		f.String("microchip", "", "microchip number of the cat")
+		f.StringSlice("tags", nil, "comma separated tags of the cat")
+	}
+}
+
+// newClient creates an api client from the client config.
+func newClient() (*client.Client, error) {
+	url := viper.GetString("client.url")
+	if url == "" {
+		return nil, fmt.Errorf("no server url configured, set client.url or pass --url")
+	}
+
+	var opts []client.Option
+	if token := viper.GetString("client.token"); token != "" {
+		opts = append(opts, client.WithToken(token))
+	} else if username := viper.GetString("client.username"); username != "" {
+		opts = append(opts, client.WithBasicAuth(username, viper.GetString("client.password")))
+	}
+	return client.New(url, opts...)
+}
+
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	if timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+// catFromFlags reads a cat from --file, if given, over base and then applies any field flags set.
+func catFromFlags(cmd *cobra.Command, base model.Cat) (model.Cat, error) {
+	cat := base
+	flags := cmd.Flags()
+
+	if file, _ := flags.GetString("file"); file != "" {
+		var r io.Reader = cmd.InOrStdin()
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return cat, err
+			}
+			defer func() { _ = f.Close() }()
+			r = f
+		}
+		b, err := ioutil.ReadAll(r)
+		if err != nil {
+			return cat, err
+		}
+		if err := json.Unmarshal(b, &cat); err != nil {
+			return cat, fmt.Errorf("invalid cat in %s: %v", file, err)
+		}
+	}
+
+	if flags.Changed("name") {
+		cat.Name, _ = flags.GetString("name")
+	}
+	if flags.Changed("color") {
+		cat.Color, _ = flags.GetString("color")
+	}
+	if flags.Changed("age") {
+		cat.Age, _ = flags.GetInt("age")
+	}
+	if flags.Changed("breed") {
+		cat.Breed, _ = flags.GetString("breed")
+	}
+	if flags.Changed("birth-date") {
+		cat.BirthDate, _ = flags.GetString("birth-date")
+	}
+	if flags.Changed("sex") {
+		cat.Sex, _ = flags.GetString("sex")
+	}
+	if flags.Changed("neutered") {
+		neutered, _ := flags.GetBool("neutered")
+		cat.Neutered = &neutered
+	}
+	if flags.Changed("weight") {
+		cat.WeightKg, _ = flags.GetFloat64("weight")
+	}
+	if flags.Changed("microchip") {
+		cat.Microchip, _ = flags.GetString("microchip")
+	}
+	if flags.Changed("tags") {
+		cat.Tags, _ = flags.GetStringSlice("tags")
+	}
+	return cat, nil
+}
+
+// printCats writes cats in the output format selected by --output. A single cat is
+// written as an object rather than a list in the json and yaml formats.
+func printCats(cmd *cobra.Command, cats []model.Cat, single bool) error {
+	w := cmd.OutOrStdout()
+	var v interface{} = cats
+	if single && len(cats) == 1 {
+		v = cats[0]
+	}
+
+	switch o, _ := cmd.Flags().GetString("output"); o {
+	case outputJson:
+		b, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case outputYaml:
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	default:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "ID\tNAME\tCOLOR\tAGE")
+		for _, c := range cats {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d\n", c.ID, c.Name, c.Color, c.Age)
+		}
+		return tw.Flush()
+	}
+}
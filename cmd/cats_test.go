@@ -0,0 +1,162 @@
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	json "github.com/json-iterator/go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// resetFlags restores the flags of cmd and its subcommands, which outlive a single execution.
+// Array and slice flags append to their value once set, so they are emptied instead.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		switch f.Value.Type() {
+		case "stringArray":
+			// the only array flag is --set, emptied along with the overrides below
+		case "stringSlice":
+			fresh := pflag.NewFlagSet(f.Name, pflag.ContinueOnError)
+			fresh.StringSlice(f.Name, nil, f.Usage)
+			f.Value = fresh.Lookup(f.Name).Value
+		default:
+			_ = f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	overrides = nil
+	cmd.PersistentFlags().VisitAll(reset)
+	cmd.Flags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
+
+func TestCatsCommands(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cats/v1/cats/fe271e7e-83ca-477b-92fc-d0c3fa602d7d":
+			_, _ = w.Write([]byte(`{"name":"cat-1","color":"color-1","age":1}`))
+		case "/cats/v1/cats":
+			switch r.URL.RawQuery {
+			case "color=white&count=10":
+				_, _ = w.Write([]byte(`[{"id":"2","name":"cat-2","color":"white","age":2}]`))
+			case "breed=siamese&count=10&maxAge=3&neutered=false&status=available&tags=indoor%2Cshy":
+				_, _ = w.Write([]byte(`[]`))
+			default:
+				_, _ = w.Write([]byte(`[{"id":"1","name":"cat-1","color":"black","age":1},{"id":"2","name":"cat-2","color":"white","age":2}]`))
+			}
+		case "/cats/v1/":
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"status":400,"message":"request does not match api specification"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"result":"cat not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		description string
+		// given
+		args []string
+		// then
+		expectedOutput   string
+		expectedExitCode int
+	}{
+		{
+			description:      "get as json",
+			args:             []string{"cats", "get", "fe271e7e-83ca-477b-92fc-d0c3fa602d7d", "-o", "json"},
+			expectedOutput:   "{\n  \"id\": \"fe271e7e-83ca-477b-92fc-d0c3fa602d7d\",\n  \"name\": \"cat-1\",\n  \"color\": \"color-1\",\n  \"age\": 1\n}\n",
+			expectedExitCode: ExitOK,
+		},
+		{
+			description:      "list filtered as yaml",
+			args:             []string{"cats", "list", "--color", "white", "-o", "yaml"},
+			expectedOutput:   "- id: \"2\"\n  name: cat-2\n  color: white\n  age: 2\n",
+			expectedExitCode: ExitOK,
+		},
+		{
+			description: "list filtered by the server",
+			args: []string{"cats", "list", "--breed", "siamese", "--max-age", "3", "--neutered=false", "--status", "available",
+				"--tags", "indoor,shy", "-o", "json"},
+			expectedOutput:   "[]\n",
+			expectedExitCode: ExitOK,
+		},
+		{
+			description:      "list with an unsupported status",
+			args:             []string{"cats", "list", "--status", "lost"},
+			expectedExitCode: ExitError,
+		},
+		{
+			description:      "list as table",
+			args:             []string{"cats", "list", "-o", "table"},
+			expectedOutput:   "ID  NAME   COLOR  AGE\n1   cat-1  black  1\n2   cat-2  white  2\n",
+			expectedExitCode: ExitOK,
+		},
+		{
+			description:      "missing cat",
+			args:             []string{"cats", "get", "missing"},
+			expectedExitCode: ExitNotFound,
+		},
+		{
+			description:      "rejected cat",
+			args:             []string{"cats", "create", "--name", "cat-1"},
+			expectedExitCode: ExitClientError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			resetFlags(catsCmd)
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs(append(tt.args, "--url", server.URL))
+
+			err := rootCmd.Execute()
+
+			if code := exitCode(err); code != tt.expectedExitCode {
+				t.Errorf("unexpected exit code: got %d, expected %d (%v)", code, tt.expectedExitCode, err)
+			}
+			if tt.expectedOutput != "" && out.String() != tt.expectedOutput {
+				t.Errorf("unexpected output: got %q, expected %q", out.String(), tt.expectedOutput)
+			}
+		})
+	}
+}
+
+func TestCatsUpdate(t *testing.T) {
+	const id = "fe271e7e-83ca-477b-92fc-d0c3fa602d7d"
+	var updated map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/cats/v1/cats/"+id:
+			_, _ = w.Write([]byte(`{"id":"` + id + `","name":"cat-1","color":"black","age":1,"status":"adopted",` +
+				`"ownerId":"3d6f1c2a-8b4e-4f7a-9c1d-2e5b8a7f6c3d","photos":[{"id":"p1"}]}`))
+		case r.Method == http.MethodPut && r.URL.Path == "/cats/v1/"+id:
+			_ = json.NewDecoder(r.Body).Decode(&updated)
+			_, _ = w.Write([]byte(`{"result":"success"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	resetFlags(catsCmd)
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"cats", "update", id, "--age", "2", "--url", server.URL})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, field := range []string{"id", "status", "ownerId", "photos"} {
+		if _, ok := updated[field]; ok {
+			t.Errorf("unexpected read only field %s in update: %v", field, updated)
+		}
+	}
+	if updated["name"] != "cat-1" || updated["age"] != float64(2) {
+		t.Errorf("unexpected update: %v", updated)
+	}
+}
@@ -0,0 +1,187 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	json "github.com/json-iterator/go"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/waikco/cats-v1/conf"
+	"gopkg.in/yaml.v2"
+)
+
+// redacted replaces the values of secret settings when the config is shown.
+const redacted = "[redacted]"
+
+// configCmd groups the subcommands inspecting the config cats-v1 runs with
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the config cats-v1 runs with",
+	Long: `Inspect the config cats-v1 runs with, merged from the config file, CATS_ environment
+variables and --set overrides, each overriding the ones before.`,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the config, listing every invalid key",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		config, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		var invalid conf.ValidationError
+		if err := config.Validate(); errors.As(err, &invalid) {
+			for _, e := range invalid {
+				fmt.Fprintf(cmd.OutOrStdout(), "%s: %s\n", e.Key, e.Reason)
+			}
+			return fmt.Errorf("config is invalid, %d invalid keys", len(invalid))
+		} else if err != nil {
+			return err
+		}
+		fmt.Fprintln(cmd.OutOrStdout(), "config is valid")
+		return nil
+	},
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective config, along with where every key was read from",
+	Long: `Print the effective config, along with where every key was read from: --set, an
+environment variable, the config file, or unset when none set it. Secrets such as
+passwords are redacted.`,
+	Args: cobra.NoArgs,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		switch o, _ := cmd.Flags().GetString("output"); o {
+		case outputTable, outputJson, outputYaml:
+			return nil
+		default:
+			return fmt.Errorf("unsupported output format %q, use one of table, json or yaml", o)
+		}
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		settings, err := effectiveConfig()
+		if err != nil {
+			return err
+		}
+		return printSettings(cmd, settings)
+	},
+}
+
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print a JSON Schema of config files, for editors to complete and check them",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := conf.Schema()
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(cmd.OutOrStdout(), string(b))
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configValidateCmd, configShowCmd, configSchemaCmd)
+	for _, c := range configCmd.Commands() {
+		// invalid config is reported by Execute, and is not a usage error
+		c.SilenceUsage = true
+		c.SilenceErrors = true
+	}
+
+	configShowCmd.Flags().StringP("output", "o", outputTable, "output format, one of table, json or yaml")
+}
+
+// configSetting is a setting of the effective config along with where it was read from.
+type configSetting struct {
+	Key    string `json:"key" yaml:"key"`
+	Value  string `json:"value" yaml:"value"`
+	Source string `json:"source" yaml:"source"`
+}
+
+// effectiveConfig returns every setting of the config, secrets being redacted.
+func effectiveConfig() ([]configSetting, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	source := configSource()
+
+	var settings []configSetting
+	for _, s := range conf.Settings(config) {
+		value := fmt.Sprint(s.Value)
+		if values, ok := s.Value.([]string); ok {
+			value = strings.Join(values, ",")
+		}
+		if s.Secret && value != "" {
+			value = redacted
+		}
+		settings = append(settings, configSetting{Key: s.Key, Value: value, Source: source(s.Key)})
+	}
+	return settings, nil
+}
+
+// configSource returns a function telling where the value of a key was read from, in the
+// order viper looks them up: --set overrides, environment variables then the config file.
+func configSource() func(key string) string {
+	set := map[string]bool{}
+	for _, override := range overrides {
+		key, _, _ := strings.Cut(override, "=")
+		set[strings.ToLower(strings.TrimSpace(key))] = true
+	}
+	// the config file is read again, without environment variables and overrides, to tell
+	// the keys it sets
+	var file *viper.Viper
+	if used := viper.ConfigFileUsed(); used != "" {
+		file = viper.New()
+		file.SetConfigFile(used)
+		if err := file.ReadInConfig(); err != nil {
+			file = nil
+		}
+	}
+
+	return func(key string) string {
+		switch {
+		case set[strings.ToLower(key)]:
+			return "--set"
+		case os.Getenv(envName(key)) != "":
+			return "env " + envName(key)
+		case file != nil && file.IsSet(key):
+			return "file " + file.ConfigFileUsed()
+		}
+		return "unset"
+	}
+}
+
+func printSettings(cmd *cobra.Command, settings []configSetting) error {
+	w := cmd.OutOrStdout()
+	switch o, _ := cmd.Flags().GetString("output"); o {
+	case outputJson:
+		b, err := json.MarshalIndent(settings, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	case outputYaml:
+		b, err := yaml.Marshal(settings)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	default:
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "KEY\tVALUE\tSOURCE")
+		for _, s := range settings {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", s.Key, s.Value, s.Source)
+		}
+		return tw.Flush()
+	}
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	json "github.com/json-iterator/go"
+	"github.com/spf13/viper"
+)
+
+func TestConfigCommands(t *testing.T) {
+	t.Setenv("CATS_DATABASE_PASSWORD", "secret")
+
+	tests := []struct {
+		description string
+		// given
+		args []string
+		// then
+		expectedOutput   string
+		expectedSettings map[string]configSetting
+		expectedExitCode int
+	}{
+		{
+			description:      "valid config",
+			args:             []string{"config", "validate"},
+			expectedOutput:   "config is valid\n",
+			expectedExitCode: ExitOK,
+		},
+		{
+			description: "invalid config",
+			args:        []string{"config", "validate", "--set", "server.port=70000", "--set", "server.tls=true", "--set", "server.key="},
+			expectedOutput: "server.port: must be a port from 1 to 65535\n" +
+				"server.key: is required when server.tls is enabled\n",
+			expectedExitCode: ExitError,
+		},
+		{
+			description: "show with sources",
+			args:        []string{"config", "show", "-o", "json", "--set", "server.port=8443"},
+			expectedSettings: map[string]configSetting{
+				"environment":       {Key: "environment", Value: "test", Source: "file ../testing/config.yaml"},
+				"server.port":       {Key: "server.port", Value: "8443", Source: "--set"},
+				"server.key":        {Key: "server.key", Source: "unset"},
+				"database.password": {Key: "database.password", Value: redacted, Source: "env CATS_DATABASE_PASSWORD"},
+				"webhooks.maxBackoff": {Key: "webhooks.maxBackoff", Value: "1h0m0s",
+					Source: "file ../testing/config.yaml"},
+				"outbox.kafka.brokers": {Key: "outbox.kafka.brokers", Value: "127.0.0.1:9092",
+					Source: "file ../testing/config.yaml"},
+			},
+			expectedExitCode: ExitOK,
+		},
+		{
+			description:      "show in an unknown format",
+			args:             []string{"config", "show", "-o", "xml"},
+			expectedExitCode: ExitError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			resetFlags(rootCmd)
+			var out bytes.Buffer
+			rootCmd.SetOut(&out)
+			rootCmd.SetArgs(append(tt.args, "--config", "../testing/config.yaml"))
+
+			err := rootCmd.Execute()
+
+			if code := exitCode(err); code != tt.expectedExitCode {
+				t.Errorf("unexpected exit code: got %d, expected %d (%v)", code, tt.expectedExitCode, err)
+			}
+			if tt.expectedOutput != "" && out.String() != tt.expectedOutput {
+				t.Errorf("unexpected output: got %q, expected %q", out.String(), tt.expectedOutput)
+			}
+			if tt.expectedSettings == nil {
+				return
+			}
+			var settings []configSetting
+			if err := json.Unmarshal(out.Bytes(), &settings); err != nil {
+				t.Fatalf("unexpected output: %v: %s", err, out.String())
+			}
+			found := 0
+			for _, s := range settings {
+				if expected, ok := tt.expectedSettings[s.Key]; ok {
+					found++
+					if s != expected {
+						t.Errorf("unexpected setting: got %+v, expected %+v", s, expected)
+					}
+				}
+			}
+			if found != len(tt.expectedSettings) {
+				t.Errorf("unexpected settings: found %d of %d in %s", found, len(tt.expectedSettings), out.String())
+			}
+		})
+	}
+}
+
+func TestConfigSchema(t *testing.T) {
+	viper.Reset()
+	defer viper.Reset()
+	resetFlags(rootCmd)
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"config", "schema"})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var schema struct {
+		Properties map[string]struct {
+			Properties map[string]struct {
+				Type    string   `json:"type"`
+				Enum    []string `json:"enum"`
+				Pattern string   `json:"pattern"`
+			} `json:"properties"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &schema); err != nil {
+		t.Fatalf("unexpected schema: %v: %s", err, out.String())
+	}
+	if port := schema.Properties["database"].Properties["port"]; port.Type != "integer" {
+		t.Errorf("unexpected database.port schema: %+v", port)
+	}
+	if store := schema.Properties["photos"].Properties["blob"]; store.Type != "object" {
+		t.Errorf("unexpected photos.blob schema: %+v", store)
+	}
+	if timeout := schema.Properties["health"].Properties["timeout"]; timeout.Type != "string" || timeout.Pattern == "" {
+		t.Errorf("unexpected health.timeout schema: %+v", timeout)
+	}
+	if publisher := schema.Properties["outbox"].Properties["publisher"]; len(publisher.Enum) == 0 {
+		t.Errorf("unexpected outbox.publisher schema: %+v", publisher)
+	}
+}
@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/waikco/cats-v1/client"
+)
+
+// Exit codes returned by the cli subcommands.
+const (
+	ExitOK          = 0
+	ExitError       = 1
+	ExitNotFound    = 3
+	ExitClientError = 4
+	ExitServerError = 5
+)
+
+// exitCode maps an error returned by a command to the process exit code,
+// distinguishing http errors reported by the api.
+func exitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		return ExitError
+	}
+	switch {
+	case apiErr.StatusCode == http.StatusNotFound:
+		return ExitNotFound
+	case apiErr.StatusCode >= 500:
+		return ExitServerError
+	case apiErr.StatusCode >= 400:
+		return ExitClientError
+	default:
+		return ExitError
+	}
+}
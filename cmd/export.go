@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/waikco/cats-v1/bulk"
+	"github.com/waikco/cats-v1/model"
+)
+
+// exportCmd writes every cat in the configured storage to a file
+var exportCmd = &cobra.Command{
+	Use:           "export [file]",
+	Short:         "Export every cat in storage to a csv, json or ndjson file",
+	Long:          "Export every cat in the configured storage, streaming them to a file or, without one, to stdout.",
+	Args:          cobra.MaximumNArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		formatName, _ := cmd.Flags().GetString("format")
+		tenant, _ := cmd.Flags().GetString("tenant")
+		path := "-"
+		if len(args) == 1 {
+			path = args[0]
+		}
+		if formatName == "" && path == "-" {
+			formatName = string(bulk.NDJSON)
+		}
+		format, err := importFormat(path, formatName)
+		if err != nil {
+			return err
+		}
+
+		var out io.Writer = cmd.OutOrStdout()
+		if path != "-" {
+			f, err := os.Create(path)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			out = f
+		}
+
+		storage, err := openStorage(tenant)
+		if err != nil {
+			return err
+		}
+
+		n, err := exportCats(context.Background(), storage, out, format)
+		if err != nil {
+			return err
+		}
+		if path != "-" {
+			fmt.Fprintf(cmd.OutOrStdout(), "exported %d cats to %s\n", n, path)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().String("format", "", "format of the file, one of csv, json or ndjson (default from the file extension, ndjson for stdout)")
+	exportCmd.Flags().String("tenant", "", "id of the tenant to export the cats of (default the default tenant)")
+}
+
+// exportCats writes every cat to out, returning how many were written.
+func exportCats(ctx context.Context, storage model.Storage, out io.Writer, format bulk.Format) (int, error) {
+	w, err := bulk.NewWriter(out, format)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	err = model.Each(ctx, storage, model.Filter{}, func(cat model.Cat) error {
+		n++
+		return w.Write(cat)
+	})
+	if err != nil {
+		return n, err
+	}
+	return n, w.Close()
+}
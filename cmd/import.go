@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/waikco/cats-v1/bulk"
+	"github.com/waikco/cats-v1/model"
+	"github.com/waikco/cats-v1/reference"
+)
+
+// importCmd loads cats from a file straight into the configured storage
+var importCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import cats from a csv, json or ndjson file into storage",
+	Long: `Import cats from a csv, json or ndjson file straight into the configured storage.
+
+Invalid records, and records failing to be stored, are skipped and listed in the
+error report, if one is requested.
+After a failure, --resume-from-line continues an import from the line after the
+last committed one, which is printed as the import progresses. For json arrays
+lines are record numbers, starting at 1.`,
+	Args:          cobra.ExactArgs(1),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		flags := cmd.Flags()
+		formatName, _ := flags.GetString("format")
+		mappingSpec, _ := flags.GetString("map")
+		reportPath, _ := flags.GetString("error-report")
+		tenant, _ := flags.GetString("tenant")
+
+		opts := importOptions{}
+		opts.dryRun, _ = flags.GetBool("dry-run")
+		opts.batchSize, _ = flags.GetInt("batch-size")
+		opts.resumeFromLine, _ = flags.GetInt("resume-from-line")
+		if opts.batchSize < 1 {
+			return fmt.Errorf("batch size must be at least 1")
+		}
+
+		format, err := importFormat(args[0], formatName)
+		if err != nil {
+			return err
+		}
+		mapping, err := bulk.ParseMapping(mappingSpec)
+		if err != nil {
+			return err
+		}
+
+		var in io.Reader = cmd.InOrStdin()
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			in = f
+		}
+		reader, err := bulk.NewReader(in, format, mapping)
+		if err != nil {
+			return err
+		}
+
+		if reportPath != "" {
+			f, err := os.Create(reportPath)
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			opts.errorReport = f
+		}
+
+		var storage model.Storage
+		if !opts.dryRun {
+			if storage, err = openStorage(tenant); err != nil {
+				return err
+			}
+		}
+
+		if opts.catalog, err = reference.Load(storage, viper.GetBool("reference.strict")); err != nil {
+			return err
+		}
+
+		result, err := importCats(storage, reader, opts, cmd.OutOrStdout())
+		fmt.Fprintf(cmd.OutOrStdout(), "%s: %d imported, %d invalid, %d failed, last committed line %d\n",
+			importVerb(opts.dryRun), result.imported, result.invalid, result.failed, result.lastCommittedLine)
+		if err != nil {
+			return err
+		}
+		if result.invalid > 0 || result.failed > 0 {
+			return fmt.Errorf("%d records were not imported", result.invalid+result.failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	f := importCmd.Flags()
+	f.String("format", "", "format of the file, one of csv, json or ndjson (default from the file extension)")
+	f.String("map", "", "csv header mapping from cat fields to columns, such as name=Cat Name,color=Colour")
+	f.Bool("dry-run", false, "parse and validate the file without storing any cats")
+	f.Int("batch-size", 100, "number of cats stored per transaction")
+	f.String("error-report", "", "write every record that was not imported to this ndjson file")
+	f.Int("resume-from-line", 0, "skip every record before this line")
+	f.String("tenant", "", "id of the tenant to import the cats into (default the default tenant)")
+}
+
+func importFormat(path, name string) (bulk.Format, error) {
+	if name != "" {
+		return bulk.ParseFormat(name)
+	}
+	if path == "-" {
+		return "", fmt.Errorf("--format is required when reading from stdin")
+	}
+	return bulk.FormatFromPath(path)
+}
+
+func importVerb(dryRun bool) string {
+	if dryRun {
+		return "dry run"
+	}
+	return "import"
+}
+
+type importOptions struct {
+	dryRun         bool
+	batchSize      int
+	resumeFromLine int
+	errorReport    io.Writer
+	// catalog normalises the breeds and colors of the cats imported.
+	catalog *reference.Catalog
+}
+
+type importResult struct {
+	imported          int
+	invalid           int
+	failed            int
+	lastCommittedLine int
+}
+
+// importFailure is a line of the error report.
+type importFailure struct {
+	Line  int       `json:"line"`
+	Error string    `json:"error"`
+	Cat   model.Cat `json:"cat"`
+}
+
+// importCats validates every record read and stores the valid ones in batches.
+func importCats(storage model.Storage, reader bulk.Reader, opts importOptions, progress io.Writer) (importResult, error) {
+	var result importResult
+
+	report := func(record bulk.Record, err error) error {
+		log.Debug().Msgf("line %d not imported: %v", record.Line, err)
+		if opts.errorReport == nil {
+			return nil
+		}
+		b, _ := json.Marshal(importFailure{Line: record.Line, Error: err.Error(), Cat: record.Cat})
+		_, werr := opts.errorReport.Write(append(b, '\n'))
+		return werr
+	}
+
+	var batch []bulk.Record
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		last := batch[len(batch)-1].Line
+		stored := len(batch)
+		if !opts.dryRun {
+			stored = 0
+			for i, err := range storeBatch(storage, batch) {
+				if err == nil {
+					stored++
+					continue
+				}
+				result.failed++
+				if rerr := report(batch[i], err); rerr != nil {
+					return rerr
+				}
+			}
+			if stored > 0 {
+				fmt.Fprintf(progress, "committed %d cats up to line %d\n", stored, last)
+			}
+		}
+		result.imported += stored
+		if stored > 0 {
+			result.lastCommittedLine = last
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		record, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if ferr := flush(); ferr != nil {
+				return result, ferr
+			}
+			return result, err
+		}
+		if record.Line < opts.resumeFromLine {
+			continue
+		}
+
+		if record.Err == nil {
+			record.Cat.Normalize(time.Now())
+			if record.Err = opts.catalog.Normalize(&record.Cat); record.Err == nil {
+				record.Err = record.Cat.Validate()
+			}
+		}
+		if record.Err != nil {
+			result.invalid++
+			if err := report(record, record.Err); err != nil {
+				return result, err
+			}
+			continue
+		}
+
+		// ids are always assigned by storage
+		record.Cat.ID = ""
+		batch = append(batch, record)
+		if len(batch) >= opts.batchSize {
+			if err := flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	return result, flush()
+}
+
+// storeBatch stores a batch of cats, returning why each record was not stored, or nil for
+// the stored ones. Storage able to insert batches stores all of them atomically or none,
+// other storage stores them one by one, so only the records failing are not stored.
+func storeBatch(storage model.Storage, batch []bulk.Record) []error {
+	errs := make([]error, len(batch))
+	if b, ok := storage.(model.BatchInserter); ok {
+		cats := make([]model.Cat, len(batch))
+		for i, record := range batch {
+			cats[i] = record.Cat
+		}
+		if _, err := b.InsertBatch(cats); err != nil {
+			for i := range errs {
+				errs[i] = err
+			}
+		}
+		return errs
+	}
+	for i, record := range batch {
+		body, err := json.Marshal(record.Cat)
+		if err == nil {
+			_, err = storage.Insert(body)
+		}
+		errs[i] = err
+	}
+	return errs
+}
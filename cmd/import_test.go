@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/bulk"
+	"github.com/waikco/cats-v1/model"
+	"github.com/waikco/cats-v1/reference"
+)
+
+func TestImportCats(t *testing.T) {
+	input := "name,color,age\n" +
+		"cat-1,black,1\n" +
+		"cat-2,,2\n" +
+		"cat-3,white,3\n" +
+		"cat-4,ginger,x\n" +
+		"cat-5,grey,5\n"
+
+	tests := []struct {
+		description string
+		// given
+		opts       importOptions
+		insertErrs []error
+		// then
+		expectedResult  importResult
+		expectedReports int
+	}{
+		{
+			description:     "valid records are imported and invalid ones reported",
+			opts:            importOptions{batchSize: 2},
+			insertErrs:      []error{nil, nil, nil},
+			expectedResult:  importResult{imported: 3, invalid: 2, lastCommittedLine: 6},
+			expectedReports: 2,
+		},
+		{
+			description:     "dry run stores nothing",
+			opts:            importOptions{batchSize: 2, dryRun: true},
+			expectedResult:  importResult{imported: 3, invalid: 2, lastCommittedLine: 6},
+			expectedReports: 2,
+		},
+		{
+			description:     "resume skips earlier lines",
+			opts:            importOptions{batchSize: 2, resumeFromLine: 4},
+			insertErrs:      []error{nil, nil},
+			expectedResult:  importResult{imported: 2, invalid: 1, lastCommittedLine: 6},
+			expectedReports: 1,
+		},
+		{
+			description:     "only the failed records of a batch are reported",
+			opts:            importOptions{batchSize: 2},
+			insertErrs:      []error{nil, errors.New("database error"), nil},
+			expectedResult:  importResult{imported: 2, invalid: 2, failed: 1, lastCommittedLine: 6},
+			expectedReports: 3,
+		},
+		{
+			description: "unknown colors are reported in strict mode",
+			opts: importOptions{batchSize: 2, catalog: reference.New(nil, []model.Color{
+				{Name: "white", Hex: "#ffffff"}, {Name: "grey", Hex: "#808080"}}, true)},
+			insertErrs:      []error{nil, nil},
+			expectedResult:  importResult{imported: 2, invalid: 3, lastCommittedLine: 6},
+			expectedReports: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockStorage(ctrl)
+			var calls []*gomock.Call
+			for _, err := range tt.insertErrs {
+				calls = append(calls, s.EXPECT().Insert(gomock.Any()).Return("id", err))
+			}
+			gomock.InOrder(calls...)
+
+			reader, err := bulk.NewReader(strings.NewReader(input), bulk.CSV, nil)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var report bytes.Buffer
+			tt.opts.errorReport = &report
+
+			got, err := importCats(s, reader, tt.opts, ioutil.Discard)
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.expectedResult {
+				t.Errorf("unexpected result: got %+v, expected %+v", got, tt.expectedResult)
+			}
+			if lines := strings.Count(report.String(), "\n"); lines != tt.expectedReports {
+				t.Errorf("unexpected error report lines: got %d, expected %d\n%s", lines, tt.expectedReports, report.String())
+			}
+		})
+	}
+}
+
+func TestExportCats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockStorage(ctrl)
+	s.EXPECT().
+		SelectAll(100, 0).
+		Return([]byte(`[{"id":"1","name":"cat-1","color":"black","age":1}]`), nil)
+
+	var out bytes.Buffer
+	n, err := exportCats(context.Background(), s, &out, bulk.CSV)
+
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("unexpected count: got %d, expected 1", n)
+	}
+	if expected := "id,name,color,age,breed,birth_date,sex,neutered,weight_kg,microchip,tags\n1,cat-1,black,1,,,,,,,\n"; out.String() != expected {
+		t.Errorf("unexpected output: got %q, expected %q", out.String(), expected)
+	}
+}
+
+func TestExportCatsEmpty(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockStorage(ctrl)
+	s.EXPECT().
+		SelectAll(100, 0).
+		Return(nil, sql.ErrNoRows)
+
+	var out bytes.Buffer
+	if _, err := exportCats(context.Background(), s, &out, bulk.JSON); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.String() != "[]\n" {
+		t.Errorf("unexpected output: got %q, expected %q", out.String(), "[]\n")
+	}
+}
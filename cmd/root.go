@@ -18,6 +18,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/waikco/cats-v1/cmd/server"
 
@@ -30,6 +31,14 @@ import (
 
 var cfgFile string
 
+// overrides are the key=value pairs of the --set flags, overriding every other source of
+// config.
+var overrides []string
+
+// envPrefix prefixes the environment variables settings are read from, such as
+// CATS_DATABASE_PASSWORD for database.password.
+const envPrefix = "CATS"
+
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
 	Use:   "cats-v1",
@@ -37,13 +46,14 @@ var rootCmd = &cobra.Command{
 	Long:  `Provides cat data through a restful API, backed by multiple storage systems`,
 	Run: func(cmd *cobra.Command, args []string) {
 		var a server.App
-		var config conf.Config
 
-		viperInstance := viper.GetViper()
-		err := viperInstance.UnmarshalExact(&config)
+		config, err := loadConfig()
 		if err != nil {
 			log.Panic().Msgf("error parsing config: %v", err)
 		}
+		if err := config.Validate(); err != nil {
+			log.Panic().Msgf("%v", err)
+		}
 		a.Config = config
 
 		a.Bootstrap()
@@ -56,7 +66,7 @@ var rootCmd = &cobra.Command{
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
@@ -68,12 +78,21 @@ func init() {
 	// will be global for your application.
 
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.cats-v1.yaml)")
+	rootCmd.PersistentFlags().StringArrayVar(&overrides, "set", nil,
+		"override a config key, such as --set database.port=5433, may be repeated")
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
 }
 
+// loadConfig unmarshals the config read by initConfig.
+func loadConfig() (conf.Config, error) {
+	var config conf.Config
+	err := viper.GetViper().UnmarshalExact(&config)
+	return config, err
+}
+
 // initConfig reads in config file and ENV variables if set.
 func initConfig() {
 	if cfgFile != "" {
@@ -93,10 +112,41 @@ func initConfig() {
 		viper.SetConfigName("config")
 	}
 
-	viper.AutomaticEnv() // read in environment variables that match
+	// read in environment variables that match, binding every key so that nested keys
+	// are read even when no config file sets them
+	viper.SetEnvPrefix(envPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+	for _, key := range conf.Keys() {
+		_ = viper.BindEnv(key)
+	}
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
 		log.Info().Msgf("Using config file: %s", viper.ConfigFileUsed())
 	}
+
+	if err := applyOverrides(overrides); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// envName returns the environment variable a key is read from, such as
+// CATS_DATABASE_PASSWORD for database.password.
+func envName(key string) string {
+	return envPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// applyOverrides sets the keys of key=value pairs, unknown keys being rejected when the
+// config is unmarshalled.
+func applyOverrides(overrides []string) error {
+	for _, override := range overrides {
+		key, value, ok := strings.Cut(override, "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			return fmt.Errorf("invalid --set %q, expected key=value", override)
+		}
+		viper.Set(strings.TrimSpace(key), value)
+	}
+	return nil
 }
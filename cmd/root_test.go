@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+func TestLoadConfig(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		cfgFile   string
+		env       map[string]string
+		overrides []string
+		// then
+		expectedPassword string
+		expectedPort     string
+		expectedErr      bool
+	}{
+		{
+			description:      "nested keys are read from the environment without a config file",
+			env:              map[string]string{"CATS_DATABASE_PASSWORD": "secret", "CATS_SERVER_PORT": "8443"},
+			expectedPassword: "secret",
+			expectedPort:     "8443",
+		},
+		{
+			description:      "the environment overrides the config file",
+			cfgFile:          "../testing/config.yaml",
+			env:              map[string]string{"CATS_DATABASE_PASSWORD": "secret"},
+			expectedPassword: "secret",
+			expectedPort:     "8080",
+		},
+		{
+			description:      "--set overrides the environment",
+			cfgFile:          "../testing/config.yaml",
+			env:              map[string]string{"CATS_SERVER_PORT": "8443"},
+			overrides:        []string{"server.port=9443", "database.password=a=b"},
+			expectedPassword: "a=b",
+			expectedPort:     "9443",
+		},
+		{
+			description: "--set of an unknown key",
+			cfgFile:     "../testing/config.yaml",
+			overrides:   []string{"database.nope=1"},
+			expectedErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfgFile, overrides = tt.cfgFile, tt.overrides
+			defer func() { cfgFile, overrides = "", nil }()
+			initConfig()
+
+			config, err := loadConfig()
+			if (err != nil) != tt.expectedErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectedErr {
+				return
+			}
+			if config.Database.Password != tt.expectedPassword {
+				t.Errorf("unexpected database password: got %q, expected %q", config.Database.Password, tt.expectedPassword)
+			}
+			if config.Server.Port != tt.expectedPort {
+				t.Errorf("unexpected server port: got %q, expected %q", config.Server.Port, tt.expectedPort)
+			}
+		})
+	}
+}
+
+func TestApplyOverrides(t *testing.T) {
+	defer viper.Reset()
+	if err := applyOverrides([]string{"server.port"}); err == nil {
+		t.Errorf("unexpected success of an override without a value")
+	}
+}
+
+func TestLoadConfigWithoutEnvironment(t *testing.T) {
+	b, err := ioutil.ReadFile("../testing/config.yaml")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// configs predating environments have no environment key
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := ioutil.WriteFile(path, bytes.Replace(b, []byte("environment: test\n"), nil, 1), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	viper.Reset()
+	defer viper.Reset()
+	cfgFile = path
+	defer func() { cfgFile = "" }()
+	initConfig()
+
+	config, err := loadConfig()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config.Environment != "" {
+		t.Errorf("unexpected environment: %q", config.Environment)
+	}
+	if err := config.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
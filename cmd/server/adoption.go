@@ -0,0 +1,111 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	json "github.com/json-iterator/go"
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/model"
+)
+
+// transitionStore returns the storage of the statuses of the cats of the tenant of a request.
+func (a *App) transitionStore(w http.ResponseWriter, r *http.Request) (model.TransitionStore, bool) {
+	return optional[model.TransitionStore](w, r, a.storage(r.Context()), "adoption statuses")
+}
+
+// TransitionCat moves a cat to another status of its adoption, responding with a 409 when
+// the status may not follow the current one or a guard of the status rejects the cat, and
+// with the recorded transition otherwise.
+func (a *App) TransitionCat(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.transitionStore(w, r)
+	if !ok {
+		return
+	}
+	catID := ps.ByName("id")
+	if uuid.FromStringOrNil(catID) == uuid.Nil {
+		respondError(w, r, http.StatusBadRequest, "invalid cat id: %s", catID)
+		return
+	}
+
+	var t model.Transition
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		_, err = decodeBody(r, body, &t)
+	}
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "invalid transition in request body")
+		return
+	}
+	t.To = strings.ToLower(strings.TrimSpace(t.To))
+	t.Actor = strings.TrimSpace(t.Actor)
+	t.Reason = strings.TrimSpace(t.Reason)
+	if invalidBody(w, r, t.Validate()) {
+		return
+	}
+
+	cat, t, err := a.transition(r, store, catID, t)
+	var invalid model.TransitionError
+	switch {
+	case err == nil:
+		cat.ID, cat.Status = catID, t.To
+		a.publish(r.Context(), events.Updated, catID, &cat)
+		respond(w, r, http.StatusCreated, t)
+	case err == sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "cat id %s not found", catID)
+	case errors.As(err, &invalid), err == model.ErrStatusChanged:
+		respondError(w, r, http.StatusConflict, "%v", err)
+	default:
+		log.Error().Msgf("error transitioning cat %s to %s: %v", catID, t.To, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to transition cat")
+	}
+}
+
+// transition moves a cat to the status of the transition when its guards allow, returning
+// the cat and the recorded transition. The cat is read from storage rather than the cache,
+// as the guards must see its current status and owner.
+func (a *App) transition(r *http.Request, store model.TransitionStore, catID string, t model.Transition) (model.Cat, model.Transition, error) {
+	var cat model.Cat
+	b, err := a.storage(r.Context()).Select(catID)
+	if err == nil {
+		err = json.Unmarshal(b, &cat)
+	}
+	if err == nil {
+		err = cat.CanTransition(t.To)
+	}
+	if err != nil {
+		return cat, t, err
+	}
+	if t.From = cat.Status; t.From == "" {
+		t.From = model.StatusIntake
+	}
+	t.ID, t.CatID = "", catID
+	t, err = store.TransitionCat(t)
+	return cat, t, err
+}
+
+// GetTransitions lists the transitions of a cat, oldest first.
+func (a *App) GetTransitions(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.transitionStore(w, r)
+	if !ok {
+		return
+	}
+	catID := ps.ByName("id")
+	if !a.catExists(w, r, catID) {
+		return
+	}
+
+	transitions, err := store.SelectTransitions(catID)
+	if err != nil {
+		log.Error().Msgf("error getting transitions of cat %s: %v", catID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get transitions")
+		return
+	}
+	respond(w, r, http.StatusOK, transitions)
+}
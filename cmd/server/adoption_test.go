@@ -0,0 +1,201 @@
+package server
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/model"
+)
+
+// transitionStorage is storage able to track the status of cats.
+type transitionStorage struct {
+	*model.MockStorage
+	*model.MockTransitionStore
+}
+
+func TestApp_Transitions(t *testing.T) {
+	const (
+		transitionID = "8c1d2e3f-4a5b-4c6d-8e7f-9a0b1c2d3e4f"
+		ownerID      = "3f2e1d0c-9b8a-4765-8432-10fedcba9876"
+	)
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	recorded := func(from, to string) model.Transition {
+		return model.Transition{ID: transitionID, CatID: catID, From: from, To: to, Actor: "jane", CreatedAt: created}
+	}
+
+	tests := []struct {
+		description string
+		// given
+		method string
+		body   string
+		mock   func(s *model.MockTransitionStore, storage *model.MockStorage)
+		// then
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description: "make available",
+			method:      http.MethodPost,
+			body:        `{"to":"available","actor":" jane "}`,
+			mock: func(s *model.MockTransitionStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom","color":"grey","status":"intake"}`), nil)
+				s.EXPECT().TransitionCat(model.Transition{CatID: catID, From: model.StatusIntake, To: model.StatusAvailable,
+					Actor: "jane"}).Return(recorded(model.StatusIntake, model.StatusAvailable), nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"id":"` + transitionID + `","from":"intake","to":"available","actor":"jane",` +
+				`"createdAt":"2020-01-02T03:04:05Z"}`,
+		},
+		{
+			description: "cats without a status are at intake",
+			method:      http.MethodPost,
+			body:        `{"to":"on-hold","actor":"jane","reason":"vet visit"}`,
+			mock: func(s *model.MockTransitionStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom","color":"grey"}`), nil)
+				s.EXPECT().TransitionCat(model.Transition{CatID: catID, From: model.StatusIntake, To: model.StatusOnHold,
+					Actor: "jane", Reason: "vet visit"}).Return(recorded(model.StatusIntake, model.StatusOnHold), nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			description: "pending adoption with an owner",
+			method:      http.MethodPost,
+			body:        `{"to":"pending-adoption","actor":"jane"}`,
+			mock: func(s *model.MockTransitionStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom","color":"grey","status":"available",`+
+					`"ownerId":"`+ownerID+`"}`), nil)
+				s.EXPECT().TransitionCat(model.Transition{CatID: catID, From: model.StatusAvailable,
+					To: model.StatusPendingAdoption, Actor: "jane"}).Return(recorded(model.StatusAvailable, model.StatusPendingAdoption), nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			description: "adopt without an owner",
+			method:      http.MethodPost,
+			body:        `{"to":"adopted","actor":"jane"}`,
+			mock: func(s *model.MockTransitionStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom","color":"grey","status":"pending-adoption"}`), nil)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{"error":{"status":409,"message":"cannot move cat from pending-adoption to adopted: ` +
+				`the cat must be assigned an owner"}}`,
+		},
+		{
+			description: "adopt before pending adoption",
+			method:      http.MethodPost,
+			body:        `{"to":"adopted","actor":"jane"}`,
+			mock: func(s *model.MockTransitionStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom","color":"grey","status":"available",`+
+					`"ownerId":"`+ownerID+`"}`), nil)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{"error":{"status":409,"message":"cannot move cat from available to adopted: ` +
+				`expected one of on-hold, pending-adoption"}}`,
+		},
+		{
+			description: "status changed meanwhile",
+			method:      http.MethodPost,
+			body:        `{"to":"available","actor":"jane"}`,
+			mock: func(s *model.MockTransitionStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom","color":"grey","status":"intake"}`), nil)
+				s.EXPECT().TransitionCat(gomock.Any()).Return(model.Transition{}, model.ErrStatusChanged)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":{"status":409,"message":"the status of the cat changed"}}`,
+		},
+		{
+			description: "owner unassigned meanwhile",
+			method:      http.MethodPost,
+			body:        `{"to":"pending-adoption","actor":"jane"}`,
+			mock: func(s *model.MockTransitionStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom","color":"grey","status":"available",`+
+					`"ownerId":"`+ownerID+`"}`), nil)
+				s.EXPECT().TransitionCat(gomock.Any()).Return(model.Transition{}, model.TransitionError{
+					From: model.StatusAvailable, To: model.StatusPendingAdoption, Reason: "the cat must be assigned an owner"})
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody: `{"error":{"status":409,"message":"cannot move cat from available to pending-adoption: ` +
+				`the cat must be assigned an owner"}}`,
+		},
+		{
+			description: "unknown cat",
+			method:      http.MethodPost,
+			body:        `{"to":"available","actor":"jane"}`,
+			mock: func(s *model.MockTransitionStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			description:    "unknown status",
+			method:         http.MethodPost,
+			body:           `{"to":"sold","actor":"jane"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "without an actor",
+			method:         http.MethodPost,
+			body:           `{"to":"available"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description: "history",
+			method:      http.MethodGet,
+			mock: func(s *model.MockTransitionStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom","color":"grey","status":"available"}`), nil)
+				s.EXPECT().SelectTransitions(catID).Return([]model.Transition{recorded(model.StatusIntake, model.StatusAvailable)}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"id":"` + transitionID + `","from":"intake","to":"available","actor":"jane",` +
+				`"createdAt":"2020-01-02T03:04:05Z"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockTransitionStore(ctrl)
+			storage := model.NewMockStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(s, storage)
+			}
+			a := App{Storage: transitionStorage{storage, s}, Config: conf.SaneDefaults()}
+			a.BootstrapServer()
+
+			req, _ := http.NewRequest(tt.method, "/cats/v1/cats/"+catID+"/transitions", bytes.NewBufferString(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, tt.expectedStatus, response.Body)
+			}
+			if tt.expectedBody != "" && response.Body.String() != tt.expectedBody {
+				t.Errorf("unxpected response body: got %s, expected %s", response.Body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestApp_Transitions_Unsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	a := App{Storage: model.NewMockStorage(ctrl)}
+	a.BootstrapServer()
+
+	req, _ := http.NewRequest(http.MethodGet, "/cats/v1/cats/"+catID+"/transitions", nil)
+	response := httptest.NewRecorder()
+	a.Router.ServeHTTP(response, req)
+
+	if response.Code != http.StatusNotImplemented {
+		t.Errorf("unxpected status code: got %d, expected %d", response.Code, http.StatusNotImplemented)
+	}
+}
@@ -1,50 +1,292 @@
 package server
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
 
 	"github.com/julienschmidt/httprouter"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	"github.com/waikco/cats-v1/buildinfo"
 	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/graph"
+	"github.com/waikco/cats-v1/healthcheck"
 	"github.com/waikco/cats-v1/model"
+	"github.com/waikco/cats-v1/outbox"
+	"github.com/waikco/cats-v1/photos"
+	"github.com/waikco/cats-v1/reference"
+	"github.com/waikco/cats-v1/webhooks"
+	"google.golang.org/grpc"
 )
 
 // App ...
 type App struct {
-	Server  *http.Server
-	Storage model.Storage
-	Router  http.Handler
-	Config  conf.Config
+	Server     *http.Server
+	GRPCServer *grpc.Server
+	Storage    model.Storage
+	Router     http.Handler
+	Config     conf.Config
+	Checks     *healthcheck.Registry
+	Events     *events.Bus
+	Graph      *graph.Executor
+	Webhooks   *webhooks.Dispatcher
+	Cache      *model.Cache
+	Changes    *model.ChangeListener
+	Outbox     *outbox.Relay
+	// Idempotency keeps the idempotency keys of requests, in the storage when it is able to.
+	Idempotency model.IdempotencyStore
+	// Blobs keeps the files of photos, which are not supported when it is nil.
+	Blobs photos.BlobStore
+	// Reference holds the breeds and colors cats are normalised against.
+	Reference *reference.Catalog
 }
 
 // Bootstrap prepares app for run by setting things up based on provided config.
 func (a *App) Bootstrap() {
 	a.BootstrapLogger()
+	log.Info().Msgf("starting cats-v1 %s", buildinfo.Get())
 	storage, err := model.BootstrapPostgres(a.Config.Database)
 	if err != nil {
 		log.Fatal().Err(err)
 	}
 	a.Storage = storage
+	if a.Config.Database.Listen {
+		changes, err := model.ListenPostgres(a.Config.Database)
+		if err != nil {
+			log.Fatal().Msgf("Unable to listen for changes: %s", err)
+		}
+		a.Changes = changes
+	}
+	if a.Config.Database.Outbox {
+		a.BootstrapOutbox()
+	}
+	catalog, err := reference.Load(a.Storage, a.Config.Reference.Strict)
+	if err != nil {
+		log.Fatal().Msgf("Unable to load reference data: %s", err)
+	}
+	a.Reference = catalog
+	if a.Config.Photos.Blob.Store != "" {
+		blobs, err := photos.NewBlobStore(a.Config.Photos.Blob)
+		if err != nil {
+			log.Fatal().Msgf("Unable to create blob store: %s", err)
+		}
+		a.Blobs = blobs
+	}
 	a.BootstrapServer()
+	if a.Config.Server.GRPCPort != "" {
+		a.BootstrapGRPC()
+	}
+}
+
+// BootstrapOutbox prepares the relay publishing the events the storage writes to the outbox.
+func (a *App) BootstrapOutbox() {
+	store, ok := a.Storage.(model.OutboxStore)
+	if !ok {
+		log.Fatal().Msg("the configured storage has no outbox")
+	}
+	publisher, err := outbox.NewPublisher(a.Config.Outbox)
+	if err != nil {
+		log.Fatal().Msgf("Unable to create outbox publisher: %s", err)
+	}
+	a.Outbox = outbox.NewRelay(store, publisher, outbox.Options{
+		Source:       a.Config.Outbox.Source,
+		BatchSize:    a.Config.Outbox.BatchSize,
+		PollInterval: a.Config.Outbox.PollInterval,
+		Timeout:      a.Config.Outbox.Timeout,
+		MinBackoff:   a.Config.Outbox.MinBackoff,
+		MaxBackoff:   a.Config.Outbox.MaxBackoff,
+	})
+}
+
+// BootstrapChecks registers the dependency checks reported by the readiness endpoint.
+func (a *App) BootstrapChecks() {
+	a.Checks = healthcheck.NewRegistry(a.Config.Health.Timeout, a.Config.Health.CacheTTL)
+
+	a.Checks.Register(healthcheck.Check{
+		Name:     "storage",
+		Critical: true,
+		Func: func(ctx context.Context) error {
+			if s, ok := a.Storage.(model.StatusContexter); ok {
+				return s.StatusContext(ctx)
+			}
+			return a.Storage.Status()
+		},
+	})
+
+	if a.Cache != nil {
+		a.Checks.Register(healthcheck.Check{
+			Name: "cache",
+			Func: func(ctx context.Context) error {
+				return a.Cache.Ping()
+			},
+		})
+	}
+
+	if a.Changes != nil {
+		a.Checks.Register(healthcheck.Check{
+			Name: "changes",
+			Func: func(ctx context.Context) error {
+				return a.Changes.Ping()
+			},
+		})
+	}
+
+	if m, ok := a.Storage.(model.Migrator); ok {
+		a.Checks.Register(healthcheck.Check{
+			Name:     "migrations",
+			Critical: true,
+			Func: func(ctx context.Context) error {
+				pending, err := m.PendingMigrations()
+				if err != nil {
+					return err
+				}
+				if len(pending) > 0 {
+					return fmt.Errorf("%d pending migrations: %v", len(pending), pending)
+				}
+				return nil
+			},
+		})
+	}
+}
+
+// Route is a single api endpoint served by the router.
+type Route struct {
+	Method string
+	Path   string
+	Handle httprouter.Handle
+	// Shadowed routes conflict with a wildcard route the router is unable to register them
+	// alongside, so are served by the wildcard route's handler instead.
+	Shadowed bool
+}
+
+// Routes lists every api route, every one of which must be described in the openapi spec.
+func (a *App) Routes() []Route {
+	return []Route{
+		{Method: http.MethodGet, Path: "/cats/v1/health", Handle: negotiate(a.Live)},
+		{Method: http.MethodGet, Path: "/cats/v1/live", Handle: negotiate(a.Live)},
+		{Method: http.MethodGet, Path: "/cats/v1/ready", Handle: negotiate(a.Ready)},
+		{Method: http.MethodGet, Path: "/cats/v1/version", Handle: negotiate(a.Version)},
+		{Method: http.MethodGet, Path: "/cats/v1/openapi.json", Handle: a.OpenAPI},
+		{Method: http.MethodGet, Path: "/cats/v1/docs", Handle: a.Docs},
+		{Method: http.MethodGet, Path: "/cats/v1/docs/:file", Handle: a.DocsAsset},
+		{Method: http.MethodGet, Path: "/cats/v1/cats/stream", Handle: a.tenant(a.Stream), Shadowed: true},
+		{Method: http.MethodGet, Path: "/cats/v1/cats/:id", Handle: a.tenant(a.streamOr(negotiate(a.GetCat)))},
+		{Method: http.MethodGet, Path: "/cats/v1/cats", Handle: a.tenant(negotiate(a.GetCats))},
+		{Method: http.MethodGet, Path: "/cats/v1/export", Handle: a.tenant(a.Export)},
+		{Method: http.MethodGet, Path: "/cats/v1/graphql", Handle: a.tenant(a.GraphQL)},
+		{Method: http.MethodPost, Path: "/cats/v1/graphql", Handle: a.tenant(a.GraphQL)},
+		{Method: http.MethodPost, Path: "/cats/v1/", Handle: a.tenant(a.idempotent(negotiate(a.CreateCat)))},
+		{Method: http.MethodPost, Path: "/cats/v1/bulkcatadd", Handle: a.tenant(a.idempotent(negotiate(a.MassCreateCat)))},
+		{Method: http.MethodPut, Path: "/cats/v1/:id", Handle: a.tenant(negotiate(a.UpdateCat))},
+		{Method: http.MethodDelete, Path: "/cats/v1/cats/:id", Handle: a.tenant(negotiate(a.DeleteCat))},
+		{Method: http.MethodGet, Path: "/cats/v1/cats/:id/photos", Handle: a.tenant(a.GetPhotos)},
+		{Method: http.MethodPost, Path: "/cats/v1/cats/:id/photos", Handle: a.tenant(a.UploadPhoto)},
+		{Method: http.MethodGet, Path: "/cats/v1/cats/:id/photos/:photoId", Handle: a.tenant(a.GetPhoto)},
+		{Method: http.MethodGet, Path: "/cats/v1/cats/:id/photos/:photoId/thumbnail", Handle: a.tenant(a.GetThumbnail)},
+		{Method: http.MethodDelete, Path: "/cats/v1/cats/:id/photos/:photoId", Handle: a.tenant(a.DeletePhoto)},
+		{Method: http.MethodGet, Path: "/cats/v1/cats/:id/medical", Handle: a.tenant(negotiate(a.GetMedicalRecords))},
+		{Method: http.MethodPost, Path: "/cats/v1/cats/:id/medical", Handle: a.tenant(negotiate(a.CreateMedicalRecord))},
+		{Method: http.MethodGet, Path: "/cats/v1/cats/:id/medical/:recordId", Handle: a.tenant(negotiate(a.GetMedicalRecord))},
+		{Method: http.MethodDelete, Path: "/cats/v1/cats/:id/medical/:recordId", Handle: a.tenant(negotiate(a.DeleteMedicalRecord))},
+		{Method: http.MethodGet, Path: "/cats/v1/cats/:id/transitions", Handle: a.tenant(negotiate(a.GetTransitions))},
+		{Method: http.MethodPost, Path: "/cats/v1/cats/:id/transitions", Handle: a.tenant(negotiate(a.TransitionCat))},
+		{Method: http.MethodGet, Path: "/cats/v1/vaccinations/due", Handle: a.tenant(negotiate(a.GetDueVaccinations))},
+		{Method: http.MethodGet, Path: "/cats/v1/owners", Handle: a.tenant(negotiate(a.GetOwners))},
+		{Method: http.MethodPost, Path: "/cats/v1/owners", Handle: a.tenant(negotiate(a.CreateOwner))},
+		{Method: http.MethodGet, Path: "/cats/v1/owners/:id", Handle: a.tenant(negotiate(a.GetOwner))},
+		{Method: http.MethodPatch, Path: "/cats/v1/owners/:id", Handle: a.tenant(negotiate(a.UpdateOwner))},
+		{Method: http.MethodDelete, Path: "/cats/v1/owners/:id", Handle: a.tenant(negotiate(a.DeleteOwner))},
+		{Method: http.MethodGet, Path: "/cats/v1/owners/:id/cats", Handle: a.tenant(negotiate(a.GetOwnerCats))},
+		{Method: http.MethodPost, Path: "/cats/v1/owners/:id/cats/:catId", Handle: a.tenant(negotiate(a.AssignCat))},
+		{Method: http.MethodDelete, Path: "/cats/v1/owners/:id/cats/:catId", Handle: a.tenant(negotiate(a.UnassignCat))},
+		{Method: http.MethodGet, Path: "/cats/v1/breeds", Handle: a.GetBreeds},
+		{Method: http.MethodGet, Path: "/cats/v1/breeds/:name", Handle: a.GetBreed},
+		{Method: http.MethodGet, Path: "/cats/v1/colors", Handle: a.GetColors},
+		{Method: http.MethodGet, Path: "/cats/v1/colors/:name", Handle: a.GetColor},
+		{Method: http.MethodGet, Path: "/cats/v1/webhooks", Handle: a.tenant(negotiate(a.GetWebhooks))},
+		{Method: http.MethodPost, Path: "/cats/v1/webhooks", Handle: a.tenant(negotiate(a.CreateWebhook))},
+		{Method: http.MethodGet, Path: "/cats/v1/webhooks/:id", Handle: a.tenant(negotiate(a.GetWebhook))},
+		{Method: http.MethodDelete, Path: "/cats/v1/webhooks/:id", Handle: a.tenant(negotiate(a.DeleteWebhook))},
+		{Method: http.MethodGet, Path: "/cats/v1/webhooks/:id/deliveries", Handle: a.tenant(negotiate(a.GetWebhookDeliveries))},
+		{Method: http.MethodPost, Path: "/cats/v1/webhooks/:id/deliveries/:deliveryId/replay", Handle: a.tenant(negotiate(a.ReplayWebhookDelivery))},
+		{Method: http.MethodGet, Path: "/cats/v1/tenants", Handle: a.admin(a.GetTenants)},
+		{Method: http.MethodPost, Path: "/cats/v1/tenants", Handle: a.admin(a.CreateTenant)},
+		{Method: http.MethodGet, Path: "/cats/v1/tenants/:id", Handle: a.admin(a.GetTenant)},
+		{Method: http.MethodPatch, Path: "/cats/v1/tenants/:id", Handle: a.admin(a.UpdateTenant)},
+	}
 }
 
 func (a *App) BootstrapServer() {
-	router := httprouter.New()
+	if a.Cache == nil && a.Config.Cache.Size > 0 {
+		a.Cache = model.NewCache(a.Config.Cache.Size, a.Config.Cache.TTL)
+	}
+	if a.Checks == nil {
+		a.BootstrapChecks()
+	}
+	if a.Config.Tenancy.Enabled {
+		_, store := a.Storage.(model.TenantStore)
+		_, scoped := a.Storage.(model.Tenanted)
+		if !store || !scoped {
+			log.Fatal().Msg("tenancy is enabled but the configured storage is unable to scope cats to tenants")
+		}
+	}
+	if a.Events == nil {
+		history := a.Config.Stream.History
+		if history <= 0 {
+			history = events.DefaultHistory
+		}
+		a.Events = events.NewBusWithHistory(history)
+	}
+	if a.Reference == nil {
+		a.Reference = reference.Default(a.Config.Reference.Strict)
+	}
+	if a.Graph == nil {
+		executor, err := graph.New(a.publish, a.Reference, graph.Limits{
+			MaxDepth:      a.Config.GraphQL.MaxDepth,
+			MaxComplexity: a.Config.GraphQL.MaxComplexity,
+		})
+		if err != nil {
+			log.Fatal().Msgf("Unable to create graphql schema: %s", err)
+		}
+		a.Graph = executor
+	}
+	if a.Idempotency == nil {
+		if store, ok := a.Storage.(model.IdempotencyStore); ok {
+			a.Idempotency = store
+		} else {
+			a.Idempotency = model.NewIdempotencyMemory()
+		}
+	}
+	if store, ok := a.Storage.(model.WebhookStore); ok && a.Webhooks == nil {
+		a.Webhooks = webhooks.NewDispatcher(store, webhooks.Options{
+			MaxAttempts:  a.Config.Webhooks.MaxAttempts,
+			MinBackoff:   a.Config.Webhooks.MinBackoff,
+			MaxBackoff:   a.Config.Webhooks.MaxBackoff,
+			Timeout:      a.Config.Webhooks.Timeout,
+			PollInterval: a.Config.Webhooks.PollInterval,
+		})
+	}
 
-	// add actual api routes
-	router.GET("/cats/v1/health", a.Health)
-	router.GET("/cats/v1/cats/:id", a.GetCat)
-	router.GET("/cats/v1/cats", a.GetCats)
-	router.POST("/cats/v1/", a.CreateCat)
-	//router.POST("/cats/v1/bulkcatadd", a.MassCreateCat)
-	router.PUT("/cats/v1/:id", a.UpdateCat)
-	router.DELETE("/cats/v1/cats/:id", a.DeleteCat)
+	router := httprouter.New()
+	for _, route := range a.Routes() {
+		if !route.Shadowed {
+			router.Handle(route.Method, route.Path, route.Handle)
+		}
+	}
 
 	a.Router = router
+	if a.Config.Validation.Enabled {
+		validator, err := NewValidator(a.Config.ValidateResponses())
+		if err != nil {
+			log.Fatal().Msgf("Unable to load api specification: %s", err)
+		}
+		a.Router = validator.Middleware(router)
+	}
 
 	cfg := &tls.Config{}
 	if a.Config.Server.TLS {
@@ -86,5 +328,29 @@ func (a *App) BootstrapLogger() {
 
 //RunApp
 func (a *App) Run() {
+	if a.GRPCServer != nil {
+		go a.RunGRPC()
+	}
+	if a.Webhooks != nil {
+		go a.Webhooks.Run(context.Background())
+	}
+	if a.Changes != nil {
+		go a.FollowChanges(a.Changes.Changes())
+	}
+	if a.Outbox != nil {
+		go a.Outbox.Run(context.Background())
+	}
+	go a.ExpireIdempotencyKeys(context.Background())
 	log.Fatal().Err(a.Server.ListenAndServe())
 }
+
+// RunGRPC serves the grpc api until the server is stopped.
+func (a *App) RunGRPC() {
+	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", a.Config.Server.GRPCPort))
+	if err != nil {
+		log.Fatal().Msgf("Unable to listen for grpc: %s", err)
+	}
+	if err := a.GRPCServer.Serve(lis); err != nil {
+		log.Fatal().Msgf("grpc server failed: %s", err)
+	}
+}
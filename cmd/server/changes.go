@@ -0,0 +1,74 @@
+package server
+
+import (
+	"database/sql"
+
+	json "github.com/json-iterator/go"
+	"github.com/rs/zerolog/log"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/model"
+)
+
+// changeEvents maps the operations of changes to the events announcing them.
+var changeEvents = map[string]events.Type{
+	model.ChangeInsert: events.Created,
+	model.ChangeUpdate: events.Updated,
+	model.ChangeDelete: events.Deleted,
+}
+
+// FollowChanges applies the changes made by other instances until changes is closed.
+func (a *App) FollowChanges(changes <-chan model.Change) {
+	for change := range changes {
+		a.applyChange(change)
+	}
+}
+
+// applyChange invalidates the cache of a cat changed by another instance and announces the
+// change to the app's subscribers. Webhooks are left to the instance making the change.
+func (a *App) applyChange(change model.Change) {
+	if change.Local() {
+		return
+	}
+	if change.Op == model.ChangeResync {
+		log.Info().Msg("clearing the cache after missing changes")
+		if a.Cache != nil {
+			a.Cache.Clear()
+		}
+		return
+	}
+	t, ok := changeEvents[change.Op]
+	if !ok {
+		log.Warn().Msgf("ignoring unknown change %s of cat %s", change.Op, change.ID)
+		return
+	}
+	// changes carry their tenant, but events only do while tenancy is enabled
+	tenant := change.Tenant
+	if !a.Config.Tenancy.Enabled {
+		tenant = ""
+	}
+	if a.Cache != nil {
+		_ = a.Cache.Delete(cacheKey(tenant, change.ID))
+	}
+
+	var cat *model.Cat
+	if t != events.Deleted {
+		b, err := a.storageFor(change.Tenant).Select(change.ID)
+		if err == sql.ErrNoRows {
+			// deleted since, which is announced by its own change
+			return
+		}
+		if err == nil {
+			cat = &model.Cat{}
+			err = json.Unmarshal(b, cat)
+		}
+		if err != nil {
+			log.Error().Msgf("unable to get cat %s changed by another instance: %v", change.ID, err)
+			return
+		}
+	}
+	if a.Events != nil {
+		e := events.New(t, change.ID, cat)
+		e.Tenant = tenant
+		a.Events.Publish(e)
+	}
+}
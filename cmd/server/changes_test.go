@@ -0,0 +1,117 @@
+package server
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/model"
+)
+
+func cachedConfig() conf.Config {
+	config := conf.SaneDefaults()
+	config.Cache = conf.Cache{Size: 1024 * 1024, TTL: time.Minute}
+	return config
+}
+
+func TestApp_FollowChanges(t *testing.T) {
+	const id = "fe271e7e-83ca-477b-92fc-d0c3fa602d7d"
+	tests := []struct {
+		description string
+		// given
+		change model.Change
+		mock   func(s *model.MockStorage)
+		// then
+		expectedType  events.Type
+		expectedCat   bool
+		expectedCache bool
+	}{
+		{
+			description:   "updated by another instance",
+			change:        model.Change{Op: model.ChangeUpdate, ID: id, Origin: "other"},
+			mock:          func(s *model.MockStorage) { s.EXPECT().Select(id).Return([]byte(`{"id":"`+id+`","name":"tom"}`), nil) },
+			expectedType:  events.Updated,
+			expectedCat:   true,
+			expectedCache: false,
+		},
+		{
+			description:  "deleted by another instance",
+			change:       model.Change{Op: model.ChangeDelete, ID: id, Origin: "other"},
+			expectedType: events.Deleted,
+		},
+		{
+			description: "inserted by another instance and deleted since",
+			change:      model.Change{Op: model.ChangeInsert, ID: id, Origin: "other"},
+			mock:        func(s *model.MockStorage) { s.EXPECT().Select(id).Return(nil, sql.ErrNoRows) },
+		},
+		{
+			description: "missed while reconnecting",
+			change:      model.Change{Op: model.ChangeResync},
+		},
+		{
+			description:   "made by this instance",
+			change:        model.Change{Op: model.ChangeUpdate, ID: id, Origin: model.InstanceName()},
+			expectedCache: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(s)
+			}
+			a := App{Storage: s, Config: cachedConfig()}
+			a.BootstrapServer()
+			_ = a.Cache.Set(cacheKey("", id), []byte(`{"id":"`+id+`"}`))
+			received, unsubscribe := a.Events.Subscribe(1)
+			defer unsubscribe()
+
+			changes := make(chan model.Change, 1)
+			changes <- tt.change
+			close(changes)
+			a.FollowChanges(changes)
+
+			select {
+			case e := <-received:
+				if e.Type != tt.expectedType || e.CatID != id || (e.Cat != nil) != tt.expectedCat {
+					t.Errorf("unxpected event: %+v", e)
+				}
+			default:
+				if tt.expectedType != "" {
+					t.Errorf("unxpected missing %s event", tt.expectedType)
+				}
+			}
+			if _, err := a.Cache.Get(cacheKey("", id)); (err == nil) != tt.expectedCache {
+				t.Errorf("unxpected cache: got cached %t, expected %t", err == nil, tt.expectedCache)
+			}
+		})
+	}
+}
+
+func TestApp_GetCat_Cached(t *testing.T) {
+	const id = "fe271e7e-83ca-477b-92fc-d0c3fa602d7d"
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockStorage(ctrl)
+	s.EXPECT().Select(id).Return([]byte(`{"id":"`+id+`","name":"tom"}`), nil).Times(2)
+	s.EXPECT().Delete(id).Return(nil)
+	a := App{Storage: s, Config: cachedConfig()}
+	a.BootstrapServer()
+
+	// the second get is read from the cache, until the cat is deleted
+	for _, method := range []string{http.MethodGet, http.MethodGet, http.MethodDelete, http.MethodGet} {
+		req, _ := http.NewRequest(method, "/cats/v1/cats/"+id, nil)
+		response := httptest.NewRecorder()
+		a.Router.ServeHTTP(response, req)
+		if response.Code != http.StatusOK {
+			t.Errorf("unxpected status code of %s: got %d, expected %d", method, response.Code, http.StatusOK)
+		}
+	}
+}
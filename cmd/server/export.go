@@ -0,0 +1,100 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog/log"
+	"github.com/waikco/cats-v1/bulk"
+	"github.com/waikco/cats-v1/codec"
+	"github.com/waikco/cats-v1/model"
+)
+
+// exportFlushEvery is how many cats are written between flushes of an export.
+const exportFlushEvery = 100
+
+// exportTypes maps the media types an export can be encoded in to their format.
+var exportTypes = map[string]bulk.Format{
+	"application/json":     bulk.JSON,
+	"application/x-ndjson": bulk.NDJSON,
+	"application/ndjson":   bulk.NDJSON,
+	"text/csv":             bulk.CSV,
+}
+
+// exportOffers are the media types of exportTypes in order of preference.
+var exportOffers = []string{"application/json", "application/x-ndjson", "application/ndjson", "text/csv"}
+
+// Export streams every cat matching the filter in the query as a json array, ndjson or csv,
+// picked from the Accept header or the format query parameter.
+func (a *App) Export(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	format, status, err := exportFormat(r)
+	if err != nil {
+		respondWithJson(w, status, Response{
+			Error: Error{
+				Status:  status,
+				Message: err.Error()},
+		})
+		return
+	}
+
+	filter, err := catFilter(r)
+	if err != nil {
+		respondWithJson(w, http.StatusBadRequest, Response{
+			Error: Error{
+				Status:  http.StatusBadRequest,
+				Message: err.Error()},
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Vary", "Accept")
+	writer, err := bulk.NewWriter(w, format)
+	if err != nil {
+		respondWithJson(w, http.StatusInternalServerError, Response{
+			Error: Error{
+				Status:  http.StatusInternalServerError,
+				Message: err.Error()},
+		})
+		return
+	}
+	flusher, _ := w.(http.Flusher)
+
+	n := 0
+	err = model.Each(r.Context(), a.storage(r.Context()), filter, func(cat model.Cat) error {
+		if err := writer.Write(cat); err != nil {
+			return err
+		}
+		if n++; n%exportFlushEvery == 0 && flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		// the status has already been sent, so the only way left to signal the failure
+		// is to abort the response before it is complete
+		log.Error().Msgf("export failed after %d cats: %v", n, err)
+		panic(http.ErrAbortHandler)
+	}
+	log.Debug().Msgf("exported %d cats as %s", n, format)
+}
+
+// exportFormat picks the format of an export, with the format query parameter taking
+// precedence over the Accept header. The returned status is the one to fail the request with.
+func exportFormat(r *http.Request) (bulk.Format, int, error) {
+	if name := r.URL.Query().Get("format"); name != "" {
+		format, err := bulk.ParseFormat(name)
+		return format, http.StatusBadRequest, err
+	}
+	mediaType, ok := codec.Negotiate(r.Header.Get("Accept"), exportOffers...)
+	if !ok {
+		return "", http.StatusNotAcceptable, fmt.Errorf(
+			"unable to export as %s, accepted types are application/json, application/x-ndjson and text/csv",
+			r.Header.Get("Accept"))
+	}
+	return exportTypes[mediaType], http.StatusOK, nil
+}
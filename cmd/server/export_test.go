@@ -0,0 +1,150 @@
+package server
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/model"
+)
+
+func TestApp_Export(t *testing.T) {
+	var a App
+	a.BootstrapServer()
+
+	page := []byte(`[{"id":"1","name":"tom","color":"grey","age":3},{"id":"2","name":"kitty","color":"black","age":9}]`)
+
+	tests := []struct {
+		description string
+		// given
+		url    string
+		accept string
+		// then
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        string
+	}{
+		{
+			description:         "json array without an accept header",
+			url:                 "/cats/v1/export",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/json",
+			expectedBody: "[\n" +
+				`{"id":"1","name":"tom","color":"grey","age":3},` + "\n" +
+				`{"id":"2","name":"kitty","color":"black","age":9}` + "\n]\n",
+		},
+		{
+			description:         "ndjson from the accept header",
+			url:                 "/cats/v1/export",
+			accept:              "application/x-ndjson",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody: `{"id":"1","name":"tom","color":"grey","age":3}` + "\n" +
+				`{"id":"2","name":"kitty","color":"black","age":9}` + "\n",
+		},
+		{
+			description:         "csv preferred by quality",
+			url:                 "/cats/v1/export",
+			accept:              "application/json;q=0.5, text/csv",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "text/csv",
+			expectedBody:        "id,name,color,age,breed,birth_date,sex,neutered,weight_kg,microchip,tags\n1,tom,grey,3,,,,,,,\n2,kitty,black,9,,,,,,,\n",
+		},
+		{
+			description:         "format parameter overrides the accept header",
+			url:                 "/cats/v1/export?format=ndjson",
+			accept:              "text/csv",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody: `{"id":"1","name":"tom","color":"grey","age":3}` + "\n" +
+				`{"id":"2","name":"kitty","color":"black","age":9}` + "\n",
+		},
+		{
+			description:         "filtered by color and age",
+			url:                 "/cats/v1/export?color=BLACK&minAge=5",
+			accept:              "application/ndjson",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/x-ndjson",
+			expectedBody:        `{"id":"2","name":"kitty","color":"black","age":9}` + "\n",
+		},
+		{
+			description:         "unsupported accept header",
+			url:                 "/cats/v1/export",
+			accept:              "application/xml",
+			expectedStatus:      http.StatusNotAcceptable,
+			expectedContentType: "application/json",
+			expectedBody: `{"error":{"status":406,"message":"unable to export as application/xml, ` +
+				`accepted types are application/json, application/x-ndjson and text/csv"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockStorage(ctrl)
+			s.EXPECT().SelectAll(100, 0).Return(page, nil).AnyTimes()
+			a.Storage = s
+
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d", response.Code, tt.expectedStatus)
+			}
+			if ct := response.Header().Get("Content-Type"); ct != tt.expectedContentType {
+				t.Errorf("unxpected content type: got %s, expected %s", ct, tt.expectedContentType)
+			}
+			if body := response.Body.String(); body != tt.expectedBody {
+				t.Errorf("unxpected response body: got %q, expected %q", body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestApp_Export_Empty(t *testing.T) {
+	var a App
+	a.BootstrapServer()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockStorage(ctrl)
+	s.EXPECT().SelectAll(100, 0).Return(nil, sql.ErrNoRows)
+	a.Storage = s
+
+	req, _ := http.NewRequest(http.MethodGet, "/cats/v1/export", nil)
+	response := httptest.NewRecorder()
+	a.Router.ServeHTTP(response, req)
+
+	if response.Code != http.StatusOK {
+		t.Errorf("unxpected status code: got %d, expected %d", response.Code, http.StatusOK)
+	}
+	if body := response.Body.String(); body != "[]\n" {
+		t.Errorf("unxpected response body: got %q, expected %q", body, "[]\n")
+	}
+}
+
+func TestApp_Export_AbortsOnStorageError(t *testing.T) {
+	var a App
+	a.BootstrapServer()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockStorage(ctrl)
+	s.EXPECT().SelectAll(100, 0).Return(nil, errors.New("database error"))
+	a.Storage = s
+
+	defer func() {
+		if r := recover(); r != http.ErrAbortHandler {
+			t.Errorf("unxpected recovered value: got %v, expected %v", r, http.ErrAbortHandler)
+		}
+	}()
+	req, _ := http.NewRequest(http.MethodGet, "/cats/v1/export", nil)
+	a.Router.ServeHTTP(httptest.NewRecorder(), req)
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	json "github.com/json-iterator/go"
+	"github.com/julienschmidt/httprouter"
+	"github.com/waikco/cats-v1/graph"
+)
+
+// GraphQL executes a graphql request, read from the query string of GET requests and the
+// json body of POST requests. Only POST requests may run mutations.
+func (a *App) GraphQL(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	req, err := graphqlRequest(r)
+	if err != nil {
+		respondWithJson(w, http.StatusBadRequest, Response{
+			Error: Error{
+				Status:  http.StatusBadRequest,
+				Message: err.Error()},
+		})
+		return
+	}
+
+	result := a.Graph.Execute(r.Context(), a.storage(r.Context()), req, r.Method == http.MethodPost)
+
+	// fields are resolved into maps, whose keys are sorted to keep responses stable, and
+	// errors in the query are part of the graphql response, which is always a 200
+	body, _ := json.ConfigCompatibleWithStandardLibrary.Marshal(result)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(body)
+}
+
+// graphqlRequest reads the query, variables and operation name of a request.
+func graphqlRequest(r *http.Request) (graph.Request, error) {
+	var req graph.Request
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+		if v := q.Get("variables"); v != "" {
+			if err := json.Unmarshal([]byte(v), &req.Variables); err != nil {
+				return req, fmt.Errorf("invalid variables: %v", err)
+			}
+		}
+	} else {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return req, err
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			return req, fmt.Errorf("invalid graphql request in request body: %v", err)
+		}
+	}
+	if req.Query == "" {
+		return req, fmt.Errorf("query is required")
+	}
+	return req, nil
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/model"
+)
+
+func TestApp_GraphQL(t *testing.T) {
+	const id = "fe271e7e-83ca-477b-92fc-d0c3fa602d7d"
+
+	tests := []struct {
+		description string
+		// given
+		method string
+		url    string
+		body   string
+		mock   func(s *model.MockStorage)
+		// then
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description: "query from the query string",
+			method:      http.MethodGet,
+			url: "/cats/v1/graphql?" + url.Values{
+				"query":     {`query($id: ID!) { cat(id: $id) { name age } }`},
+				"variables": {`{"id":"` + id + `"}`},
+			}.Encode(),
+			mock: func(s *model.MockStorage) {
+				s.EXPECT().Select(id).Return([]byte(`{"name":"tom","color":"grey","age":3}`), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data":{"cat":{"age":3,"name":"tom"}}}`,
+		},
+		{
+			description:    "mutation from the body",
+			method:         http.MethodPost,
+			url:            "/cats/v1/graphql",
+			body:           `{"query":"mutation { deleteCat(id: \"` + id + `\") }"}`,
+			mock:           func(s *model.MockStorage) { s.EXPECT().Delete(id).Return(nil) },
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"data":{"deleteCat":"` + id + `"}}`,
+		},
+		{
+			description:    "mutation from the query string",
+			method:         http.MethodGet,
+			url:            "/cats/v1/graphql?" + url.Values{"query": {`mutation { deleteCat(id: "` + id + `") }`}}.Encode(),
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"data":null,"errors":[{"message":"mutations must be sent with POST","locations":[],` +
+				`"extensions":{"code":"METHOD_NOT_ALLOWED"}}]}`,
+		},
+		{
+			description:    "invalid variables",
+			method:         http.MethodGet,
+			url:            "/cats/v1/graphql?" + url.Values{"query": {`{ cats { nodes { id } } }`}, "variables": {`[`}}.Encode(),
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "missing query",
+			method:         http.MethodPost,
+			url:            "/cats/v1/graphql",
+			body:           `{"variables":{}}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(s)
+			}
+			a := App{Storage: s, Config: conf.SaneDefaults()}
+			a.BootstrapServer()
+
+			req, _ := http.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, tt.expectedStatus, response.Body)
+			}
+			if tt.expectedBody != "" && response.Body.String() != tt.expectedBody {
+				t.Errorf("unxpected response body: got %s, expected %s", response.Body, tt.expectedBody)
+			}
+		})
+	}
+}
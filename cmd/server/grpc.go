@@ -0,0 +1,369 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/healthcheck"
+	"github.com/waikco/cats-v1/model"
+	catsv1 "github.com/waikco/cats-v1/proto/cats/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// BootstrapGRPC creates the grpc server, serving the cats service along with grpc health
+// checking, backed by the readiness checks, and reflection.
+func (a *App) BootstrapGRPC() {
+	var opts []grpc.ServerOption
+	if a.Config.Server.TLS {
+		creds, err := credentials.NewServerTLSFromFile(a.Config.Server.Cert, a.Config.Server.Key)
+		if err != nil {
+			log.Fatal().Msgf("Unable to load cert/key: %s", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+
+	a.GRPCServer = grpc.NewServer(opts...)
+	catsv1.RegisterCatsServiceServer(a.GRPCServer, &catsService{app: a})
+	grpc_health_v1.RegisterHealthServer(a.GRPCServer, &healthService{checks: a.Checks, interval: a.Config.Health.CacheTTL})
+	reflection.Register(a.GRPCServer)
+	log.Info().Msgf("initialized grpc server to listen on: :%s", a.Config.Server.GRPCPort)
+}
+
+// catsService implements the grpc cats service on the app's storage.
+type catsService struct {
+	catsv1.UnimplementedCatsServiceServer
+	app *App
+}
+
+// tenant scopes a call to its tenant when tenancy is enabled, resolved from the
+// authorization and tenant header metadata the same way as for the rest api.
+func (s *catsService) tenant(ctx context.Context) (context.Context, error) {
+	if !s.app.Config.Tenancy.Enabled {
+		return ctx, nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	first := func(key string) string {
+		if values := md.Get(key); len(values) > 0 {
+			return values[0]
+		}
+		return ""
+	}
+	tenant, err := s.app.resolveTenant(first("authorization"), first(s.app.tenantHeader()))
+	switch err {
+	case nil:
+		return withTenant(ctx, tenant), nil
+	case errNoTenant, errUnknownTenant:
+		return ctx, status.Error(codes.Unauthenticated, err.Error())
+	default:
+		log.Error().Msgf("unable to resolve the tenant of a call: %v", err)
+		return ctx, status.Error(codes.Internal, "unable to resolve tenant")
+	}
+}
+
+func (s *catsService) GetCat(ctx context.Context, req *catsv1.GetCatRequest) (*catsv1.Cat, error) {
+	ctx, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateID(req.GetId()); err != nil {
+		return nil, err
+	}
+	b, err := s.app.storage(ctx).Select(req.GetId())
+	if err != nil {
+		return nil, storageError(err, "error getting cat")
+	}
+	var cat model.Cat
+	if err := json.Unmarshal(b, &cat); err != nil {
+		return nil, storageError(err, "error getting cat")
+	}
+	cat.ID = req.GetId()
+	return toProto(cat), nil
+}
+
+func (s *catsService) ListCats(req *catsv1.ListCatsRequest, stream catsv1.CatsService_ListCatsServer) error {
+	ctx, err := s.tenant(stream.Context())
+	if err != nil {
+		return err
+	}
+	filter := model.Filter{
+		Name:      req.GetName(),
+		Color:     req.GetColor(),
+		Breed:     req.GetBreed(),
+		Sex:       req.GetSex(),
+		Neutered:  req.Neutered,
+		Microchip: req.GetMicrochip(),
+		Tags:      req.GetTags(),
+		Status:    req.GetStatus(),
+	}
+	if req.MinAge != nil {
+		minAge := int(req.GetMinAge())
+		filter.MinAge = &minAge
+	}
+	if req.MaxAge != nil {
+		maxAge := int(req.GetMaxAge())
+		filter.MaxAge = &maxAge
+	}
+
+	err = model.Each(ctx, s.app.storage(ctx), filter, func(cat model.Cat) error {
+		return stream.Send(toProto(cat))
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		return storageError(err, "error listing cats")
+	}
+	return nil
+}
+
+func (s *catsService) CreateCat(ctx context.Context, req *catsv1.CreateCatRequest) (*catsv1.Cat, error) {
+	ctx, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cat := fromProto(req.GetCat())
+	if err := s.validateCat(&cat); err != nil {
+		return nil, err
+	}
+	body, _ := json.Marshal(cat)
+	id, err := s.app.storage(ctx).Insert(body)
+	if err != nil {
+		return nil, storageError(err, "error storing cat")
+	}
+	cat.ID = id
+	s.app.publish(ctx, events.Created, id, &cat)
+	return toProto(cat), nil
+}
+
+func (s *catsService) UpdateCat(ctx context.Context, req *catsv1.UpdateCatRequest) (*catsv1.Cat, error) {
+	ctx, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateID(req.GetId()); err != nil {
+		return nil, err
+	}
+	cat := fromProto(req.GetCat())
+	if err := s.validateCat(&cat); err != nil {
+		return nil, err
+	}
+	body, _ := json.Marshal(cat)
+	if err := s.app.storage(ctx).Update(req.GetId(), body); err != nil {
+		return nil, storageError(err, "error storing cat")
+	}
+	cat.ID = req.GetId()
+	s.app.publish(ctx, events.Updated, cat.ID, &cat)
+	return toProto(cat), nil
+}
+
+func (s *catsService) DeleteCat(ctx context.Context, req *catsv1.DeleteCatRequest) (*catsv1.DeleteCatResponse, error) {
+	ctx, err := s.tenant(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateID(req.GetId()); err != nil {
+		return nil, err
+	}
+	if err := s.app.storage(ctx).Delete(req.GetId()); err != nil {
+		return nil, storageError(err, "error deleting cat")
+	}
+	s.app.publish(ctx, events.Deleted, req.GetId(), nil)
+	return &catsv1.DeleteCatResponse{}, nil
+}
+
+func (s *catsService) WatchCats(req *catsv1.WatchCatsRequest, stream catsv1.CatsService_WatchCatsServer) error {
+	ctx, err := s.tenant(stream.Context())
+	if err != nil {
+		return err
+	}
+	tenant := tenantFrom(ctx)
+	ch, cancel := s.app.Events.Subscribe(events.DefaultBuffer)
+	defer cancel()
+	// headers tell clients the watch has started, so no later change is missed
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case e := <-ch:
+			if e.Tenant != tenant {
+				continue
+			}
+			if err := stream.Send(eventToProto(e)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func validateID(id string) error {
+	if uuid.FromStringOrNil(id) == uuid.Nil {
+		return status.Errorf(codes.InvalidArgument, "invalid cat id: %s", id)
+	}
+	return nil
+}
+
+// validateCat normalises and checks a cat the same way the rest api does, listing every
+// invalid field in a BadRequest detail.
+func (s *catsService) validateCat(cat *model.Cat) error {
+	err := s.app.prepareCat(cat, time.Now())
+	if err == nil {
+		return nil
+	}
+	st := status.New(codes.InvalidArgument, err.Error())
+	if fields, ok := err.(model.ValidationError); ok {
+		br := &errdetails.BadRequest{}
+		for _, f := range fields {
+			br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       f.Field,
+				Description: f.Reason,
+			})
+		}
+		if detailed, derr := st.WithDetails(br); derr == nil {
+			st = detailed
+		}
+	}
+	return st.Err()
+}
+
+// storageError maps a storage error onto a grpc status.
+func storageError(err error, message string) error {
+	var quota model.QuotaError
+	switch {
+	case errors.As(err, &quota):
+		return status.Error(codes.ResourceExhausted, quota.Error())
+	case err == sql.ErrNoRows:
+		return status.Error(codes.NotFound, "cat not found")
+	case err == model.ErrMicrochipExists:
+		return status.Error(codes.AlreadyExists, err.Error())
+	case err == context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	case err == context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	default:
+		log.Debug().Msgf("%s: %v", message, err)
+		return status.Error(codes.Internal, message)
+	}
+}
+
+func toProto(c model.Cat) *catsv1.Cat {
+	return &catsv1.Cat{
+		Id:        c.ID,
+		Name:      c.Name,
+		Color:     c.Color,
+		Age:       int32(c.Age),
+		Breed:     c.Breed,
+		BirthDate: c.BirthDate,
+		Sex:       c.Sex,
+		Neutered:  c.Neutered,
+		WeightKg:  c.WeightKg,
+		Microchip: c.Microchip,
+		Tags:      c.Tags,
+		Status:    c.Status,
+	}
+}
+
+func fromProto(c *catsv1.Cat) model.Cat {
+	return model.Cat{
+		Name:      c.GetName(),
+		Color:     c.GetColor(),
+		Age:       int(c.GetAge()),
+		Breed:     c.GetBreed(),
+		BirthDate: c.GetBirthDate(),
+		Sex:       c.GetSex(),
+		Neutered:  c.Neutered,
+		WeightKg:  c.GetWeightKg(),
+		Microchip: c.GetMicrochip(),
+		Tags:      c.GetTags(),
+	}
+}
+
+var eventTypes = map[events.Type]catsv1.CatEvent_Type{
+	events.Created: catsv1.CatEvent_TYPE_CREATED,
+	events.Updated: catsv1.CatEvent_TYPE_UPDATED,
+	events.Deleted: catsv1.CatEvent_TYPE_DELETED,
+}
+
+func eventToProto(e events.Event) *catsv1.CatEvent {
+	pe := &catsv1.CatEvent{
+		Id:    e.ID,
+		Type:  eventTypes[e.Type],
+		CatId: e.CatID,
+		Time:  timestamppb.New(e.Time),
+	}
+	if e.Cat != nil {
+		pe.Cat = toProto(*e.Cat)
+	}
+	return pe
+}
+
+// healthService implements grpc health checking with the readiness checks, reporting
+// the same status for the server as a whole and for the cats service.
+type healthService struct {
+	grpc_health_v1.UnimplementedHealthServer
+	checks   *healthcheck.Registry
+	interval time.Duration
+}
+
+func (h *healthService) Check(ctx context.Context, req *grpc_health_v1.HealthCheckRequest) (*grpc_health_v1.HealthCheckResponse, error) {
+	if !h.known(req.GetService()) {
+		return nil, status.Errorf(codes.NotFound, "unknown service %s", req.GetService())
+	}
+	return &grpc_health_v1.HealthCheckResponse{Status: h.status(ctx)}, nil
+}
+
+// Watch sends the serving status whenever it changes, running the checks every interval.
+func (h *healthService) Watch(req *grpc_health_v1.HealthCheckRequest, stream grpc_health_v1.Health_WatchServer) error {
+	if !h.known(req.GetService()) {
+		return stream.Send(&grpc_health_v1.HealthCheckResponse{Status: grpc_health_v1.HealthCheckResponse_SERVICE_UNKNOWN})
+	}
+	interval := h.interval
+	if interval <= 0 {
+		interval = healthcheck.DefaultCacheTTL
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := grpc_health_v1.HealthCheckResponse_UNKNOWN
+	for {
+		if current := h.status(stream.Context()); current != last {
+			if err := stream.Send(&grpc_health_v1.HealthCheckResponse{Status: current}); err != nil {
+				return err
+			}
+			last = current
+		}
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *healthService) known(service string) bool {
+	return service == "" || service == catsv1.CatsService_ServiceDesc.ServiceName
+}
+
+func (h *healthService) status(ctx context.Context) grpc_health_v1.HealthCheckResponse_ServingStatus {
+	if h.checks.Run(ctx).Healthy() {
+		return grpc_health_v1.HealthCheckResponse_SERVING
+	}
+	return grpc_health_v1.HealthCheckResponse_NOT_SERVING
+}
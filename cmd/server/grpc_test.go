@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/model"
+	catsv1 "github.com/waikco/cats-v1/proto/cats/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const catID = "fe271e7e-83ca-477b-92fc-d0c3fa602d7d"
+
+// grpcTestApp serves the grpc api of an app over an in memory connection.
+func grpcTestApp(t *testing.T, s model.Storage) (*App, *grpc.ClientConn) {
+	a := &App{Config: conf.SaneDefaults(), Storage: s}
+	a.BootstrapServer()
+	a.BootstrapGRPC()
+
+	lis := bufconn.Listen(1 << 20)
+	go func() { _ = a.GRPCServer.Serve(lis) }()
+	t.Cleanup(a.GRPCServer.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return a, conn
+}
+
+func TestGRPC_GetCat(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		id           string
+		mockResponse []byte
+		mockErr      error
+		mockCalls    int
+		// then
+		expectedCode codes.Code
+		expectedCat  *catsv1.Cat
+	}{
+		{
+			description:  "cat found",
+			id:           catID,
+			mockResponse: []byte(`{"name":"cat-1","color":"color-1","age":1}`),
+			mockCalls:    1,
+			expectedCode: codes.OK,
+			expectedCat:  &catsv1.Cat{Id: catID, Name: "cat-1", Color: "color-1", Age: 1},
+		},
+		{
+			description:  "cat not found",
+			id:           catID,
+			mockErr:      sql.ErrNoRows,
+			mockCalls:    1,
+			expectedCode: codes.NotFound,
+		},
+		{
+			description:  "invalid id",
+			id:           "cat-1",
+			expectedCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockStorage(ctrl)
+			s.EXPECT().Select(tt.id).Return(tt.mockResponse, tt.mockErr).Times(tt.mockCalls)
+			_, conn := grpcTestApp(t, s)
+
+			cat, err := catsv1.NewCatsServiceClient(conn).GetCat(context.Background(), &catsv1.GetCatRequest{Id: tt.id})
+			if code := status.Code(err); code != tt.expectedCode {
+				t.Fatalf("unxpected status code: got %s, expected %s", code, tt.expectedCode)
+			}
+			if tt.expectedCat != nil && (cat.Id != tt.expectedCat.Id || cat.Name != tt.expectedCat.Name ||
+				cat.Color != tt.expectedCat.Color || cat.Age != tt.expectedCat.Age) {
+				t.Errorf("unxpected cat: got %v, expected %v", cat, tt.expectedCat)
+			}
+		})
+	}
+}
+
+func TestGRPC_ListCats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockStorage(ctrl)
+	s.EXPECT().SelectAll(100, 0).
+		Return([]byte(`[{"id":"1","name":"tom","color":"grey","age":3},{"id":"2","name":"kitty","color":"black","age":9}]`), nil)
+	_, conn := grpcTestApp(t, s)
+
+	minAge := int32(5)
+	stream, err := catsv1.NewCatsServiceClient(conn).ListCats(context.Background(), &catsv1.ListCatsRequest{MinAge: &minAge})
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	var ids []string
+	for {
+		cat, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("unxpected error: %v", err)
+		}
+		ids = append(ids, cat.Id)
+	}
+	if len(ids) != 1 || ids[0] != "2" {
+		t.Errorf("unxpected cats: got %v, expected [2]", ids)
+	}
+}
+
+func TestGRPC_CreateCat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockStorage(ctrl)
+	s.EXPECT().Insert([]byte(`{"name":"cat-1","color":"color-1","age":1}`)).Return(catID, nil)
+	_, conn := grpcTestApp(t, s)
+	client := catsv1.NewCatsServiceClient(conn)
+
+	cat, err := client.CreateCat(context.Background(), &catsv1.CreateCatRequest{
+		Cat: &catsv1.Cat{Name: "cat-1", Color: "color-1", Age: 1},
+	})
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	if cat.Id != catID {
+		t.Errorf("unxpected id: got %s, expected %s", cat.Id, catID)
+	}
+
+	_, err = client.CreateCat(context.Background(), &catsv1.CreateCatRequest{Cat: &catsv1.Cat{Age: -1}})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("unxpected status code: got %s, expected %s", st.Code(), codes.InvalidArgument)
+	}
+	var fields []string
+	for _, d := range st.Details() {
+		if br, ok := d.(*errdetails.BadRequest); ok {
+			for _, v := range br.FieldViolations {
+				fields = append(fields, v.Field)
+			}
+		}
+	}
+	if len(fields) != 3 {
+		t.Errorf("unxpected field violations: got %v, expected name, color and age", fields)
+	}
+
+	s.EXPECT().Insert([]byte(`{"name":"cat-2","color":"color-2","microchip":"985112345678901"}`)).
+		Return("", model.ErrMicrochipExists)
+	_, err = client.CreateCat(context.Background(), &catsv1.CreateCatRequest{
+		Cat: &catsv1.Cat{Name: "cat-2", Color: "color-2", Microchip: "985112345678901"},
+	})
+	if code := status.Code(err); code != codes.AlreadyExists {
+		t.Errorf("unxpected status code: got %s, expected %s", code, codes.AlreadyExists)
+	}
+}
+
+func TestGRPC_WatchCats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockStorage(ctrl)
+	s.EXPECT().Delete(catID).Return(nil)
+	_, conn := grpcTestApp(t, s)
+	client := catsv1.NewCatsServiceClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := client.WatchCats(ctx, &catsv1.WatchCatsRequest{})
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	// headers are sent once the watch has subscribed to changes
+	if _, err := stream.Header(); err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+
+	if _, err := client.DeleteCat(ctx, &catsv1.DeleteCatRequest{Id: catID}); err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	e, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	if e.Type != catsv1.CatEvent_TYPE_DELETED || e.CatId != catID || e.Cat != nil {
+		t.Errorf("unxpected event: %v", e)
+	}
+}
+
+func TestGRPC_Health(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockStorage(ctrl)
+	s.EXPECT().Status().Return(nil)
+	_, conn := grpcTestApp(t, s)
+	client := grpc_health_v1.NewHealthClient(conn)
+
+	resp, err := client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "cats.v1.CatsService"})
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	if resp.Status != grpc_health_v1.HealthCheckResponse_SERVING {
+		t.Errorf("unxpected status: got %s, expected %s", resp.Status, grpc_health_v1.HealthCheckResponse_SERVING)
+	}
+
+	_, err = client.Check(context.Background(), &grpc_health_v1.HealthCheckRequest{Service: "unknown"})
+	if code := status.Code(err); code != codes.NotFound {
+		t.Errorf("unxpected status code: got %s, expected %s", code, codes.NotFound)
+	}
+}
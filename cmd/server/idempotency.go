@@ -0,0 +1,134 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// IdempotencyKeyHeader carries the key a client retries a request with.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader marks responses replayed for a retried request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+
+	DefaultIdempotencyTTL         = 24 * time.Hour
+	DefaultIdempotencyLockTimeout = time.Minute
+
+	maxIdempotencyKeyLength = 255
+	// idempotencyExpiryInterval is how often expired idempotency keys are deleted.
+	idempotencyExpiryInterval = time.Hour
+)
+
+// idempotent makes requests carrying an Idempotency-Key header safe to retry. The response
+// to the first request made with a key is stored and replayed to any retry with the same
+// method, path and body, while a retry with another request gets a 422, and one made while
+// the first is still in progress a 409. Server errors are not stored, so the request may
+// be retried.
+func (a *App) idempotent(next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next(w, r, ps)
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			respondError(w, r, http.StatusBadRequest, "%s must be at most %d characters", IdempotencyKeyHeader, maxIdempotencyKeyLength)
+			return
+		}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			respondError(w, r, http.StatusInternalServerError, "error reading body")
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+		// keys are scoped to the endpoint they are used with, and to the tenant using them
+		key = r.Method + " " + r.URL.Path + " " + key
+		if tenant := tenantFrom(r.Context()); tenant != "" {
+			key = tenant + " " + key
+		}
+		fingerprint := requestFingerprint(r, body)
+		ttl, lockTimeout := a.Config.Idempotency.TTL, a.Config.Idempotency.LockTimeout
+		if ttl <= 0 {
+			ttl = DefaultIdempotencyTTL
+		}
+		if lockTimeout <= 0 {
+			lockTimeout = DefaultIdempotencyLockTimeout
+		}
+
+		now := time.Now()
+		record, reserved, err := a.Idempotency.ReserveIdempotencyKey(key, fingerprint, now, now.Add(lockTimeout))
+		if err != nil {
+			log.Error().Msgf("unable to reserve idempotency key %s: %v", key, err)
+			respondError(w, r, http.StatusInternalServerError, "unable to check %s", IdempotencyKeyHeader)
+			return
+		}
+		if !reserved {
+			switch {
+			case record.Fingerprint != fingerprint:
+				respondError(w, r, http.StatusUnprocessableEntity, "%s was already used with another request", IdempotencyKeyHeader)
+			case !record.Completed:
+				w.Header().Set("Retry-After", "1")
+				respondError(w, r, http.StatusConflict, "a request with the same %s is in progress", IdempotencyKeyHeader)
+			default:
+				if record.ContentType != "" {
+					w.Header().Set("Content-Type", record.ContentType)
+				}
+				w.Header().Set(IdempotentReplayedHeader, "true")
+				w.WriteHeader(record.Status)
+				_, _ = w.Write(record.Body)
+			}
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(recorder, r, ps)
+		if recorder.status >= http.StatusInternalServerError {
+			err = a.Idempotency.ReleaseIdempotencyKey(key)
+		} else {
+			record.Status = recorder.status
+			record.ContentType = w.Header().Get("Content-Type")
+			record.Body = recorder.body.Bytes()
+			record.ExpiresAt = time.Now().Add(ttl)
+			err = a.Idempotency.CompleteIdempotencyKey(record)
+		}
+		if err != nil {
+			log.Error().Msgf("unable to store the response for idempotency key %s: %v", key, err)
+		}
+		w.WriteHeader(recorder.status)
+		_, _ = w.Write(recorder.body.Bytes())
+	}
+}
+
+// requestFingerprint identifies a request by its body and the media type it is encoded in.
+func requestFingerprint(r *http.Request, body []byte) string {
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n", r.Header.Get("Content-Type"))
+	_, _ = h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// ExpireIdempotencyKeys deletes expired idempotency keys every hour until the context is done.
+func (a *App) ExpireIdempotencyKeys(ctx context.Context) {
+	ticker := time.NewTicker(idempotencyExpiryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := a.Idempotency.DeleteExpiredIdempotencyKeys(time.Now()); err != nil {
+				log.Error().Msgf("unable to delete expired idempotency keys: %v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/model"
+)
+
+const idempotentID = "fe271e7e-83ca-477b-92fc-d0c3fa602d7d"
+
+func postWithKey(a *App, url, key, body string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(http.MethodPost, url, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	response := httptest.NewRecorder()
+	a.Router.ServeHTTP(response, req)
+	return response
+}
+
+func TestApp_Idempotency(t *testing.T) {
+	const (
+		tom   = `{"name":"tom","color":"grey","age":2}`
+		kitty = `{"name":"kitty","color":"black","age":1}`
+	)
+	created := `{"result":"` + idempotentID + `","error":{}}`
+
+	tests := []struct {
+		description string
+		// given
+		mock     func(s *model.MockStorage)
+		requests [][2]string
+		// then
+		expectedStatus   []int
+		expectedBody     []string
+		expectedReplayed []bool
+	}{
+		{
+			description: "retry replays the response",
+			mock: func(s *model.MockStorage) {
+				s.EXPECT().Insert(gomock.Any()).Return(idempotentID, nil)
+			},
+			requests:         [][2]string{{"k1", tom}, {"k1", tom}},
+			expectedStatus:   []int{http.StatusCreated, http.StatusCreated},
+			expectedBody:     []string{created, created},
+			expectedReplayed: []bool{false, true},
+		},
+		{
+			description: "reuse with another body",
+			mock: func(s *model.MockStorage) {
+				s.EXPECT().Insert(gomock.Any()).Return(idempotentID, nil)
+			},
+			requests:         [][2]string{{"k1", tom}, {"k1", kitty}},
+			expectedStatus:   []int{http.StatusCreated, http.StatusUnprocessableEntity},
+			expectedReplayed: []bool{false, false},
+		},
+		{
+			description: "retry after a server error",
+			mock: func(s *model.MockStorage) {
+				gomock.InOrder(
+					s.EXPECT().Insert(gomock.Any()).Return("", errors.New("connection reset")),
+					s.EXPECT().Insert(gomock.Any()).Return(idempotentID, nil),
+				)
+			},
+			requests:         [][2]string{{"k1", tom}, {"k1", tom}},
+			expectedStatus:   []int{http.StatusInternalServerError, http.StatusCreated},
+			expectedReplayed: []bool{false, false},
+		},
+		{
+			description: "without a key",
+			mock: func(s *model.MockStorage) {
+				s.EXPECT().Insert(gomock.Any()).Return(idempotentID, nil).Times(2)
+			},
+			requests:         [][2]string{{"", tom}, {"", tom}},
+			expectedStatus:   []int{http.StatusCreated, http.StatusCreated},
+			expectedReplayed: []bool{false, false},
+		},
+		{
+			description:      "key too long",
+			requests:         [][2]string{{strings.Repeat("k", 256), tom}},
+			expectedStatus:   []int{http.StatusBadRequest},
+			expectedReplayed: []bool{false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(s)
+			}
+			a := App{Storage: s, Config: conf.SaneDefaults()}
+			a.BootstrapServer()
+
+			for i, req := range tt.requests {
+				response := postWithKey(&a, "/cats/v1/", req[0], req[1])
+				if response.Code != tt.expectedStatus[i] {
+					t.Errorf("unxpected status code of request %d: got %d, expected %d: %s", i, response.Code, tt.expectedStatus[i], response.Body)
+				}
+				if tt.expectedBody != nil && response.Body.String() != tt.expectedBody[i] {
+					t.Errorf("unxpected response body of request %d: got %s, expected %s", i, response.Body, tt.expectedBody[i])
+				}
+				if replayed := response.Header().Get(IdempotentReplayedHeader) == "true"; replayed != tt.expectedReplayed[i] {
+					t.Errorf("unxpected replay of request %d: got %t, expected %t", i, replayed, tt.expectedReplayed[i])
+				}
+			}
+		})
+	}
+}
+
+func TestApp_Idempotency_Concurrent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	inserting, done := make(chan struct{}), make(chan struct{})
+	s := model.NewMockStorage(ctrl)
+	s.EXPECT().Insert(gomock.Any()).DoAndReturn(func(b []byte) (string, error) {
+		close(inserting)
+		<-done
+		return idempotentID, nil
+	})
+	a := App{Storage: s, Config: conf.SaneDefaults()}
+	a.BootstrapServer()
+
+	const body = `{"name":"tom","color":"grey","age":2}`
+	var wg sync.WaitGroup
+	var first *httptest.ResponseRecorder
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		first = postWithKey(&a, "/cats/v1/", "k1", body)
+	}()
+
+	<-inserting
+	if response := postWithKey(&a, "/cats/v1/", "k1", body); response.Code != http.StatusConflict {
+		t.Errorf("unxpected status code while in progress: got %d, expected %d", response.Code, http.StatusConflict)
+	}
+	close(done)
+	wg.Wait()
+	if first.Code != http.StatusCreated {
+		t.Errorf("unxpected status code: got %d, expected %d", first.Code, http.StatusCreated)
+	}
+	if response := postWithKey(&a, "/cats/v1/", "k1", body); response.Code != http.StatusCreated {
+		t.Errorf("unxpected status code once completed: got %d, expected %d", response.Code, http.StatusCreated)
+	}
+}
+
+func TestApp_MassCreateCat(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		body string
+		mock func(s *model.MockStorage)
+		// then
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description: "created in order",
+			body:        `[{"name":"tom","color":"grey","age":2},{"name":"kitty","color":"black","age":1}]`,
+			mock: func(s *model.MockStorage) {
+				gomock.InOrder(
+					s.EXPECT().Insert([]byte(`{"name":"tom","color":"grey","age":2}`)).Return("1", nil),
+					s.EXPECT().Insert([]byte(`{"name":"kitty","color":"black","age":1}`)).Return("2", nil),
+				)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"result":["1","2"],"error":{}}`,
+		},
+		{
+			description:    "empty",
+			body:           `[]`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "invalid cat",
+			body:           `[{"name":"tom","color":"grey","age":2},{"name":"","color":"black","age":1}]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"status":400,"message":"cat 1: invalid cat: name is required"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(s)
+			}
+			a := App{Storage: s, Config: conf.SaneDefaults()}
+			a.BootstrapServer()
+
+			response := postWithKey(&a, "/cats/v1/bulkcatadd", "", tt.body)
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, tt.expectedStatus, response.Body)
+			}
+			if tt.expectedBody != "" && response.Body.String() != tt.expectedBody {
+				t.Errorf("unxpected response body: got %s, expected %s", response.Body, tt.expectedBody)
+			}
+		})
+	}
+}
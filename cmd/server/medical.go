@@ -0,0 +1,192 @@
+package server
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+	"github.com/waikco/cats-v1/model"
+)
+
+const (
+	// defaultDueDays is how many days ahead vaccinations are listed as upcoming by default.
+	defaultDueDays = 30
+	// maxDueDays is the furthest ahead vaccinations may be listed as upcoming.
+	maxDueDays = 365
+)
+
+// medicalStore returns the storage of the medical records of the tenant of a request.
+func (a *App) medicalStore(w http.ResponseWriter, r *http.Request) (model.MedicalStore, bool) {
+	return optional[model.MedicalStore](w, r, a.storage(r.Context()), "medical records")
+}
+
+// CreateMedicalRecord records a vaccination, treatment or weigh-in of a cat.
+func (a *App) CreateMedicalRecord(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.medicalStore(w, r)
+	if !ok {
+		return
+	}
+	catID := ps.ByName("id")
+	if !a.catExists(w, r, catID) {
+		return
+	}
+
+	var record model.MedicalRecord
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		_, err = decodeBody(r, body, &record)
+	}
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "invalid medical record in request body")
+		return
+	}
+	record.Kind = strings.ToLower(strings.TrimSpace(record.Kind))
+	record.Name = strings.TrimSpace(record.Name)
+	if invalidBody(w, r, record.Validate()) {
+		return
+	}
+	record.ID, record.CatID = "", catID
+
+	id, err := store.InsertMedicalRecord(record)
+	if err == nil {
+		record, err = store.SelectMedicalRecord(catID, id)
+	}
+	switch err {
+	case nil:
+		respond(w, r, http.StatusCreated, record)
+	case sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "cat id %s not found", catID)
+	default:
+		log.Error().Msgf("error storing medical record of cat %s: %v", catID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to store medical record")
+	}
+}
+
+// GetMedicalRecords lists the medical records of a cat, latest first, optionally of a
+// single kind.
+func (a *App) GetMedicalRecords(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.medicalStore(w, r)
+	if !ok {
+		return
+	}
+	kind := r.FormValue("kind")
+	switch kind {
+	case "", model.KindVaccination, model.KindTreatment, model.KindWeighIn:
+	default:
+		respondError(w, r, http.StatusBadRequest, "invalid kind %q, expected vaccination, treatment or weigh-in", kind)
+		return
+	}
+	catID := ps.ByName("id")
+	if !a.catExists(w, r, catID) {
+		return
+	}
+
+	records, err := store.SelectMedicalRecords(catID, kind)
+	if err != nil {
+		log.Error().Msgf("error getting medical records of cat %s: %v", catID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get medical records")
+		return
+	}
+	respond(w, r, http.StatusOK, records)
+}
+
+// GetMedicalRecord retrieves a medical record of a cat.
+func (a *App) GetMedicalRecord(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.medicalStore(w, r)
+	if !ok {
+		return
+	}
+	if record, ok := selectMedicalRecord(w, r, store, ps.ByName("id"), ps.ByName("recordId")); ok {
+		respond(w, r, http.StatusOK, record)
+	}
+}
+
+// DeleteMedicalRecord deletes a medical record of a cat.
+func (a *App) DeleteMedicalRecord(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.medicalStore(w, r)
+	if !ok {
+		return
+	}
+	catID, id := ps.ByName("id"), ps.ByName("recordId")
+	if uuid.FromStringOrNil(catID) == uuid.Nil || uuid.FromStringOrNil(id) == uuid.Nil {
+		respondError(w, r, http.StatusBadRequest, "invalid cat id %s or medical record id %s", catID, id)
+		return
+	}
+	switch err := store.DeleteMedicalRecord(catID, id); err {
+	case nil:
+		respond(w, r, http.StatusOK, Response{Result: "success"})
+	case sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "medical record id %s of cat id %s not found", id, catID)
+	default:
+		log.Error().Msgf("error deleting medical record %s of cat %s: %v", id, catID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to delete medical record")
+	}
+}
+
+// GetDueVaccinations lists the vaccinations of every cat that are overdue or due within
+// the next days, soonest first. The status parameter narrows them to either overdue or
+// upcoming ones.
+func (a *App) GetDueVaccinations(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.medicalStore(w, r)
+	if !ok {
+		return
+	}
+	days := defaultDueDays
+	if s := r.FormValue("days"); s != "" {
+		var err error
+		if days, err = strconv.Atoi(s); err != nil || days < 0 || days > maxDueDays {
+			respondError(w, r, http.StatusBadRequest, "invalid days %q, expected a number from 0 to %d", s, maxDueDays)
+			return
+		}
+	}
+	status := r.FormValue("status")
+	switch status {
+	case "", "overdue", "upcoming":
+	default:
+		respondError(w, r, http.StatusBadRequest, "invalid status %q, expected overdue or upcoming", status)
+		return
+	}
+
+	due, err := store.SelectDueVaccinations(time.Now().AddDate(0, 0, days))
+	if err != nil {
+		log.Error().Msgf("error getting due vaccinations: %v", err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get due vaccinations")
+		return
+	}
+	if status != "" {
+		filtered := []model.DueVaccination{}
+		for _, d := range due {
+			if d.Overdue == (status == "overdue") {
+				filtered = append(filtered, d)
+			}
+		}
+		due = filtered
+	}
+	respond(w, r, http.StatusOK, due)
+}
+
+// selectMedicalRecord selects a medical record of a cat, responding with a 400 for invalid
+// ids and a 404 for unknown ones.
+func selectMedicalRecord(w http.ResponseWriter, r *http.Request, store model.MedicalStore, catID, id string) (model.MedicalRecord, bool) {
+	if uuid.FromStringOrNil(catID) == uuid.Nil || uuid.FromStringOrNil(id) == uuid.Nil {
+		respondError(w, r, http.StatusBadRequest, "invalid cat id %s or medical record id %s", catID, id)
+		return model.MedicalRecord{}, false
+	}
+	record, err := store.SelectMedicalRecord(catID, id)
+	switch err {
+	case nil:
+		return record, true
+	case sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "medical record id %s of cat id %s not found", id, catID)
+	default:
+		log.Error().Msgf("error getting medical record %s of cat %s: %v", id, catID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get medical record")
+	}
+	return model.MedicalRecord{}, false
+}
@@ -0,0 +1,193 @@
+package server
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/model"
+)
+
+// medicalStorage is storage able to persist medical records.
+type medicalStorage struct {
+	*model.MockStorage
+	*model.MockMedicalStore
+}
+
+func TestApp_Medical(t *testing.T) {
+	const recordID = "5b0e7c4a-2f1d-4e8b-9a6c-3d7f1e2b8c4a"
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	vaccination := model.MedicalRecord{ID: recordID, CatID: catID, Kind: model.KindVaccination, Name: "rabies",
+		Date: "2020-01-02", DueDate: "2021-01-02", CreatedAt: created}
+	recordJSON := `{"id":"` + recordID + `","kind":"vaccination","name":"rabies","date":"2020-01-02","dueDate":"2021-01-02",` +
+		`"createdAt":"2020-01-02T03:04:05Z"}`
+	due := []model.DueVaccination{
+		{CatID: catID, CatName: "tom", Vaccine: "rabies", LastDate: "2020-01-02", DueDate: "2021-01-02", Overdue: true},
+		{CatID: catID, CatName: "tom", Vaccine: "fvrcp", LastDate: "2020-06-01", DueDate: "2099-06-01"},
+	}
+
+	tests := []struct {
+		description string
+		// given
+		method string
+		url    string
+		body   string
+		mock   func(s *model.MockMedicalStore, storage *model.MockStorage)
+		// then
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description: "create",
+			method:      http.MethodPost,
+			url:         "/cats/v1/cats/" + catID + "/medical",
+			body:        `{"kind":"vaccination","name":" rabies ","date":"2020-01-02","dueDate":"2021-01-02"}`,
+			mock: func(s *model.MockMedicalStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom","color":"grey"}`), nil)
+				s.EXPECT().InsertMedicalRecord(model.MedicalRecord{CatID: catID, Kind: model.KindVaccination, Name: "rabies",
+					Date: "2020-01-02", DueDate: "2021-01-02"}).Return(recordID, nil)
+				s.EXPECT().SelectMedicalRecord(catID, recordID).Return(vaccination, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   recordJSON,
+		},
+		{
+			description: "create an invalid weigh-in",
+			method:      http.MethodPost,
+			url:         "/cats/v1/cats/" + catID + "/medical",
+			body:        `{"kind":"weigh-in","date":"2020-01-02","dueDate":"2021-01-02"}`,
+			mock: func(s *model.MockMedicalStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom","color":"grey"}`), nil)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"error":{"status":400,"message":"invalid medical record: weightKg must be more than 0 and at most 30, ` +
+				`dueDate is only recorded by vaccinations and treatments","details":[` +
+				`{"location":"body","field":"weightKg","reason":"must be more than 0 and at most 30"},` +
+				`{"location":"body","field":"dueDate","reason":"is only recorded by vaccinations and treatments"}]}}`,
+		},
+		{
+			description: "create for an unknown cat",
+			method:      http.MethodPost,
+			url:         "/cats/v1/cats/" + catID + "/medical",
+			body:        `{"kind":"weigh-in","date":"2020-01-02","weightKg":4.2}`,
+			mock: func(s *model.MockMedicalStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			description: "list of a kind",
+			method:      http.MethodGet,
+			url:         "/cats/v1/cats/" + catID + "/medical?kind=vaccination",
+			mock: func(s *model.MockMedicalStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom","color":"grey"}`), nil)
+				s.EXPECT().SelectMedicalRecords(catID, model.KindVaccination).Return([]model.MedicalRecord{vaccination}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[` + recordJSON + `]`,
+		},
+		{
+			description:    "list of an unknown kind",
+			method:         http.MethodGet,
+			url:            "/cats/v1/cats/" + catID + "/medical?kind=surgery",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description: "get unknown",
+			method:      http.MethodGet,
+			url:         "/cats/v1/cats/" + catID + "/medical/" + recordID,
+			mock: func(s *model.MockMedicalStore, storage *model.MockStorage) {
+				s.EXPECT().SelectMedicalRecord(catID, recordID).Return(model.MedicalRecord{}, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody: `{"error":{"status":404,"message":"medical record id ` + recordID + ` of cat id ` + catID +
+				` not found"}}`,
+		},
+		{
+			description: "delete",
+			method:      http.MethodDelete,
+			url:         "/cats/v1/cats/" + catID + "/medical/" + recordID,
+			mock: func(s *model.MockMedicalStore, storage *model.MockStorage) {
+				s.EXPECT().DeleteMedicalRecord(catID, recordID).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"result":"success","error":{}}`,
+		},
+		{
+			description: "due vaccinations",
+			method:      http.MethodGet,
+			url:         "/cats/v1/vaccinations/due",
+			mock: func(s *model.MockMedicalStore, storage *model.MockStorage) {
+				s.EXPECT().SelectDueVaccinations(gomock.Any()).Return(due, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"catId":"` + catID + `","catName":"tom","vaccine":"rabies","lastDate":"2020-01-02",` +
+				`"dueDate":"2021-01-02","overdue":true},{"catId":"` + catID + `","catName":"tom","vaccine":"fvrcp",` +
+				`"lastDate":"2020-06-01","dueDate":"2099-06-01","overdue":false}]`,
+		},
+		{
+			description: "overdue vaccinations",
+			method:      http.MethodGet,
+			url:         "/cats/v1/vaccinations/due?days=7&status=overdue",
+			mock: func(s *model.MockMedicalStore, storage *model.MockStorage) {
+				s.EXPECT().SelectDueVaccinations(gomock.Any()).Return(due, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"catId":"` + catID + `","catName":"tom","vaccine":"rabies","lastDate":"2020-01-02",` +
+				`"dueDate":"2021-01-02","overdue":true}]`,
+		},
+		{
+			description:    "due vaccinations too far ahead",
+			method:         http.MethodGet,
+			url:            "/cats/v1/vaccinations/due?days=400",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockMedicalStore(ctrl)
+			storage := model.NewMockStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(s, storage)
+			}
+			a := App{Storage: medicalStorage{storage, s}, Config: conf.SaneDefaults()}
+			a.BootstrapServer()
+
+			req, _ := http.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, tt.expectedStatus, response.Body)
+			}
+			if tt.expectedBody != "" && response.Body.String() != tt.expectedBody {
+				t.Errorf("unxpected response body: got %s, expected %s", response.Body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestApp_Medical_Unsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	a := App{Storage: model.NewMockStorage(ctrl)}
+	a.BootstrapServer()
+
+	req, _ := http.NewRequest(http.MethodGet, "/cats/v1/vaccinations/due", nil)
+	response := httptest.NewRecorder()
+	a.Router.ServeHTTP(response, req)
+
+	if response.Code != http.StatusNotImplemented {
+		t.Errorf("unxpected status code: got %d, expected %d", response.Code, http.StatusNotImplemented)
+	}
+}
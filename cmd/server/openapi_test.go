@@ -0,0 +1,117 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/waikco/cats-v1/openapi"
+)
+
+var pathParam = regexp.MustCompile(`:([^/]+)`)
+
+func loadSpec(t *testing.T) *openapi3.T {
+	t.Helper()
+	doc, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("invalid spec: %v", err)
+	}
+	return doc
+}
+
+func TestOpenAPI_DocumentsEveryRoute(t *testing.T) {
+	doc := loadSpec(t)
+
+	var a App
+	documented := map[string]bool{}
+	for _, route := range a.Routes() {
+		path := pathParam.ReplaceAllString(route.Path, "{$1}")
+		documented[route.Method+" "+path] = true
+
+		item := doc.Paths.Value(path)
+		if item == nil {
+			t.Errorf("route %s %s is not documented: missing path %s", route.Method, route.Path, path)
+			continue
+		}
+		if item.GetOperation(route.Method) == nil {
+			t.Errorf("route %s %s is not documented: missing %s operation", route.Method, route.Path, route.Method)
+		}
+	}
+
+	for path, item := range doc.Paths.Map() {
+		for method := range item.Operations() {
+			if !documented[strings.ToUpper(method)+" "+path] {
+				t.Errorf("spec documents %s %s which is not routed", method, path)
+			}
+		}
+	}
+}
+
+func TestApp_DocsAssetNotFound(t *testing.T) {
+	var a App
+	a.BootstrapServer()
+
+	for _, request := range []string{"/cats/v1/docs/nope.js", "/cats/v1/docs/..", "/cats/v1/docs/NOTICE"} {
+		req := httptest.NewRequest(http.MethodGet, request, nil)
+		response := httptest.NewRecorder()
+		a.Router.ServeHTTP(response, req)
+
+		if response.Code != http.StatusNotFound {
+			t.Errorf("unxpected status code of %s: got %d, expected %d", request, response.Code, http.StatusNotFound)
+		}
+	}
+}
+
+func TestApp_OpenAPI(t *testing.T) {
+	var a App
+	a.BootstrapServer()
+
+	tests := []struct {
+		description string
+		// given
+		request string
+		// then
+		expectedContentType string
+	}{
+		{
+			description:         "spec",
+			request:             "/cats/v1/openapi.json",
+			expectedContentType: "application/json",
+		},
+		{
+			description:         "documentation page",
+			request:             "/cats/v1/docs",
+			expectedContentType: "text/html; charset=utf-8",
+		},
+		{
+			description:         "documentation script",
+			request:             "/cats/v1/docs/swagger-ui-bundle.js",
+			expectedContentType: "text/javascript; charset=utf-8",
+		},
+		{
+			description:         "documentation styles",
+			request:             "/cats/v1/docs/swagger-ui.css",
+			expectedContentType: "text/css; charset=utf-8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.request, nil)
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != http.StatusOK {
+				t.Errorf("unxpected status code: got %d, expected %d", response.Code, http.StatusOK)
+			}
+			if got := response.Header().Get("Content-Type"); got != tt.expectedContentType {
+				t.Errorf("unxpected content type: got %s, expected %s", got, tt.expectedContentType)
+			}
+			if response.Body.Len() == 0 {
+				t.Error("expected a non-empty body")
+			}
+		})
+	}
+}
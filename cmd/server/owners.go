@@ -0,0 +1,248 @@
+package server
+
+import (
+	"database/sql"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	json "github.com/json-iterator/go"
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/model"
+)
+
+// ownerStore returns the storage of the owners of the tenant of a request.
+func (a *App) ownerStore(w http.ResponseWriter, r *http.Request) (model.OwnerStore, bool) {
+	return optional[model.OwnerStore](w, r, a.storage(r.Context()), "owners")
+}
+
+// CreateOwner creates an owner, without any cats.
+func (a *App) CreateOwner(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.ownerStore(w, r)
+	if !ok {
+		return
+	}
+
+	var owner model.Owner
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		_, err = decodeBody(r, body, &owner)
+	}
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "invalid owner in request body")
+		return
+	}
+	if err := owner.Validate(); err != nil {
+		respondError(w, r, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	id, err := store.InsertOwner(owner)
+	if err == nil {
+		owner, err = store.SelectOwner(id)
+	}
+	if err != nil {
+		log.Error().Msgf("error creating owner: %v", err)
+		respondError(w, r, http.StatusInternalServerError, "unable to create owner")
+		return
+	}
+	respond(w, r, http.StatusCreated, owner)
+}
+
+// GetOwners lists a page of owners, the same way as cats.
+func (a *App) GetOwners(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.ownerStore(w, r)
+	if !ok {
+		return
+	}
+	count, _ := strconv.Atoi(r.FormValue("count"))
+	start, _ := strconv.Atoi(r.FormValue("start"))
+	if count > 10 || count < 1 {
+		count = 10
+	}
+	if start < 0 {
+		start = 0
+	}
+
+	owners, err := store.SelectOwners(count, start)
+	if err != nil {
+		log.Error().Msgf("error getting owners: %v", err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get owners")
+		return
+	}
+	respond(w, r, http.StatusOK, owners)
+}
+
+// GetOwner retrieves an owner.
+func (a *App) GetOwner(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.ownerStore(w, r)
+	if !ok {
+		return
+	}
+	if owner, ok := selectOwner(w, r, store, ps.ByName("id")); ok {
+		respond(w, r, http.StatusOK, owner)
+	}
+}
+
+// UpdateOwner changes the fields of an owner given in the request body, leaving the others
+// as they are.
+func (a *App) UpdateOwner(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.ownerStore(w, r)
+	if !ok {
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		respondError(w, r, http.StatusInternalServerError, "error reading body")
+		return
+	}
+	owner, ok := selectOwner(w, r, store, ps.ByName("id"))
+	if !ok {
+		return
+	}
+	id, created := owner.ID, owner.CreatedAt
+	if _, err := decodeBody(r, body, &owner); err != nil {
+		respondError(w, r, http.StatusBadRequest, "invalid owner in request body")
+		return
+	}
+	owner.ID, owner.CreatedAt = id, created
+	if err := owner.Validate(); err != nil {
+		respondError(w, r, http.StatusBadRequest, "%v", err)
+		return
+	}
+
+	switch err := store.UpdateOwner(owner); err {
+	case nil:
+		respond(w, r, http.StatusOK, owner)
+	case sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "owner id %s not found", id)
+	default:
+		log.Error().Msgf("error updating owner %s: %v", id, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to update owner")
+	}
+}
+
+// DeleteOwner deletes an owner, which is refused with a 409 while it still has cats.
+func (a *App) DeleteOwner(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.ownerStore(w, r)
+	if !ok {
+		return
+	}
+	id := ps.ByName("id")
+	if uuid.FromStringOrNil(id) == uuid.Nil {
+		respondError(w, r, http.StatusBadRequest, "invalid owner id: %s", id)
+		return
+	}
+	switch err := store.DeleteOwner(id); err {
+	case nil:
+		respond(w, r, http.StatusOK, Response{Result: "success"})
+	case sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "owner id %s not found", id)
+	case model.ErrOwnerHasCats:
+		respondError(w, r, http.StatusConflict, "owner id %s still has cats, unassign them first", id)
+	default:
+		log.Error().Msgf("error deleting owner %s: %v", id, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to delete owner")
+	}
+}
+
+// GetOwnerCats lists every cat assigned to an owner.
+func (a *App) GetOwnerCats(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.ownerStore(w, r)
+	if !ok {
+		return
+	}
+	owner, ok := selectOwner(w, r, store, ps.ByName("id"))
+	if !ok {
+		return
+	}
+	cats, err := store.SelectOwnerCats(owner.ID)
+	if err != nil {
+		log.Error().Msgf("error getting cats of owner %s: %v", owner.ID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get cats")
+		return
+	}
+	respond(w, r, http.StatusOK, cats)
+}
+
+// AssignCat assigns a cat to an owner, taking it from any previous owner, and responds
+// with the cat.
+func (a *App) AssignCat(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	a.setOwner(w, r, ps, true)
+}
+
+// UnassignCat takes a cat from its owner, and responds with the cat, which is refused with a
+// 409 while the cat is pending adoption or adopted.
+func (a *App) UnassignCat(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	a.setOwner(w, r, ps, false)
+}
+
+func (a *App) setOwner(w http.ResponseWriter, r *http.Request, ps httprouter.Params, assign bool) {
+	store, ok := a.ownerStore(w, r)
+	if !ok {
+		return
+	}
+	catID := ps.ByName("catId")
+	if uuid.FromStringOrNil(catID) == uuid.Nil {
+		respondError(w, r, http.StatusBadRequest, "invalid cat id: %s", catID)
+		return
+	}
+	owner, ok := selectOwner(w, r, store, ps.ByName("id"))
+	if !ok {
+		return
+	}
+
+	var err error
+	if assign {
+		err = store.AssignCat(catID, owner.ID)
+	} else {
+		err = store.UnassignCat(catID, owner.ID)
+	}
+	var cat model.Cat
+	if err == nil {
+		var b []byte
+		if b, err = a.storage(r.Context()).Select(catID); err == nil {
+			err = json.Unmarshal(b, &cat)
+		}
+	}
+	switch err {
+	case nil:
+		cat.ID = catID
+		a.publish(r.Context(), events.Updated, catID, &cat)
+		respond(w, r, http.StatusOK, cat)
+	case sql.ErrNoRows:
+		if assign {
+			respondError(w, r, http.StatusNotFound, "cat id %s not found", catID)
+		} else {
+			respondError(w, r, http.StatusNotFound, "cat id %s is not assigned to owner id %s", catID, owner.ID)
+		}
+	case model.ErrCatInAdoption:
+		respondError(w, r, http.StatusConflict, "cat id %s is pending adoption or adopted, so keeps its owner", catID)
+	default:
+		log.Error().Msgf("error changing the owner of cat %s: %v", catID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to change the owner of the cat")
+	}
+}
+
+// selectOwner selects an owner, responding with a 400 for invalid ids and a 404 for
+// unknown ones.
+func selectOwner(w http.ResponseWriter, r *http.Request, store model.OwnerStore, id string) (model.Owner, bool) {
+	if uuid.FromStringOrNil(id) == uuid.Nil {
+		respondError(w, r, http.StatusBadRequest, "invalid owner id: %s", id)
+		return model.Owner{}, false
+	}
+	owner, err := store.SelectOwner(id)
+	switch err {
+	case nil:
+		return owner, true
+	case sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "owner id %s not found", id)
+	default:
+		log.Error().Msgf("error getting owner %s: %v", id, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get owner")
+	}
+	return model.Owner{}, false
+}
@@ -0,0 +1,241 @@
+package server
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/codec"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/model"
+)
+
+// ownerStorage is storage able to persist owners.
+type ownerStorage struct {
+	*model.MockStorage
+	*model.MockOwnerStore
+}
+
+func TestApp_Owners(t *testing.T) {
+	const (
+		ownerID = "3d6f1c2a-8b4e-4f7a-9c1d-2e5b8a7f6c3d"
+		catID   = "fe271e7e-83ca-477b-92fc-d0c3fa602d7d"
+	)
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	owner := model.Owner{ID: ownerID, Name: "jane", Email: "jane@example.com", CreatedAt: created}
+
+	tests := []struct {
+		description string
+		// given
+		method string
+		url    string
+		body   string
+		mock   func(s *model.MockOwnerStore, storage *model.MockStorage)
+		// then
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description: "create",
+			method:      http.MethodPost,
+			url:         "/cats/v1/owners",
+			body:        `{"name":"jane","email":"jane@example.com"}`,
+			mock: func(s *model.MockOwnerStore, storage *model.MockStorage) {
+				s.EXPECT().InsertOwner(model.Owner{Name: "jane", Email: "jane@example.com"}).Return(ownerID, nil)
+				s.EXPECT().SelectOwner(ownerID).Return(owner, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody:   `{"id":"` + ownerID + `","name":"jane","email":"jane@example.com","createdAt":"2020-01-02T03:04:05Z"}`,
+		},
+		{
+			description:    "create with an invalid email",
+			method:         http.MethodPost,
+			url:            "/cats/v1/owners",
+			body:           `{"name":"jane","email":"jane"}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description: "list",
+			method:      http.MethodGet,
+			url:         "/cats/v1/owners?count=2",
+			mock: func(s *model.MockOwnerStore, storage *model.MockStorage) {
+				s.EXPECT().SelectOwners(2, 0).Return([]model.Owner{owner}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"id":"` + ownerID + `","name":"jane","email":"jane@example.com","createdAt":"2020-01-02T03:04:05Z"}]`,
+		},
+		{
+			description: "get unknown",
+			method:      http.MethodGet,
+			url:         "/cats/v1/owners/" + ownerID,
+			mock: func(s *model.MockOwnerStore, storage *model.MockStorage) {
+				s.EXPECT().SelectOwner(ownerID).Return(model.Owner{}, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":{"status":404,"message":"owner id ` + ownerID + ` not found"}}`,
+		},
+		{
+			description: "update the given fields",
+			method:      http.MethodPatch,
+			url:         "/cats/v1/owners/" + ownerID,
+			body:        `{"phone":"555-0100"}`,
+			mock: func(s *model.MockOwnerStore, storage *model.MockStorage) {
+				s.EXPECT().SelectOwner(ownerID).Return(owner, nil)
+				updated := owner
+				updated.Phone = "555-0100"
+				s.EXPECT().UpdateOwner(updated).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"id":"` + ownerID + `","name":"jane","email":"jane@example.com","phone":"555-0100",` +
+				`"createdAt":"2020-01-02T03:04:05Z"}`,
+		},
+		{
+			description: "delete with cats",
+			method:      http.MethodDelete,
+			url:         "/cats/v1/owners/" + ownerID,
+			mock: func(s *model.MockOwnerStore, storage *model.MockStorage) {
+				s.EXPECT().DeleteOwner(ownerID).Return(model.ErrOwnerHasCats)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			description: "delete",
+			method:      http.MethodDelete,
+			url:         "/cats/v1/owners/" + ownerID,
+			mock: func(s *model.MockOwnerStore, storage *model.MockStorage) {
+				s.EXPECT().DeleteOwner(ownerID).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"result":"success","error":{}}`,
+		},
+		{
+			description: "list cats",
+			method:      http.MethodGet,
+			url:         "/cats/v1/owners/" + ownerID + "/cats",
+			mock: func(s *model.MockOwnerStore, storage *model.MockStorage) {
+				s.EXPECT().SelectOwner(ownerID).Return(owner, nil)
+				s.EXPECT().SelectOwnerCats(ownerID).Return([]model.Cat{{ID: catID, Name: "tom", OwnerID: ownerID}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"id":"` + catID + `","name":"tom","ownerId":"` + ownerID + `"}]`,
+		},
+		{
+			description: "assign a cat",
+			method:      http.MethodPost,
+			url:         "/cats/v1/owners/" + ownerID + "/cats/" + catID,
+			mock: func(s *model.MockOwnerStore, storage *model.MockStorage) {
+				s.EXPECT().SelectOwner(ownerID).Return(owner, nil)
+				s.EXPECT().AssignCat(catID, ownerID).Return(nil)
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom","color":"grey","ownerId":"`+ownerID+`"}`), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":"` + catID + `","name":"tom","color":"grey","ownerId":"` + ownerID + `"}`,
+		},
+		{
+			description: "assign an unknown cat",
+			method:      http.MethodPost,
+			url:         "/cats/v1/owners/" + ownerID + "/cats/" + catID,
+			mock: func(s *model.MockOwnerStore, storage *model.MockStorage) {
+				s.EXPECT().SelectOwner(ownerID).Return(owner, nil)
+				s.EXPECT().AssignCat(catID, ownerID).Return(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			description: "unassign a cat of another owner",
+			method:      http.MethodDelete,
+			url:         "/cats/v1/owners/" + ownerID + "/cats/" + catID,
+			mock: func(s *model.MockOwnerStore, storage *model.MockStorage) {
+				s.EXPECT().SelectOwner(ownerID).Return(owner, nil)
+				s.EXPECT().UnassignCat(catID, ownerID).Return(sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":{"status":404,"message":"cat id ` + catID + ` is not assigned to owner id ` + ownerID + `"}}`,
+		},
+		{
+			description: "unassign a cat pending adoption",
+			method:      http.MethodDelete,
+			url:         "/cats/v1/owners/" + ownerID + "/cats/" + catID,
+			mock: func(s *model.MockOwnerStore, storage *model.MockStorage) {
+				s.EXPECT().SelectOwner(ownerID).Return(owner, nil)
+				s.EXPECT().UnassignCat(catID, ownerID).Return(model.ErrCatInAdoption)
+			},
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"error":{"status":409,"message":"cat id ` + catID + ` is pending adoption or adopted, so keeps its owner"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockOwnerStore(ctrl)
+			storage := model.NewMockStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(s, storage)
+			}
+			a := App{Storage: ownerStorage{storage, s}, Config: conf.SaneDefaults()}
+			a.BootstrapServer()
+
+			req, _ := http.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, tt.expectedStatus, response.Body)
+			}
+			if tt.expectedBody != "" && response.Body.String() != tt.expectedBody {
+				t.Errorf("unxpected response body: got %s, expected %s", response.Body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestApp_Owners_Encodings(t *testing.T) {
+	const ownerID = "3d6f1c2a-8b4e-4f7a-9c1d-2e5b8a7f6c3d"
+	owner := model.Owner{ID: ownerID, Name: "jane", CreatedAt: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockOwnerStore(ctrl)
+	s.EXPECT().InsertOwner(model.Owner{Name: "jane"}).Return(ownerID, nil)
+	s.EXPECT().SelectOwner(ownerID).Return(owner, nil)
+	a := App{Storage: ownerStorage{model.NewMockStorage(ctrl), s}, Config: conf.SaneDefaults()}
+	a.BootstrapServer()
+
+	req, _ := http.NewRequest(http.MethodPost, "/cats/v1/owners", bytes.NewBufferString(`<owner><name>jane</name></owner>`))
+	req.Header.Set("Content-Type", "application/xml")
+	req.Header.Set("Accept", "application/msgpack")
+	response := httptest.NewRecorder()
+	a.Router.ServeHTTP(response, req)
+
+	if response.Code != http.StatusCreated {
+		t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, http.StatusCreated, response.Body)
+	}
+	if ct := response.Header().Get("Content-Type"); ct != "application/msgpack" {
+		t.Errorf("unxpected content type: %s", ct)
+	}
+	if expected, _ := codec.MessagePack.Marshal(owner); !bytes.Equal(response.Body.Bytes(), expected) {
+		t.Errorf("unxpected response body: %x", response.Body.Bytes())
+	}
+}
+
+func TestApp_Owners_Unsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	a := App{Storage: model.NewMockStorage(ctrl)}
+	a.BootstrapServer()
+
+	req, _ := http.NewRequest(http.MethodGet, "/cats/v1/owners", nil)
+	response := httptest.NewRecorder()
+	a.Router.ServeHTTP(response, req)
+
+	if response.Code != http.StatusNotImplemented {
+		t.Errorf("unxpected status code: got %d, expected %d", response.Code, http.StatusNotImplemented)
+	}
+}
@@ -0,0 +1,303 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+	"github.com/waikco/cats-v1/model"
+	"github.com/waikco/cats-v1/photos"
+)
+
+// Defaults of the photo limits, for configs without them.
+const (
+	DefaultMaxPhotoSize  = 10 << 20
+	DefaultThumbnailSize = 256
+)
+
+// photoField names the multipart field of uploaded photos.
+const photoField = "photo"
+
+// photoKey and thumbnailKey key the files of a photo in the blob store.
+func photoKey(catID, id string) string     { return "cats/" + catID + "/photos/" + id + "/original" }
+func thumbnailKey(catID, id string) string { return "cats/" + catID + "/photos/" + id + "/thumbnail" }
+
+// photoStore returns the storage of the photos of the tenant of a request, which are not
+// supported either when no blob store is configured.
+func (a *App) photoStore(w http.ResponseWriter, r *http.Request) (model.PhotoStore, bool) {
+	if a.Blobs == nil {
+		respondError(w, r, http.StatusNotImplemented, "photos are not supported by the configured storage")
+		return nil, false
+	}
+	return optional[model.PhotoStore](w, r, a.storage(r.Context()), "photos")
+}
+
+// UploadPhoto stores the photo in the photo field of a multipart form, along with a
+// thumbnail of it. The type of the photo is sniffed from its content, whatever it was
+// uploaded as.
+func (a *App) UploadPhoto(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.photoStore(w, r)
+	if !ok {
+		return
+	}
+	catID := ps.ByName("id")
+	if !a.catExists(w, r, catID) {
+		return
+	}
+
+	maxSize := a.Config.Photos.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxPhotoSize
+	}
+	// leave room for the rest of the form, the photo itself being limited below
+	r.Body = http.MaxBytesReader(w, r.Body, maxSize+64<<10)
+	b, err := readPhoto(r, maxSize)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge) || err == errPhotoTooLarge:
+		respondError(w, r, http.StatusRequestEntityTooLarge, "photos must be at most %d bytes", maxSize)
+		return
+	case err == http.ErrNotMultipart:
+		respondError(w, r, http.StatusUnsupportedMediaType, "photos must be uploaded as multipart/form-data")
+		return
+	case err != nil:
+		respondError(w, r, http.StatusBadRequest, "%v", err)
+		return
+	}
+	contentType := photos.Sniff(b)
+	if !photos.ContentTypes[contentType] {
+		respondError(w, r, http.StatusUnsupportedMediaType, "photos must be jpeg, png or gif, not %s", contentType)
+		return
+	}
+	img, err := photos.Decode(b)
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "invalid photo: %v", err)
+		return
+	}
+	size := a.Config.Photos.ThumbnailSize
+	if size <= 0 {
+		size = DefaultThumbnailSize
+	}
+	var thumb bytes.Buffer
+	if err := photos.EncodeThumbnail(&thumb, photos.Thumbnail(img, size)); err != nil {
+		log.Error().Msgf("error creating thumbnail of photo of cat %s: %v", catID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to create thumbnail")
+		return
+	}
+
+	photo := model.Photo{
+		ID:          uuid.NewV4().String(),
+		CatID:       catID,
+		ContentType: contentType,
+		Size:        int64(len(b)),
+		Width:       img.Bounds().Dx(),
+		Height:      img.Bounds().Dy(),
+		CreatedAt:   time.Now().UTC().Truncate(time.Microsecond),
+	}
+	ctx := r.Context()
+	err = a.Blobs.Put(ctx, photoKey(catID, photo.ID), bytes.NewReader(b), photo.Size, contentType)
+	if err == nil {
+		err = a.Blobs.Put(ctx, thumbnailKey(catID, photo.ID), &thumb, int64(thumb.Len()), photos.ThumbnailContentType)
+	}
+	if err == nil {
+		err = store.InsertPhoto(photo)
+	}
+	switch err {
+	case nil:
+		respondWithJson(w, http.StatusCreated, photo)
+		return
+	case sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "cat id %s not found", catID)
+	default:
+		log.Error().Msgf("error storing photo of cat %s: %v", catID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to store photo")
+	}
+	a.deletePhotoFiles(context.Background(), []model.Photo{photo})
+}
+
+var errPhotoTooLarge = errors.New("photo too large")
+
+// readPhoto reads the photo field of a multipart form, failing with errPhotoTooLarge when
+// it is over maxSize bytes.
+func readPhoto(r *http.Request, maxSize int64) ([]byte, error) {
+	form, err := r.MultipartReader()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		part, err := form.NextPart()
+		if err == io.EOF {
+			return nil, fmt.Errorf("a %s field is required", photoField)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() != photoField {
+			continue
+		}
+		b, err := ioutil.ReadAll(io.LimitReader(part, maxSize+1))
+		if err == nil && int64(len(b)) > maxSize {
+			err = errPhotoTooLarge
+		}
+		return b, err
+	}
+}
+
+// GetPhotos lists the photos of a cat.
+func (a *App) GetPhotos(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.photoStore(w, r)
+	if !ok {
+		return
+	}
+	catID := ps.ByName("id")
+	if !a.catExists(w, r, catID) {
+		return
+	}
+	list, err := store.SelectPhotos(catID)
+	if err != nil {
+		log.Error().Msgf("error getting photos of cat %s: %v", catID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get photos")
+		return
+	}
+	respondWithJson(w, http.StatusOK, list)
+}
+
+// GetPhoto serves a photo as it was uploaded.
+func (a *App) GetPhoto(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	a.servePhoto(w, r, ps, false)
+}
+
+// GetThumbnail serves the thumbnail of a photo, which is always a jpeg.
+func (a *App) GetThumbnail(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	a.servePhoto(w, r, ps, true)
+}
+
+func (a *App) servePhoto(w http.ResponseWriter, r *http.Request, ps httprouter.Params, thumbnail bool) {
+	store, ok := a.photoStore(w, r)
+	if !ok {
+		return
+	}
+	photo, ok := selectPhoto(w, r, store, ps.ByName("id"), ps.ByName("photoId"))
+	if !ok {
+		return
+	}
+	key, contentType, size := photoKey(photo.CatID, photo.ID), photo.ContentType, photo.Size
+	if thumbnail {
+		key, contentType, size = thumbnailKey(photo.CatID, photo.ID), photos.ThumbnailContentType, -1
+	}
+	f, err := a.Blobs.Get(r.Context(), key)
+	if err != nil {
+		log.Error().Msgf("error getting file %s of photo %s: %v", key, photo.ID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get photo")
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	w.Header().Set("Content-Type", contentType)
+	if size >= 0 {
+		w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	}
+	// photos never change, a new photo being uploaded instead
+	w.Header().Set("Cache-Control", "private, max-age=31536000, immutable")
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, f); err != nil {
+		log.Debug().Msgf("error serving photo %s: %v", photo.ID, err)
+	}
+}
+
+// DeletePhoto deletes a photo, along with its files.
+func (a *App) DeletePhoto(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.photoStore(w, r)
+	if !ok {
+		return
+	}
+	photo, ok := selectPhoto(w, r, store, ps.ByName("id"), ps.ByName("photoId"))
+	if !ok {
+		return
+	}
+	switch err := store.DeletePhoto(photo.CatID, photo.ID); err {
+	case nil:
+		a.deletePhotoFiles(r.Context(), []model.Photo{photo})
+		respondWithJson(w, http.StatusOK, Response{Result: "success"})
+	case sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "photo id %s not found", photo.ID)
+	default:
+		log.Error().Msgf("error deleting photo %s: %v", photo.ID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to delete photo")
+	}
+}
+
+// catPhotos lists the photos of a cat when photos are supported, logging any error.
+func (a *App) catPhotos(ctx context.Context, catID string) []model.Photo {
+	store, ok := a.storage(ctx).(model.PhotoStore)
+	if !ok || a.Blobs == nil {
+		return nil
+	}
+	list, err := store.SelectPhotos(catID)
+	if err != nil {
+		log.Error().Msgf("error getting photos of cat %s: %v", catID, err)
+	}
+	return list
+}
+
+// deletePhotoFiles deletes the files of photos, which are only logged when they fail to
+// be, as nothing refers to them anymore.
+func (a *App) deletePhotoFiles(ctx context.Context, list []model.Photo) {
+	for _, photo := range list {
+		for _, key := range []string{photoKey(photo.CatID, photo.ID), thumbnailKey(photo.CatID, photo.ID)} {
+			if err := a.Blobs.Delete(ctx, key); err != nil {
+				log.Warn().Msgf("unable to delete file %s of photo %s: %v", key, photo.ID, err)
+			}
+		}
+	}
+}
+
+// catExists checks a cat exists, responding with a 400 for invalid ids and a 404 for
+// unknown ones.
+func (a *App) catExists(w http.ResponseWriter, r *http.Request, id string) bool {
+	if uuid.FromStringOrNil(id) == uuid.Nil {
+		respondError(w, r, http.StatusBadRequest, "invalid cat id: %s", id)
+		return false
+	}
+	_, err := a.selectCat(r.Context(), id)
+	switch err {
+	case nil:
+		return true
+	case sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "cat id %s not found", id)
+	default:
+		log.Error().Msgf("error getting cat %s: %v", id, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get cat")
+	}
+	return false
+}
+
+// selectPhoto selects a photo of a cat, responding with a 400 for invalid ids and a 404 for
+// unknown ones.
+func selectPhoto(w http.ResponseWriter, r *http.Request, store model.PhotoStore, catID, id string) (model.Photo, bool) {
+	if uuid.FromStringOrNil(catID) == uuid.Nil || uuid.FromStringOrNil(id) == uuid.Nil {
+		respondError(w, r, http.StatusBadRequest, "invalid cat id %s or photo id %s", catID, id)
+		return model.Photo{}, false
+	}
+	photo, err := store.SelectPhoto(catID, id)
+	switch err {
+	case nil:
+		return photo, true
+	case sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "photo id %s not found", id)
+	default:
+		log.Error().Msgf("error getting photo %s: %v", id, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get photo")
+	}
+	return model.Photo{}, false
+}
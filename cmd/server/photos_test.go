@@ -0,0 +1,284 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/model"
+	"github.com/waikco/cats-v1/photos"
+)
+
+// photoStorage is storage able to persist the metadata of photos.
+type photoStorage struct {
+	*model.MockStorage
+	*model.MockPhotoStore
+}
+
+func testPhoto(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var b bytes.Buffer
+	if err := png.Encode(&b, image.NewGray(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	return b.Bytes()
+}
+
+// multipartPhoto encodes a multipart form with content in its photo field.
+func multipartPhoto(t *testing.T, content []byte) (string, *bytes.Buffer) {
+	t.Helper()
+	var b bytes.Buffer
+	form := multipart.NewWriter(&b)
+	part, err := form.CreateFormFile("photo", "tom.png")
+	if err == nil {
+		_, err = part.Write(content)
+	}
+	if err == nil {
+		err = form.Close()
+	}
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	return form.FormDataContentType(), &b
+}
+
+func TestApp_Photos(t *testing.T) {
+	const (
+		catID   = "fe271e7e-83ca-477b-92fc-d0c3fa602d7d"
+		photoID = "7b1c2d3e-4f5a-4b6c-8d7e-9f0a1b2c3d4e"
+	)
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	photo := model.Photo{ID: photoID, CatID: catID, ContentType: "image/png", Size: 5, Width: 2, Height: 1, CreatedAt: created}
+	photoJSON := `{"id":"` + photoID + `","contentType":"image/png","size":5,"width":2,"height":1,"createdAt":"2020-01-02T03:04:05Z"}`
+	valid := testPhoto(t, 300, 150)
+
+	tests := []struct {
+		description string
+		// given
+		method      string
+		url         string
+		contentType string
+		body        []byte
+		maxSize     int64
+		mock        func(s *model.MockPhotoStore, storage *model.MockStorage)
+		// then
+		expectedStatus int
+		expectedBody   string
+		expectedType   string
+		expectedFiles  []string
+	}{
+		{
+			description: "upload",
+			method:      http.MethodPost,
+			url:         "/cats/v1/cats/" + catID + "/photos",
+			body:        valid,
+			mock: func(s *model.MockPhotoStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom"}`), nil)
+				s.EXPECT().InsertPhoto(gomock.Any()).DoAndReturn(func(p model.Photo) error {
+					if p.CatID != catID || p.ContentType != "image/png" || p.Size != int64(len(valid)) ||
+						p.Width != 300 || p.Height != 150 || p.CreatedAt.IsZero() {
+						t.Errorf("unxpected photo: %+v", p)
+					}
+					return nil
+				})
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			description: "upload too large",
+			method:      http.MethodPost,
+			url:         "/cats/v1/cats/" + catID + "/photos",
+			body:        valid,
+			maxSize:     int64(len(valid)) - 1,
+			mock: func(s *model.MockPhotoStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom"}`), nil)
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			description: "upload something other than a photo",
+			method:      http.MethodPost,
+			url:         "/cats/v1/cats/" + catID + "/photos",
+			body:        []byte("<html><body>tom</body></html>"),
+			mock: func(s *model.MockPhotoStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom"}`), nil)
+			},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			description: "upload without a form",
+			method:      http.MethodPost,
+			url:         "/cats/v1/cats/" + catID + "/photos",
+			contentType: "image/png",
+			body:        valid,
+			mock: func(s *model.MockPhotoStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom"}`), nil)
+			},
+			expectedStatus: http.StatusUnsupportedMediaType,
+		},
+		{
+			description: "upload to an unknown cat",
+			method:      http.MethodPost,
+			url:         "/cats/v1/cats/" + catID + "/photos",
+			body:        valid,
+			mock: func(s *model.MockPhotoStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return(nil, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":{"status":404,"message":"cat id ` + catID + ` not found"}}`,
+		},
+		{
+			description: "list",
+			method:      http.MethodGet,
+			url:         "/cats/v1/cats/" + catID + "/photos",
+			mock: func(s *model.MockPhotoStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom"}`), nil)
+				s.EXPECT().SelectPhotos(catID).Return([]model.Photo{photo}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[` + photoJSON + `]`,
+		},
+		{
+			description: "get",
+			method:      http.MethodGet,
+			url:         "/cats/v1/cats/" + catID + "/photos/" + photoID,
+			mock: func(s *model.MockPhotoStore, storage *model.MockStorage) {
+				s.EXPECT().SelectPhoto(catID, photoID).Return(photo, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "photo",
+			expectedType:   "image/png",
+		},
+		{
+			description: "get thumbnail",
+			method:      http.MethodGet,
+			url:         "/cats/v1/cats/" + catID + "/photos/" + photoID + "/thumbnail",
+			mock: func(s *model.MockPhotoStore, storage *model.MockStorage) {
+				s.EXPECT().SelectPhoto(catID, photoID).Return(photo, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   "thumbnail",
+			expectedType:   "image/jpeg",
+		},
+		{
+			description: "get unknown",
+			method:      http.MethodGet,
+			url:         "/cats/v1/cats/" + catID + "/photos/" + photoID,
+			mock: func(s *model.MockPhotoStore, storage *model.MockStorage) {
+				s.EXPECT().SelectPhoto(catID, photoID).Return(model.Photo{}, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			description: "delete",
+			method:      http.MethodDelete,
+			url:         "/cats/v1/cats/" + catID + "/photos/" + photoID,
+			mock: func(s *model.MockPhotoStore, storage *model.MockStorage) {
+				s.EXPECT().SelectPhoto(catID, photoID).Return(photo, nil)
+				s.EXPECT().DeletePhoto(catID, photoID).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedFiles:  []string{},
+		},
+		{
+			description: "get the cat with its photos",
+			method:      http.MethodGet,
+			url:         "/cats/v1/cats/" + catID,
+			mock: func(s *model.MockPhotoStore, storage *model.MockStorage) {
+				storage.EXPECT().Select(catID).Return([]byte(`{"name":"tom"}`), nil)
+				s.EXPECT().SelectPhotos(catID).Return([]model.Photo{photo}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"tom","photos":[` + photoJSON + `]}`,
+		},
+		{
+			description: "delete the cat with its photos",
+			method:      http.MethodDelete,
+			url:         "/cats/v1/cats/" + catID,
+			mock: func(s *model.MockPhotoStore, storage *model.MockStorage) {
+				s.EXPECT().SelectPhotos(catID).Return([]model.Photo{photo}, nil)
+				storage.EXPECT().Delete(catID).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedFiles:  []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockPhotoStore(ctrl)
+			storage := model.NewMockStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(s, storage)
+			}
+			blobs, err := photos.NewFS(t.TempDir())
+			if err != nil {
+				t.Fatalf("unxpected error: %v", err)
+			}
+			ctx := context.Background()
+			_ = blobs.Put(ctx, photoKey(catID, photoID), strings.NewReader("photo"), 5, "image/png")
+			_ = blobs.Put(ctx, thumbnailKey(catID, photoID), strings.NewReader("thumbnail"), 9, "image/jpeg")
+
+			config := conf.SaneDefaults()
+			if tt.maxSize > 0 {
+				config.Photos.MaxSize = tt.maxSize
+			}
+			a := App{Storage: photoStorage{storage, s}, Blobs: blobs, Config: config}
+			a.BootstrapServer()
+
+			contentType, body := tt.contentType, bytes.NewBuffer(tt.body)
+			if tt.method == http.MethodPost && contentType == "" {
+				contentType, body = multipartPhoto(t, tt.body)
+			}
+			req, _ := http.NewRequest(tt.method, tt.url, body)
+			if contentType != "" {
+				req.Header.Set("Content-Type", contentType)
+			}
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, tt.expectedStatus, response.Body)
+			}
+			if tt.expectedBody != "" && response.Body.String() != tt.expectedBody {
+				t.Errorf("unxpected response body: got %s, expected %s", response.Body, tt.expectedBody)
+			}
+			if got := response.Header().Get("Content-Type"); tt.expectedType != "" && got != tt.expectedType {
+				t.Errorf("unxpected content type: got %s, expected %s", got, tt.expectedType)
+			}
+			if tt.expectedFiles != nil {
+				for _, key := range []string{photoKey(catID, photoID), thumbnailKey(catID, photoID)} {
+					if _, err := blobs.Get(ctx, key); err != photos.ErrNotFound {
+						t.Errorf("unxpected file %s left: %v", key, err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestApp_Photos_Unsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	a := App{Storage: photoStorage{model.NewMockStorage(ctrl), model.NewMockPhotoStore(ctrl)}}
+	a.BootstrapServer()
+
+	req, _ := http.NewRequest(http.MethodGet, "/cats/v1/cats/fe271e7e-83ca-477b-92fc-d0c3fa602d7d/photos", nil)
+	response := httptest.NewRecorder()
+	a.Router.ServeHTTP(response, req)
+
+	if response.Code != http.StatusNotImplemented {
+		t.Errorf("unxpected status code: got %d, expected %d", response.Code, http.StatusNotImplemented)
+	}
+}
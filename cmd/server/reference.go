@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/waikco/cats-v1/model"
+)
+
+// prepareCat puts a cat in the form it is stored in, its breed and color normalised against
+// the reference data, and checks that it can be stored.
+func (a *App) prepareCat(cat *model.Cat, now time.Time) error {
+	cat.Normalize(now)
+	if err := a.Reference.Normalize(cat); err != nil {
+		return err
+	}
+	return cat.Validate()
+}
+
+// GetBreeds lists every breed cats are normalised against.
+func (a *App) GetBreeds(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	respondWithJson(w, http.StatusOK, a.Reference.Breeds())
+}
+
+// GetBreed looks a breed up by its name or one of its aliases.
+func (a *App) GetBreed(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	name := ps.ByName("name")
+	breed, ok := a.Reference.Breed(name)
+	if !ok {
+		respondError(w, r, http.StatusNotFound, "breed %s not found", name)
+		return
+	}
+	respondWithJson(w, http.StatusOK, breed)
+}
+
+// GetColors lists every color of the palette cats are normalised against.
+func (a *App) GetColors(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	respondWithJson(w, http.StatusOK, a.Reference.Colors())
+}
+
+// GetColor looks a color up by its name or one of its aliases.
+func (a *App) GetColor(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	name := ps.ByName("name")
+	color, ok := a.Reference.Color(name)
+	if !ok {
+		respondError(w, r, http.StatusNotFound, "color %s not found", name)
+		return
+	}
+	respondWithJson(w, http.StatusOK, color)
+}
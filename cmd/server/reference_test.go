@@ -0,0 +1,144 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/model"
+	"github.com/waikco/cats-v1/reference"
+)
+
+func TestApp_Reference(t *testing.T) {
+	catalog := reference.New(
+		[]model.Breed{{Name: "Maine Coon", Aliases: []string{"coon"}}},
+		[]model.Color{{Name: "black", Hex: "#1c1c1c", Aliases: []string{"blk"}}},
+		false)
+
+	tests := []struct {
+		description string
+		// given
+		url string
+		// then
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description:    "breeds",
+			url:            "/cats/v1/breeds",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"name":"Maine Coon","aliases":["coon"]}]`,
+		},
+		{
+			description:    "breed by alias",
+			url:            "/cats/v1/breeds/COON",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"Maine Coon","aliases":["coon"]}`,
+		},
+		{
+			description:    "colors",
+			url:            "/cats/v1/colors",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"name":"black","hex":"#1c1c1c","aliases":["blk"]}]`,
+		},
+		{
+			description:    "color by name",
+			url:            "/cats/v1/colors/black",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"name":"black","hex":"#1c1c1c","aliases":["blk"]}`,
+		},
+		{
+			description:    "unknown color",
+			url:            "/cats/v1/colors/sparkly",
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":{"status":404,"message":"color sparkly not found"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			a := App{Storage: model.NewMockStorage(ctrl), Config: conf.SaneDefaults(), Reference: catalog}
+			a.BootstrapServer()
+
+			req, _ := http.NewRequest(http.MethodGet, tt.url, nil)
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, tt.expectedStatus, response.Body)
+			}
+			if response.Body.String() != tt.expectedBody {
+				t.Errorf("unxpected response body: got %s, expected %s", response.Body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestApp_CreateCat_Reference(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		strict bool
+		body   string
+		mock   func(s *model.MockStorage)
+		// then
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description: "aliases are normalised",
+			body:        `{"name":"tom","color":"Black ","breed":"coon"}`,
+			mock: func(s *model.MockStorage) {
+				s.EXPECT().Insert([]byte(`{"name":"tom","color":"black","breed":"Maine Coon"}`)).Return(catID, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			description: "unknown values are kept",
+			body:        `{"name":"tom","color":"sparkly"}`,
+			mock: func(s *model.MockStorage) {
+				s.EXPECT().Insert([]byte(`{"name":"tom","color":"sparkly"}`)).Return(catID, nil)
+			},
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			description:    "unknown values are rejected when strict",
+			strict:         true,
+			body:           `{"name":"tom","color":"sparkly","breed":"unicorn"}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody: `{"error":{"status":400,"message":"invalid cat: color must be a known color, breed must be a known breed",` +
+				`"details":[{"location":"body","field":"color","reason":"must be a known color"},` +
+				`{"location":"body","field":"breed","reason":"must be a known breed"}]}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(s)
+			}
+			config := conf.SaneDefaults()
+			config.Reference.Strict = tt.strict
+			a := App{Storage: s, Config: config}
+			a.BootstrapServer()
+
+			req, _ := http.NewRequest(http.MethodPost, "/cats/v1/", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, tt.expectedStatus, response.Body)
+			}
+			if tt.expectedBody != "" && response.Body.String() != tt.expectedBody {
+				t.Errorf("unxpected response body: got %s, expected %s", response.Body, tt.expectedBody)
+			}
+		})
+	}
+}
@@ -1,11 +1,17 @@
 package server
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"mime"
 	"net/http"
+	"path"
 	"strconv"
+	"strings"
+	"time"
 
 	uuid "github.com/satori/go.uuid"
 
@@ -13,20 +19,70 @@ import (
 
 	"github.com/julienschmidt/httprouter"
 	"github.com/rs/zerolog/log"
+	"github.com/waikco/cats-v1/buildinfo"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/model"
+	"github.com/waikco/cats-v1/openapi"
 )
 
 type health struct {
-	Status string `json:"status"`
+	Status string `json:"status" xml:"status"`
 }
 
-func (a *App) Health(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	respondWithJson(w, http.StatusOK, health{Status: "Pets is up and available"})
+// Live reports that the process is up, without checking any dependencies.
+func (a *App) Live(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	respond(w, r, http.StatusOK, health{Status: "Pets is up and available"})
+}
+
+// Ready runs the registered dependency checks, responding 503 when a critical check fails.
+func (a *App) Ready(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	report := a.Checks.Run(r.Context())
+	if !report.Healthy() {
+		log.Warn().Msgf("readiness check failed: %+v", report.Components)
+		respond(w, r, http.StatusServiceUnavailable, report)
+		return
+	}
+	respond(w, r, http.StatusOK, report)
+}
+
+// Version reports the build information of the running binary.
+func (a *App) Version(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	respond(w, r, http.StatusOK, buildinfo.Get())
+}
+
+// OpenAPI serves the OpenAPI 3 document describing the api.
+func (a *App) OpenAPI(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(openapi.Spec())
+}
+
+// Docs serves the api documentation page.
+func (a *App) Docs(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(openapi.Docs())
+}
+
+// DocsAsset serves the scripts and styles loaded by the api documentation page.
+func (a *App) DocsAsset(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	name := ps.ByName("file")
+	b, err := openapi.Asset(name)
+	if err != nil {
+		respondWithJson(w, http.StatusNotFound, Response{
+			Error: Error{Status: http.StatusNotFound, Message: fmt.Sprintf("documentation asset %s not found", name)},
+		})
+		return
+	}
+	w.Header().Set("Content-Type", mime.TypeByExtension(path.Ext(name)))
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(b)
 }
 
 func (a *App) CreateCat(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		respondWithJson(w, http.StatusInternalServerError, Response{
+		respond(w, r, http.StatusInternalServerError, Response{
 			Result: nil,
 			Error: Error{
 				Status:  http.StatusInternalServerError,
@@ -36,27 +92,37 @@ func (a *App) CreateCat(w http.ResponseWriter, r *http.Request, ps httprouter.Pa
 		return
 	}
 
-	if !json.Valid(body) {
-		respondWithJson(w, http.StatusBadRequest,
+	var cat model.Cat
+	if name, err := decodeBody(r, body, &cat); err != nil {
+		respond(w, r, http.StatusBadRequest,
 			Response{
 				Error: Error{
 					Status:  http.StatusBadRequest,
-					Message: "invalid json in request body"},
+					Message: fmt.Sprintf("invalid %s in request body", name)},
 			})
-		log.Warn().Msgf("received invalid json in request body: %v", err)
+		log.Warn().Msgf("received invalid %s in request body: %v", name, err)
 		return
 	}
+	if invalidBody(w, r, a.prepareCat(&cat, time.Now())) {
+		return
+	}
+	body, _ = json.Marshal(cat)
 
-	if id, err := a.Storage.Insert(body); err != nil {
+	if id, err := a.storage(r.Context()).Insert(body); err != nil {
+		if quotaExceeded(w, r, err) || microchipExists(w, r, err) {
+			return
+		}
 		log.Info().Msgf("error storing cat %s: %v", string(body), err)
-		respondWithJson(w, http.StatusInternalServerError, Response{
+		respond(w, r, http.StatusInternalServerError, Response{
 			Error: Error{
 				Status:  http.StatusInternalServerError,
 				Message: "error storing cat"},
 		})
 		return
 	} else {
-		respondWithJson(w, http.StatusCreated,
+		cat.ID = id
+		a.publish(r.Context(), events.Created, id, &cat)
+		respond(w, r, http.StatusCreated,
 			Response{
 				Result: id,
 			},
@@ -65,21 +131,110 @@ func (a *App) CreateCat(w http.ResponseWriter, r *http.Request, ps httprouter.Pa
 	}
 }
 
+// maxBulkCats bounds the number of cats created by a single bulk request.
+const maxBulkCats = 1000
+
+// MassCreateCat creates every cat in the request body at once, responding with their ids
+// in the order given.
+func (a *App) MassCreateCat(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		respond(w, r, http.StatusInternalServerError, Response{
+			Error: Error{
+				Status:  http.StatusInternalServerError,
+				Message: "error reading body"},
+		})
+		log.Info().Msgf("error reading body: %v", err)
+		return
+	}
+
+	var cats []model.Cat
+	if name, err := decodeBody(r, body, &cats); err != nil {
+		respond(w, r, http.StatusBadRequest, Response{
+			Error: Error{
+				Status:  http.StatusBadRequest,
+				Message: fmt.Sprintf("invalid %s in request body", name)},
+		})
+		log.Warn().Msgf("received invalid %s in request body: %v", name, err)
+		return
+	}
+	if len(cats) == 0 || len(cats) > maxBulkCats {
+		respond(w, r, http.StatusBadRequest, Response{
+			Error: Error{
+				Status:  http.StatusBadRequest,
+				Message: fmt.Sprintf("request body must hold between 1 and %d cats", maxBulkCats)},
+		})
+		return
+	}
+	now := time.Now()
+	for i := range cats {
+		if err := a.prepareCat(&cats[i], now); err != nil {
+			respond(w, r, http.StatusBadRequest, Response{
+				Error: Error{
+					Status:  http.StatusBadRequest,
+					Message: fmt.Sprintf("cat %d: %v", i, err)},
+			})
+			return
+		}
+	}
+
+	ids, err := a.insertCats(a.storage(r.Context()), cats)
+	if err != nil {
+		if quotaExceeded(w, r, err) || microchipExists(w, r, err) {
+			return
+		}
+		log.Info().Msgf("error storing %d cats: %v", len(cats), err)
+		respond(w, r, http.StatusInternalServerError, Response{
+			Error: Error{
+				Status:  http.StatusInternalServerError,
+				Message: "error storing cats"},
+		})
+		return
+	}
+	for i := range cats {
+		cats[i].ID = ids[i]
+		a.publish(r.Context(), events.Created, ids[i], &cats[i])
+	}
+	respond(w, r, http.StatusCreated, Response{Result: ids})
+}
+
+// insertCats inserts cats in a single transaction when the storage is able to, and one by
+// one otherwise.
+func (a *App) insertCats(storage model.Storage, cats []model.Cat) ([]string, error) {
+	if b, ok := storage.(model.BatchInserter); ok {
+		return b.InsertBatch(cats)
+	}
+	ids := make([]string, 0, len(cats))
+	for _, cat := range cats {
+		body, _ := json.Marshal(cat)
+		id, err := storage.Insert(body)
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
 func (a *App) GetCat(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	cat, err := a.Storage.Select(ps.ByName("id"))
+	b, err := a.selectCat(r.Context(), ps.ByName("id"))
+	var cat model.Cat
+	if err == nil {
+		err = json.Unmarshal(b, &cat)
+	}
 	switch err {
 	case nil:
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write(cat)
+		cat.Photos = a.catPhotos(r.Context(), ps.ByName("id"))
+		respond(w, r, http.StatusOK, cat)
 	case sql.ErrNoRows:
-		respondWithJson(w, http.StatusNotFound, Response{Result: "cat not found"})
+		respond(w, r, http.StatusNotFound, Response{Result: "cat not found"})
 	default:
 		log.Debug().Msgf("error getting cat: %v", err)
-		respondWithJson(w, http.StatusInternalServerError, Response{Result: "error getting cat"})
+		respond(w, r, http.StatusInternalServerError, Response{Result: "error getting cat"})
 	}
 }
 
+// GetCats lists a page of cats, optionally filtered.
 func (a *App) GetCats(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	count, _ := strconv.Atoi(r.FormValue("count"))
 	start, _ := strconv.Atoi(r.FormValue("start"))
@@ -92,18 +247,31 @@ func (a *App) GetCats(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 		start = 0
 	}
 
-	all, err := a.Storage.SelectAll(count, start)
+	filter, err := catFilter(r)
+	if err != nil {
+		respond(w, r, http.StatusBadRequest, Response{
+			Error: Error{
+				Status:  http.StatusBadRequest,
+				Message: err.Error()},
+		})
+		return
+	}
+
+	cats := []model.Cat{}
+	if filter.Empty() {
+		var all []byte
+		all, err = a.storage(r.Context()).SelectAll(count, start)
+		if err == nil {
+			err = json.Unmarshal(all, &cats)
+		}
+	} else {
+		cats, err = selectFiltered(r.Context(), a.storage(r.Context()), filter, count, start)
+	}
 	switch err {
-	case nil:
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write(all)
-	case sql.ErrNoRows:
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		_, _ = w.Write([]byte(`[]`))
+	case nil, sql.ErrNoRows:
+		respond(w, r, http.StatusOK, cats)
 	default:
-		respondWithJson(w, http.StatusInternalServerError, Response{
+		respond(w, r, http.StatusInternalServerError, Response{
 			Error: Error{
 				Status:  http.StatusInternalServerError,
 				Message: err.Error()},
@@ -111,10 +279,78 @@ func (a *App) GetCats(w http.ResponseWriter, r *http.Request, ps httprouter.Para
 	}
 }
 
+// errPageFull stops iterating over cats once a page has been collected.
+var errPageFull = errors.New("page full")
+
+// selectFiltered selects a page of the cats matching a filter, in the order storage
+// iterates over them.
+func selectFiltered(ctx context.Context, storage model.Storage, filter model.Filter, count, start int) ([]model.Cat, error) {
+	cats := []model.Cat{}
+	skipped := 0
+	err := model.Each(ctx, storage, filter, func(cat model.Cat) error {
+		if skipped < start {
+			skipped++
+			return nil
+		}
+		cats = append(cats, cat)
+		if len(cats) == count {
+			return errPageFull
+		}
+		return nil
+	})
+	if err == errPageFull {
+		err = nil
+	}
+	return cats, err
+}
+
+// catFilter reads the filter of cats from the query parameters, tags being separated by
+// commas.
+func catFilter(r *http.Request) (model.Filter, error) {
+	q := r.URL.Query()
+	filter := model.Filter{
+		Name:      q.Get("name"),
+		Color:     q.Get("color"),
+		Breed:     q.Get("breed"),
+		Sex:       q.Get("sex"),
+		Microchip: q.Get("microchip"),
+		Status:    q.Get("status"),
+	}
+	if filter.Status != "" && !model.ValidStatus(filter.Status) {
+		return filter, fmt.Errorf("invalid status %q, expected one of %s", filter.Status, strings.Join(model.Statuses, ", "))
+	}
+	for param, bound := range map[string]**int{"minAge": &filter.MinAge, "maxAge": &filter.MaxAge} {
+		s := q.Get(param)
+		if s == "" {
+			continue
+		}
+		age, err := strconv.Atoi(s)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s %q, expected an integer", param, s)
+		}
+		*bound = &age
+	}
+	if s := q.Get("neutered"); s != "" {
+		neutered, err := strconv.ParseBool(s)
+		if err != nil {
+			return filter, fmt.Errorf("invalid neutered %q, expected true or false", s)
+		}
+		filter.Neutered = &neutered
+	}
+	if s := q.Get("tags"); s != "" {
+		for _, tag := range strings.Split(s, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				filter.Tags = append(filter.Tags, tag)
+			}
+		}
+	}
+	return filter, nil
+}
+
 func (a *App) UpdateCat(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	body, err := ioutil.ReadAll(r.Body)
 	if err != nil {
-		respondWithJson(w, http.StatusInternalServerError, Response{
+		respond(w, r, http.StatusInternalServerError, Response{
 			Error: Error{
 				Status:  http.StatusInternalServerError,
 				Message: "error reading body",
@@ -123,32 +359,42 @@ func (a *App) UpdateCat(w http.ResponseWriter, r *http.Request, ps httprouter.Pa
 		return
 	}
 
-	if !json.Valid(body) {
-		respondWithJson(w, http.StatusBadRequest,
+	var cat model.Cat
+	if name, err := decodeBody(r, body, &cat); err != nil {
+		respond(w, r, http.StatusBadRequest,
 			Response{
 				Error: Error{
 					Status:  http.StatusBadRequest,
-					Message: "invalid json in request body"},
+					Message: fmt.Sprintf("invalid %s in request body", name)},
 			})
-		log.Warn().Msgf("received invalid json in request body: %v", err)
+		log.Warn().Msgf("received invalid %s in request body: %v", name, err)
 		return
 	}
+	if invalidBody(w, r, a.prepareCat(&cat, time.Now())) {
+		return
+	}
+	body, _ = json.Marshal(cat)
 
 	id := ps.ByName("id")
-	err = a.Storage.Update(id, body)
+	err = a.storage(r.Context()).Update(id, body)
+	if microchipExists(w, r, err) {
+		return
+	}
 	switch err {
 	case nil:
-		respondWithJson(w, http.StatusOK, Response{
+		cat.ID = id
+		a.publish(r.Context(), events.Updated, id, &cat)
+		respond(w, r, http.StatusOK, Response{
 			Result: "success",
 		})
 	case sql.ErrNoRows:
-		respondWithJson(w, http.StatusNotFound, Response{
+		respond(w, r, http.StatusNotFound, Response{
 			Error: Error{
 				Status:  http.StatusNotFound,
 				Message: fmt.Sprintf("cat id %s not found", id)},
 		})
 	default:
-		respondWithJson(w, http.StatusInternalServerError, Response{Result: "error storing object"})
+		respond(w, r, http.StatusInternalServerError, Response{Result: "error storing object"})
 
 	}
 }
@@ -156,26 +402,95 @@ func (a *App) UpdateCat(w http.ResponseWriter, r *http.Request, ps httprouter.Pa
 func (a *App) DeleteCat(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	id := ps.ByName("id")
 	if uuid.FromStringOrNil(id) == uuid.Nil {
-		respondWithJson(w, http.StatusBadRequest, Response{
+		respond(w, r, http.StatusBadRequest, Response{
 			Error: Error{
 				Status:  http.StatusBadRequest,
 				Message: fmt.Sprintf("invalid cat id: %s", id)},
 		})
 		return
 	}
-	err := a.Storage.Delete(id)
+	// the photos of the cat are deleted along with it, leaving their files to be deleted
+	photos := a.catPhotos(r.Context(), id)
+	err := a.storage(r.Context()).Delete(id)
 	switch err {
 	case nil:
-		respondWithJson(w, http.StatusOK, Response{Result: "success"})
+		a.deletePhotoFiles(r.Context(), photos)
+		a.publish(r.Context(), events.Deleted, id, nil)
+		respond(w, r, http.StatusOK, Response{Result: "success"})
 	case sql.ErrNoRows:
-		respondWithJson(w, http.StatusNotFound, Response{
+		respond(w, r, http.StatusNotFound, Response{
 			Error: Error{
 				Status:  http.StatusNotFound,
 				Message: fmt.Sprintf("cat id %s not found", id)},
 		})
 		log.Debug().Msgf("error getting cat: %v", err)
 	default:
-		respondWithJson(w, http.StatusInternalServerError, Response{Result: err.Error()})
+		respond(w, r, http.StatusInternalServerError, Response{Result: err.Error()})
 		log.Debug().Msgf("error getting cat: %v", err)
 	}
 }
+
+// invalidBody reports whether err is a request body failing validation, responding with a 400
+// detailing every invalid field when it is.
+func invalidBody(w http.ResponseWriter, r *http.Request, err error) bool {
+	if err == nil {
+		return false
+	}
+	e := Error{Status: http.StatusBadRequest, Message: err.Error()}
+	var fields model.ValidationError
+	if errors.As(err, &fields) {
+		for _, f := range fields {
+			e.Details = append(e.Details, ErrorDetail{Location: "body", Field: f.Field, Reason: f.Reason})
+		}
+	}
+	respond(w, r, http.StatusBadRequest, Response{Error: e})
+	return true
+}
+
+// microchipExists reports whether err is a cat being stored with the microchip of another,
+// responding with a 409 when it is.
+func microchipExists(w http.ResponseWriter, r *http.Request, err error) bool {
+	if err != model.ErrMicrochipExists {
+		return false
+	}
+	respond(w, r, http.StatusConflict, Response{
+		Error: Error{
+			Status:  http.StatusConflict,
+			Message: err.Error()},
+	})
+	return true
+}
+
+// selectCat selects a cat of the tenant of a request, reading through the cache when one
+// is configured.
+func (a *App) selectCat(ctx context.Context, id string) ([]byte, error) {
+	storage := a.storage(ctx)
+	if a.Cache == nil {
+		return storage.Select(id)
+	}
+	key := cacheKey(tenantFrom(ctx), id)
+	if b, err := a.Cache.Get(key); err == nil {
+		return b, nil
+	}
+	b, err := storage.Select(id)
+	if err == nil {
+		if err := a.Cache.Set(key, b); err != nil {
+			log.Debug().Msgf("unable to cache cat %s: %v", id, err)
+		}
+	}
+	return b, err
+}
+
+// publish announces a change to a cat of the tenant of a request to the app's subscribers.
+// Its delivery to webhooks is queued by the storage, along with the change.
+func (a *App) publish(ctx context.Context, t events.Type, id string, cat *model.Cat) {
+	tenant := tenantFrom(ctx)
+	if a.Cache != nil {
+		_ = a.Cache.Delete(cacheKey(tenant, id))
+	}
+	e := events.New(t, id, cat)
+	e.Tenant = tenant
+	if a.Events != nil {
+		a.Events.Publish(e)
+	}
+}
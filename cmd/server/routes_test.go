@@ -11,6 +11,8 @@ import (
 
 	"github.com/golang/mock/gomock"
 	json "github.com/json-iterator/go"
+	"github.com/waikco/cats-v1/buildinfo"
+	"github.com/waikco/cats-v1/healthcheck"
 	"github.com/waikco/cats-v1/model"
 )
 
@@ -82,6 +84,41 @@ func TestApp_CreateCat(t *testing.T) {
 			},
 			expectedMockCalls: 1,
 		},
+		{
+			description:    "unsuccessful pet creation from invalid details",
+			requestBody:    []byte(`{"name":"cat-1","color":"color-1","sex":"unknown","weightKg":31}`),
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: Response{
+				Error: Error{
+					Status:  http.StatusBadRequest,
+					Message: "invalid cat: sex must be female or male, weightKg must be between 0 and 30",
+					Details: []ErrorDetail{
+						{Location: "body", Field: "sex", Reason: "must be female or male"},
+						{Location: "body", Field: "weightKg", Reason: "must be between 0 and 30"},
+					},
+				},
+			},
+			expectedMockCalls: 0,
+		},
+		{
+			description:    "unsuccessful pet creation from a microchip in use",
+			requestBody:    []byte(`{"name":"cat-1","color":"color-1","microchip":"985112345678901"}`),
+			expectedStatus: http.StatusConflict,
+			expectedResponse: Response{
+				Error: Error{
+					Status:  http.StatusConflict,
+					Message: model.ErrMicrochipExists.Error(),
+				},
+			},
+			mockResponse: struct {
+				one string
+				two error
+			}{
+				one: "",
+				two: model.ErrMicrochipExists,
+			},
+			expectedMockCalls: 1,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.description, func(t *testing.T) {
@@ -358,6 +395,53 @@ func TestApp_GetCats(t *testing.T) {
 				two: sql.ErrNoRows},
 			expectedMockCalls: 1,
 		},
+		{
+			description:    "filtered",
+			request:        "/cats/v1/cats?breed=Siamese&tags=indoor",
+			expectedStatus: http.StatusOK,
+			expectedResponse: []model.Cat{{
+				Name:  "cat-2",
+				Color: "color-2",
+				Breed: "siamese",
+				Tags:  []string{"indoor", "shy"}},
+			},
+			mockResponse: struct {
+				one []byte
+				two error
+			}{
+				one: []byte(`[{"name": "cat-1", "color": "color-1", "breed": "siamese"},` +
+					`{"name": "cat-2", "color": "color-2", "breed": "siamese", "tags": ["indoor", "shy"]}]`),
+				two: nil},
+			expectedMockCalls: 1,
+		},
+		{
+			description:    "filtered by status",
+			request:        "/cats/v1/cats?status=available",
+			expectedStatus: http.StatusOK,
+			expectedResponse: []model.Cat{{
+				Name:   "cat-1",
+				Color:  "color-1",
+				Status: "available"},
+			},
+			mockResponse: struct {
+				one []byte
+				two error
+			}{
+				one: []byte(`[{"name": "cat-1", "color": "color-1", "status": "available"},` +
+					`{"name": "cat-2", "color": "color-2", "status": "adopted"}]`),
+				two: nil},
+			expectedMockCalls: 1,
+		},
+		{
+			description:    "invalid filter",
+			request:        "/cats/v1/cats?neutered=maybe",
+			expectedStatus: http.StatusBadRequest,
+			expectedResponse: Response{
+				Error: Error{
+					Status:  http.StatusBadRequest,
+					Message: `invalid neutered "maybe", expected true or false`},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.description, func(t *testing.T) {
@@ -544,3 +628,101 @@ func TestApp_UpdateCat(t *testing.T) {
 		})
 	}
 }
+
+func TestApp_Ready(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		mockResponse error
+		cacheSize    int
+		// then
+		expectedStatus       int
+		expectedReportStatus string
+		expectedComponents   []string
+	}{
+		{
+			description:          "storage available",
+			mockResponse:         nil,
+			expectedStatus:       http.StatusOK,
+			expectedReportStatus: healthcheck.StatusUp,
+			expectedComponents:   []string{"storage"},
+		},
+		{
+			description:          "storage unavailable",
+			mockResponse:         errors.New("connection refused"),
+			expectedStatus:       http.StatusServiceUnavailable,
+			expectedReportStatus: healthcheck.StatusDown,
+			expectedComponents:   []string{"storage"},
+		},
+		{
+			description:          "storage and cache available",
+			mockResponse:         nil,
+			cacheSize:            1024 * 1024,
+			expectedStatus:       http.StatusOK,
+			expectedReportStatus: healthcheck.StatusUp,
+			expectedComponents:   []string{"cache", "storage"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			var a App
+			a.Config.Cache.Size = tt.cacheSize
+			a.BootstrapServer()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockStorage(ctrl)
+			s.EXPECT().
+				Status().
+				Return(tt.mockResponse).
+				Times(1)
+			a.Storage = s
+
+			req := httptest.NewRequest(http.MethodGet, "/cats/v1/ready", nil)
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d", response.Code, tt.expectedStatus)
+			}
+
+			var got healthcheck.Report
+			err := json.Unmarshal(response.Body.Bytes(), &got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Status != tt.expectedReportStatus {
+				t.Errorf("unxpected report status: got %s, expected %s", got.Status, tt.expectedReportStatus)
+			}
+			var components []string
+			for _, c := range got.Components {
+				components = append(components, c.Name)
+			}
+			if !reflect.DeepEqual(components, tt.expectedComponents) {
+				t.Errorf("unxpected components: got %v, expected %v", components, tt.expectedComponents)
+			}
+		})
+	}
+}
+
+func TestApp_Version(t *testing.T) {
+	var a App
+	a.BootstrapServer()
+
+	req := httptest.NewRequest(http.MethodGet, "/cats/v1/version", nil)
+	response := httptest.NewRecorder()
+	a.Router.ServeHTTP(response, req)
+
+	if response.Code != http.StatusOK {
+		t.Errorf("unxpected status code: got %d, expected %d", response.Code, http.StatusOK)
+	}
+
+	var got buildinfo.Info
+	err := json.Unmarshal(response.Body.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, buildinfo.Get()) {
+		t.Errorf("unxpected response got: got %v, expected %v", got, buildinfo.Get())
+	}
+}
@@ -1,9 +1,16 @@
 package server
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"strings"
 
 	json "github.com/json-iterator/go"
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog/log"
+	"github.com/waikco/cats-v1/codec"
+	"github.com/waikco/cats-v1/model"
 )
 
 //respondWithJson wraps a message into json and returns it in the Response,along with a header and Response code
@@ -15,12 +22,116 @@ func respondWithJson(w http.ResponseWriter, code int, message interface{}) {
 	_, _ = w.Write(response)
 }
 
+// respondError responds with an error, formatting its message, in the media type negotiated
+// for the request.
+func respondError(w http.ResponseWriter, r *http.Request, status int, format string, args ...interface{}) {
+	respond(w, r, status, Response{
+		Error: Error{
+			Status:  status,
+			Message: fmt.Sprintf(format, args...)},
+	})
+}
+
+// optional returns storage as the optional interface S, responding with a 501 telling that
+// feature is not supported when the storage does not implement it.
+func optional[S any](w http.ResponseWriter, r *http.Request, storage model.Storage, feature string) (S, bool) {
+	store, ok := storage.(S)
+	if !ok {
+		respondError(w, r, http.StatusNotImplemented, "%s are not supported by the configured storage", feature)
+	}
+	return store, ok
+}
+
 type Response struct {
-	Result interface{} `json:"result,omitempty"`
-	Error  Error       `json:"error,omitempty"`
+	Result interface{} `json:"result,omitempty" xml:"result,omitempty"`
+	Error  Error       `json:"error,omitempty" xml:"error,omitempty"`
 }
 
 type Error struct {
-	Status  int         `json:"status,omitempty"`
-	Message interface{} `json:"message,omitempty"`
+	Status  int           `json:"status,omitempty" xml:"status,omitempty"`
+	Message interface{}   `json:"message,omitempty" xml:"message,omitempty"`
+	Details []ErrorDetail `json:"details,omitempty" xml:"details,omitempty"`
+}
+
+// ErrorDetail describes a single problem with a request, such as a field failing validation.
+type ErrorDetail struct {
+	Location string `json:"location,omitempty" xml:"location,omitempty"`
+	Field    string `json:"field,omitempty" xml:"field,omitempty"`
+	Reason   string `json:"reason" xml:"reason"`
+}
+
+// codecs are the media types the api is spoken in, json being used when clients have no preference.
+var codecs = codec.Default()
+
+type negotiatedKey struct{}
+
+// negotiated holds the codecs picked for a request body and its response.
+type negotiated struct {
+	request, response codec.Codec
+}
+
+// negotiate picks the codecs of a request from its Content-Type and Accept headers, responding
+// 415 to bodies in unsupported media types and 406 when no supported media type is accepted.
+func negotiate(next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		n := negotiated{request: codec.JSON}
+		if ct := r.Header.Get("Content-Type"); ct != "" && r.ContentLength != 0 {
+			c, ok := codecs.ForContentType(ct)
+			if !ok {
+				respondWithJson(w, http.StatusUnsupportedMediaType, Response{
+					Error: Error{
+						Status:  http.StatusUnsupportedMediaType,
+						Message: fmt.Sprintf("unsupported content type %s, use one of %s", ct, strings.Join(codecs.ContentTypes(), ", "))},
+				})
+				return
+			}
+			n.request = c
+		}
+
+		c, ok := codecs.Negotiate(r.Header.Get("Accept"))
+		if !ok {
+			respondWithJson(w, http.StatusNotAcceptable, Response{
+				Error: Error{
+					Status:  http.StatusNotAcceptable,
+					Message: fmt.Sprintf("unable to respond with %s, use one of %s", r.Header.Get("Accept"), strings.Join(codecs.ContentTypes(), ", "))},
+			})
+			return
+		}
+		n.response = c
+
+		next(w, r.WithContext(context.WithValue(r.Context(), negotiatedKey{}, n)), ps)
+	}
+}
+
+func negotiatedCodecs(r *http.Request) negotiated {
+	if n, ok := r.Context().Value(negotiatedKey{}).(negotiated); ok {
+		return n
+	}
+	return negotiated{request: codec.JSON, response: codec.JSON}
+}
+
+// respond encodes a message in the media type negotiated for the request, along with a header and response code.
+func respond(w http.ResponseWriter, r *http.Request, code int, message interface{}) {
+	c := negotiatedCodecs(r).response
+	response, err := c.Marshal(message)
+	if err != nil {
+		log.Error().Msgf("error encoding response as %s: %v", c.Name(), err)
+		respondWithJson(w, http.StatusInternalServerError, Response{
+			Error: Error{
+				Status:  http.StatusInternalServerError,
+				Message: "error encoding response"},
+		})
+		return
+	}
+
+	w.Header().Set("Content-Type", c.ContentType())
+	w.Header().Set("Vary", "Accept")
+	w.WriteHeader(code)
+	_, _ = w.Write(response)
+}
+
+// decodeBody decodes a request body in its negotiated media type, returning the name of that media type.
+func decodeBody(r *http.Request, body []byte, v interface{}) (string, error) {
+	c := negotiatedCodecs(r).request
+	return c.Name(), c.Unmarshal(body, v)
 }
@@ -0,0 +1,172 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/codec"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/model"
+)
+
+func TestApp_Encodings(t *testing.T) {
+	a := App{Config: conf.SaneDefaults()}
+	a.BootstrapServer()
+
+	cat := model.Cat{Name: "cat-1", Color: "color-1", Age: 1}
+	encode := func(c codec.Codec, v interface{}) []byte {
+		b, err := c.Marshal(v)
+		if err != nil {
+			t.Fatalf("unxpected error: %v", err)
+		}
+		return b
+	}
+
+	tests := []struct {
+		description string
+		// given
+		method      string
+		request     string
+		accept      string
+		contentType string
+		requestBody []byte
+		// then
+		expectedStatus      int
+		expectedContentType string
+		expectedBody        []byte
+		expectedMockCalls   int
+	}{
+		{
+			description:         "cat as xml",
+			method:              http.MethodGet,
+			request:             "/cats/v1/cats/fe271e7e-83ca-477b-92fc-d0c3fa602d7d",
+			accept:              "application/xml",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/xml",
+			expectedBody:        encode(codec.XML, cat),
+			expectedMockCalls:   1,
+		},
+		{
+			description:         "cat as messagepack from an alias",
+			method:              http.MethodGet,
+			request:             "/cats/v1/cats/fe271e7e-83ca-477b-92fc-d0c3fa602d7d",
+			accept:              "application/x-msgpack",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/msgpack",
+			expectedBody:        encode(codec.MessagePack, cat),
+			expectedMockCalls:   1,
+		},
+		{
+			description:         "cat as cbor",
+			method:              http.MethodGet,
+			request:             "/cats/v1/cats/fe271e7e-83ca-477b-92fc-d0c3fa602d7d",
+			accept:              "application/cbor",
+			expectedStatus:      http.StatusOK,
+			expectedContentType: "application/cbor",
+			expectedBody:        encode(codec.CBOR, cat),
+			expectedMockCalls:   1,
+		},
+		{
+			description:         "unacceptable media type",
+			method:              http.MethodGet,
+			request:             "/cats/v1/cats/fe271e7e-83ca-477b-92fc-d0c3fa602d7d",
+			accept:              "text/html",
+			expectedStatus:      http.StatusNotAcceptable,
+			expectedContentType: "application/json",
+			expectedBody: []byte(`{"error":{"status":406,"message":"unable to respond with text/html, use one of ` +
+				`application/json, application/xml, application/msgpack, application/cbor, text/xml, ` +
+				`application/x-msgpack, application/vnd.msgpack"}}`),
+		},
+		{
+			description:         "cat created from cbor",
+			method:              http.MethodPost,
+			request:             "/cats/v1/",
+			contentType:         "application/cbor",
+			requestBody:         encode(codec.CBOR, cat),
+			expectedStatus:      http.StatusCreated,
+			expectedContentType: "application/json",
+			expectedBody:        []byte(`{"result":"fe271e7e-83ca-477b-92fc-d0c3fa602d7d","error":{}}`),
+			expectedMockCalls:   1,
+		},
+		{
+			description:         "cat created from xml, responding in xml",
+			method:              http.MethodPost,
+			request:             "/cats/v1/",
+			accept:              "application/xml",
+			contentType:         "text/xml; charset=utf-8",
+			requestBody:         []byte(`<cat><name>cat-1</name><color>color-1</color><age>1</age></cat>`),
+			expectedStatus:      http.StatusCreated,
+			expectedContentType: "application/xml",
+			expectedBody: []byte(`<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<response><result>fe271e7e-83ca-477b-92fc-d0c3fa602d7d</result><error></error></response>`),
+			expectedMockCalls: 1,
+		},
+		{
+			description:         "invalid messagepack",
+			method:              http.MethodPost,
+			request:             "/cats/v1/",
+			contentType:         "application/msgpack",
+			requestBody:         []byte{0xc1},
+			expectedStatus:      http.StatusBadRequest,
+			expectedContentType: "application/json",
+		},
+		{
+			description:         "unsupported media type",
+			method:              http.MethodPost,
+			request:             "/cats/v1/",
+			contentType:         "text/plain",
+			requestBody:         []byte(`cat-1`),
+			expectedStatus:      http.StatusUnsupportedMediaType,
+			expectedContentType: "application/json",
+			expectedBody: []byte(`{"error":{"status":415,"message":"unsupported content type text/plain, use one of ` +
+				`application/json, application/xml, application/msgpack, application/cbor, text/xml, ` +
+				`application/x-msgpack, application/vnd.msgpack"}}`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockStorage(ctrl)
+			s.EXPECT().
+				Select(gomock.Any()).
+				Return([]byte(`{"name":"cat-1","color":"color-1","age":1}`), nil).
+				Times(tt.expectedMockCalls * btoi(tt.method == http.MethodGet))
+			s.EXPECT().
+				Insert([]byte(`{"name":"cat-1","color":"color-1","age":1}`)).
+				Return("fe271e7e-83ca-477b-92fc-d0c3fa602d7d", nil).
+				Times(tt.expectedMockCalls * btoi(tt.method == http.MethodPost))
+			a.Storage = s
+
+			req := httptest.NewRequest(tt.method, tt.request, bytes.NewReader(tt.requestBody))
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, tt.expectedStatus, response.Body.Bytes())
+			}
+			if got := response.Header().Get("Content-Type"); got != tt.expectedContentType {
+				t.Errorf("unxpected content type: got %s, expected %s", got, tt.expectedContentType)
+			}
+			if tt.expectedBody != nil && !bytes.Equal(response.Body.Bytes(), tt.expectedBody) {
+				t.Errorf("unxpected body: got %q, expected %q", response.Body.Bytes(), tt.expectedBody)
+			}
+		})
+	}
+}
+
+func btoi(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
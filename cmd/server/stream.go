@@ -0,0 +1,253 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+	json "github.com/json-iterator/go"
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog/log"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/model"
+)
+
+const (
+	// DefaultHeartbeat is how often idle streams are sent a heartbeat.
+	DefaultHeartbeat = 15 * time.Second
+
+	// sseRetry is the reconnection delay suggested to server-sent event clients, in milliseconds.
+	sseRetry = 3000
+	// resetEvent tells clients events may have been missed since the one they resumed from.
+	resetEvent = "reset"
+	// streamWriteTimeout bounds every websocket write.
+	streamWriteTimeout = 10 * time.Second
+)
+
+var upgrader = websocket.Upgrader{}
+
+// streamFilter selects the events sent to a stream.
+type streamFilter struct {
+	tenant string
+	types  map[events.Type]bool
+	cats   model.Filter
+}
+
+// matches reports whether an event passes the filter. Deleted cats are gone, so their
+// events pass any cat filter.
+func (f streamFilter) matches(e events.Event) bool {
+	if e.Tenant != f.tenant {
+		return false
+	}
+	if len(f.types) > 0 && !f.types[e.Type] {
+		return false
+	}
+	return e.Cat == nil || f.cats.Matches(*e.Cat)
+}
+
+// streamOr serves the change stream at /cats/v1/cats/stream, which the router is unable to
+// tell apart from the id of a cat, and otherwise calls next.
+func (a *App) streamOr(next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if ps.ByName("id") == "stream" {
+			a.Stream(w, r, ps)
+			return
+		}
+		next(w, r, ps)
+	}
+}
+
+// Stream pushes the creation, update and deletion of cats as server-sent events, or over a
+// websocket when the request is an upgrade. Clients resume after the event given in the
+// Last-Event-ID header or lastEventId query parameter, replayed from the bus history.
+// Clients falling too far behind are disconnected, so they resume where they left off.
+func (a *App) Stream(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	filter, err := newStreamFilter(r)
+	if err != nil {
+		respondWithJson(w, http.StatusBadRequest, Response{
+			Error: Error{
+				Status:  http.StatusBadRequest,
+				Message: err.Error()},
+		})
+		return
+	}
+
+	lastID := r.Header.Get("Last-Event-ID")
+	if lastID == "" {
+		lastID = r.URL.Query().Get("lastEventId")
+	}
+	heartbeat := a.Config.Stream.Heartbeat
+	if heartbeat <= 0 {
+		heartbeat = DefaultHeartbeat
+	}
+
+	if websocket.IsWebSocketUpgrade(r) {
+		// subscribed before the handshake completes, so no event after it is missed
+		sub := a.Events.Resume(lastID, a.Config.Stream.Buffer)
+		defer sub.Close()
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			// the upgrader has already responded
+			log.Debug().Msgf("websocket upgrade failed: %v", err)
+			return
+		}
+		streamWebSocket(conn, sub, filter, heartbeat)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondWithJson(w, http.StatusInternalServerError, Response{
+			Error: Error{
+				Status:  http.StatusInternalServerError,
+				Message: "streaming is not supported"},
+		})
+		return
+	}
+	sub := a.Events.Resume(lastID, a.Config.Stream.Buffer)
+	defer sub.Close()
+	streamSSE(w, r, flusher, sub, filter, heartbeat)
+}
+
+func newStreamFilter(r *http.Request) (streamFilter, error) {
+	cats, err := catFilter(r)
+	if err != nil {
+		return streamFilter{}, err
+	}
+	filter := streamFilter{tenant: tenantFrom(r.Context()), cats: cats}
+	if types := r.URL.Query().Get("types"); types != "" {
+		filter.types = map[events.Type]bool{}
+		for _, t := range strings.Split(types, ",") {
+			if !subscribableEvents[strings.TrimSpace(t)] {
+				return filter, fmt.Errorf("unknown event type %q, use cat.created, cat.updated or cat.deleted", t)
+			}
+			filter.types[events.Type(strings.TrimSpace(t))] = true
+		}
+	}
+	return filter, nil
+}
+
+// streamSSE writes events in the text/event-stream format until the client goes away or
+// falls behind.
+func streamSSE(w http.ResponseWriter, r *http.Request, flusher http.Flusher, sub *events.Subscription, filter streamFilter, heartbeat time.Duration) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if _, err := fmt.Fprintf(w, "retry: %d\n\n", sseRetry); err != nil {
+		return
+	}
+	if sub.Missed {
+		if _, err := fmt.Fprintf(w, "event: %s\ndata: {}\n\n", resetEvent); err != nil {
+			return
+		}
+	}
+
+	send := func(e events.Event) error {
+		if !filter.matches(e) {
+			return nil
+		}
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+		return err
+	}
+	for _, e := range sub.Replay {
+		if err := send(e); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case e, ok := <-sub.Events:
+			if !ok {
+				log.Debug().Msg("ended the event stream of a client falling behind")
+				return
+			}
+			if err := send(e); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// streamWebSocket writes events as json text messages, pinging the client as a heartbeat,
+// until the client goes away or falls behind.
+func streamWebSocket(conn *websocket.Conn, sub *events.Subscription, filter streamFilter, heartbeat time.Duration) {
+	defer func() { _ = conn.Close() }()
+
+	// clients only ever send control messages, which are handled while reading
+	gone := make(chan struct{})
+	_ = conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * heartbeat))
+	})
+	go func() {
+		defer close(gone)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	write := func(v interface{}) error {
+		_ = conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		return conn.WriteJSON(v)
+	}
+	send := func(e events.Event) error {
+		if !filter.matches(e) {
+			return nil
+		}
+		return write(e)
+	}
+	if sub.Missed {
+		if err := write(map[string]string{"type": resetEvent}); err != nil {
+			return
+		}
+	}
+	for _, e := range sub.Replay {
+		if err := send(e); err != nil {
+			return
+		}
+	}
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-gone:
+			return
+		case <-ticker.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(streamWriteTimeout)); err != nil {
+				return
+			}
+		case e, ok := <-sub.Events:
+			if !ok {
+				log.Debug().Msg("ended the websocket stream of a client falling behind")
+				_ = conn.WriteControl(websocket.CloseMessage,
+					websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "fell behind, resume from the last event received"),
+					time.Now().Add(streamWriteTimeout))
+				return
+			}
+			if err := send(e); err != nil {
+				return
+			}
+		}
+	}
+}
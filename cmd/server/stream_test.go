@@ -0,0 +1,170 @@
+package server
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	json "github.com/json-iterator/go"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/model"
+)
+
+func newStreamServer(t *testing.T, heartbeat time.Duration) (*App, *httptest.Server) {
+	t.Helper()
+	config := conf.SaneDefaults()
+	config.Stream.Heartbeat = heartbeat
+	a := &App{Config: config}
+	a.BootstrapServer()
+	return a, httptest.NewServer(a.Router)
+}
+
+// readSSE reads the next server-sent event, or comment, up to the blank line ending it.
+func readSSE(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("unxpected error reading the stream: %v", err)
+		}
+		if line == "\n" {
+			return strings.Join(lines, "\n")
+		}
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+}
+
+func openSSE(t *testing.T, url string, lastEventID string) (*http.Response, *bufio.Reader) {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodGet, url, nil)
+	req.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("unxpected status code: got %d, expected %d", resp.StatusCode, http.StatusOK)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("unxpected content type: got %s, expected text/event-stream", ct)
+	}
+	r := bufio.NewReader(resp.Body)
+	if got := readSSE(t, r); got != "retry: 3000" {
+		t.Errorf("unxpected first event: got %q, expected the retry delay", got)
+	}
+	return resp, r
+}
+
+func sseEvent(e events.Event) string {
+	data, _ := json.Marshal(e)
+	return "id: " + e.ID + "\nevent: " + string(e.Type) + "\ndata: " + string(data)
+}
+
+func TestApp_Stream_SSE(t *testing.T) {
+	a, server := newStreamServer(t, time.Hour)
+	defer server.Close()
+
+	resp, r := openSSE(t, server.URL+"/cats/v1/cats/stream?types=cat.created,cat.deleted&color=black", "")
+	defer func() { _ = resp.Body.Close() }()
+
+	grey := events.New(events.Created, "1", &model.Cat{ID: "1", Name: "tom", Color: "grey"})
+	updated := events.New(events.Updated, "2", &model.Cat{ID: "2", Name: "kitty", Color: "black"})
+	created := events.New(events.Created, "2", &model.Cat{ID: "2", Name: "kitty", Color: "black"})
+	deleted := events.New(events.Deleted, "1", nil)
+	for _, e := range []events.Event{grey, updated, created, deleted} {
+		a.Events.Publish(e)
+	}
+
+	for _, expected := range []events.Event{created, deleted} {
+		if got := readSSE(t, r); got != sseEvent(expected) {
+			t.Errorf("unxpected event: got %q, expected %q", got, sseEvent(expected))
+		}
+	}
+}
+
+func TestApp_Stream_Resume(t *testing.T) {
+	a, server := newStreamServer(t, time.Hour)
+	defer server.Close()
+
+	first := events.New(events.Created, "1", &model.Cat{ID: "1"})
+	second := events.New(events.Updated, "1", &model.Cat{ID: "1"})
+	a.Events.Publish(first)
+	a.Events.Publish(second)
+
+	t.Run("from the history", func(t *testing.T) {
+		resp, r := openSSE(t, server.URL+"/cats/v1/cats/stream", first.ID)
+		defer func() { _ = resp.Body.Close() }()
+		if got := readSSE(t, r); got != sseEvent(second) {
+			t.Errorf("unxpected event: got %q, expected %q", got, sseEvent(second))
+		}
+	})
+	t.Run("from beyond the history", func(t *testing.T) {
+		resp, r := openSSE(t, server.URL+"/cats/v1/cats/stream?lastEventId=unknown", "")
+		defer func() { _ = resp.Body.Close() }()
+		for _, expected := range []string{"event: reset\ndata: {}", sseEvent(first), sseEvent(second)} {
+			if got := readSSE(t, r); got != expected {
+				t.Errorf("unxpected event: got %q, expected %q", got, expected)
+			}
+		}
+	})
+}
+
+func TestApp_Stream_Heartbeat(t *testing.T) {
+	_, server := newStreamServer(t, 10*time.Millisecond)
+	defer server.Close()
+
+	resp, r := openSSE(t, server.URL+"/cats/v1/cats/stream", "")
+	defer func() { _ = resp.Body.Close() }()
+	if got := readSSE(t, r); got != ": heartbeat" {
+		t.Errorf("unxpected event: got %q, expected a heartbeat", got)
+	}
+}
+
+func TestApp_Stream_InvalidFilter(t *testing.T) {
+	_, server := newStreamServer(t, time.Hour)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/cats/v1/cats/stream?types=cat.adopted")
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("unxpected status code: got %d, expected %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestApp_Stream_WebSocket(t *testing.T) {
+	a, server := newStreamServer(t, time.Hour)
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/cats/v1/cats/stream?name=kitty"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	tom := events.New(events.Created, "1", &model.Cat{ID: "1", Name: "tom"})
+	kitty := events.New(events.Created, "2", &model.Cat{ID: "2", Name: "Kitty"})
+	a.Events.Publish(tom)
+	a.Events.Publish(kitty)
+
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var got events.Event
+	if err := conn.ReadJSON(&got); err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	if got.ID != kitty.ID || got.Type != events.Created || got.Cat == nil || got.Cat.Name != "Kitty" {
+		t.Errorf("unxpected event: got %+v, expected %+v", got, kitty)
+	}
+}
@@ -0,0 +1,306 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	json "github.com/json-iterator/go"
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+	"github.com/waikco/cats-v1/model"
+)
+
+// DefaultTenantHeader names the header holding the id of the tenant of a request.
+const DefaultTenantHeader = "X-Tenant-ID"
+
+var (
+	errNoTenant      = errors.New("an api key or tenant is required")
+	errUnknownTenant = errors.New("unknown api key or tenant")
+)
+
+type tenantKey struct{}
+
+// tenantFrom returns the tenant of a request, which is empty when tenancy is disabled.
+func tenantFrom(ctx context.Context) string {
+	tenant, _ := ctx.Value(tenantKey{}).(string)
+	return tenant
+}
+
+func withTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, tenantKey{}, tenant)
+}
+
+// storage returns the storage of the tenant of a request.
+func (a *App) storage(ctx context.Context) model.Storage {
+	return a.storageFor(tenantFrom(ctx))
+}
+
+// storageFor returns the storage scoped to a tenant, or the app's storage when the tenant
+// is empty or the storage is unable to scope queries.
+func (a *App) storageFor(tenant string) model.Storage {
+	if t, ok := a.Storage.(model.Tenanted); ok && tenant != "" {
+		return t.ForTenant(tenant)
+	}
+	return a.Storage
+}
+
+// cacheKey keys the cache of a cat by its tenant, the ids of cats being unique to a tenant
+// only as far as callers are concerned.
+func cacheKey(tenant, id string) string {
+	if tenant == "" {
+		tenant = model.DefaultTenant
+	}
+	return tenant + "/" + id
+}
+
+// validTenantID reports whether id is a uuid, the default tenant being the nil uuid.
+func validTenantID(id string) bool {
+	_, err := uuid.FromString(id)
+	return err == nil
+}
+
+// hashKey hashes api keys, which are only ever stored hashed.
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// bearer returns the token of a bearer authorization header.
+func bearer(authorization string) string {
+	const prefix = "Bearer "
+	if len(authorization) > len(prefix) && strings.EqualFold(authorization[:len(prefix)], prefix) {
+		return strings.TrimSpace(authorization[len(prefix):])
+	}
+	return ""
+}
+
+// resolveTenant returns the id of the tenant authenticated by the api key of a bearer
+// authorization, or named by the tenant header when it is trusted.
+func (a *App) resolveTenant(authorization, header string) (string, error) {
+	store, ok := a.Storage.(model.TenantStore)
+	if !ok {
+		return "", fmt.Errorf("tenants are not supported by the configured storage")
+	}
+	var tenant model.Tenant
+	var err error
+	switch key := bearer(authorization); {
+	case key != "":
+		tenant, err = store.SelectTenantByKey(hashKey(key))
+	case a.Config.Tenancy.TrustHeader && header != "":
+		if !validTenantID(header) {
+			return "", errUnknownTenant
+		}
+		tenant, err = store.SelectTenant(header)
+	default:
+		return "", errNoTenant
+	}
+	if err == sql.ErrNoRows {
+		return "", errUnknownTenant
+	}
+	return tenant.ID, err
+}
+
+// tenant scopes requests to their tenant when tenancy is enabled, responding with a 401 to
+// requests without a known one.
+func (a *App) tenant(next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		if !a.Config.Tenancy.Enabled {
+			next(w, r, ps)
+			return
+		}
+		tenant, err := a.resolveTenant(r.Header.Get("Authorization"), r.Header.Get(a.tenantHeader()))
+		switch err {
+		case nil:
+			next(w, r.WithContext(withTenant(r.Context(), tenant)), ps)
+		case errNoTenant, errUnknownTenant:
+			w.Header().Set("WWW-Authenticate", `Bearer realm="cats"`)
+			respondError(w, r, http.StatusUnauthorized, "%v", err)
+		default:
+			log.Error().Msgf("unable to resolve the tenant of a request: %v", err)
+			respondError(w, r, http.StatusInternalServerError, "unable to resolve tenant")
+		}
+	}
+}
+
+func (a *App) tenantHeader() string {
+	if a.Config.Tenancy.Header != "" {
+		return a.Config.Tenancy.Header
+	}
+	return DefaultTenantHeader
+}
+
+// admin authenticates requests to administer tenants by the configured admin token.
+func (a *App) admin(next httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		token := a.Config.Tenancy.AdminToken
+		if token == "" {
+			respondError(w, r, http.StatusForbidden, "tenant administration is disabled")
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(bearer(r.Header.Get("Authorization"))), []byte(token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="cats"`)
+			respondError(w, r, http.StatusUnauthorized, "an admin token is required")
+			return
+		}
+		next(w, r, ps)
+	}
+}
+
+// tenantStore returns the storage of tenants.
+func (a *App) tenantStore(w http.ResponseWriter, r *http.Request) (model.TenantStore, bool) {
+	return optional[model.TenantStore](w, r, a.Storage, "tenants")
+}
+
+// CreateTenant creates a tenant along with its api key, which is only ever returned in
+// this response.
+func (a *App) CreateTenant(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.tenantStore(w, r)
+	if !ok {
+		return
+	}
+
+	var tenant model.Tenant
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(body, &tenant)
+	}
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "invalid tenant in request body")
+		return
+	}
+	if strings.TrimSpace(tenant.Name) == "" {
+		respondError(w, r, http.StatusBadRequest, "name is required")
+		return
+	}
+	if tenant.MaxCats < 0 {
+		respondError(w, r, http.StatusBadRequest, "maxCats must not be negative")
+		return
+	}
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		respondError(w, r, http.StatusInternalServerError, "unable to generate an api key")
+		return
+	}
+	tenant.ID, tenant.APIKey = "", ""
+
+	id, err := store.InsertTenant(tenant, hashKey(hex.EncodeToString(key)))
+	if err == model.ErrTenantExists {
+		respondError(w, r, http.StatusConflict, "tenant %s already exists", tenant.Name)
+		return
+	}
+	if err == nil {
+		tenant, err = store.SelectTenant(id)
+	}
+	if err != nil {
+		log.Error().Msgf("error creating tenant %s: %v", tenant.Name, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to create tenant")
+		return
+	}
+	tenant.APIKey = hex.EncodeToString(key)
+	respondWithJson(w, http.StatusCreated, tenant)
+}
+
+// GetTenants lists every tenant.
+func (a *App) GetTenants(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.tenantStore(w, r)
+	if !ok {
+		return
+	}
+	tenants, err := store.SelectTenants()
+	if err != nil {
+		log.Error().Msgf("error getting tenants: %v", err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get tenants")
+		return
+	}
+	respondWithJson(w, http.StatusOK, tenants)
+}
+
+// GetTenant retrieves a tenant.
+func (a *App) GetTenant(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.tenantStore(w, r)
+	if !ok {
+		return
+	}
+	if tenant, ok := selectTenant(w, r, store, ps.ByName("id")); ok {
+		respondWithJson(w, http.StatusOK, tenant)
+	}
+}
+
+// tenantUpdate is the body of requests updating a tenant, whose quota is all that may change.
+type tenantUpdate struct {
+	MaxCats *int `json:"maxCats"`
+}
+
+// UpdateTenant sets the most cats a tenant may have. Cats the tenant already has over a
+// lowered quota are kept, but no more may be created.
+func (a *App) UpdateTenant(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.tenantStore(w, r)
+	if !ok {
+		return
+	}
+	var q tenantUpdate
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		err = json.Unmarshal(body, &q)
+	}
+	if err != nil || q.MaxCats == nil || *q.MaxCats < 0 {
+		respondError(w, r, http.StatusBadRequest, "maxCats must be a number of at least 0")
+		return
+	}
+	tenant, ok := selectTenant(w, r, store, ps.ByName("id"))
+	if !ok {
+		return
+	}
+	if err := store.UpdateTenantQuota(tenant.ID, *q.MaxCats); err != nil {
+		log.Error().Msgf("error updating quota of tenant %s: %v", tenant.ID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to update quota")
+		return
+	}
+	tenant.MaxCats = *q.MaxCats
+	respondWithJson(w, http.StatusOK, tenant)
+}
+
+// selectTenant selects a tenant, responding with a 400 for invalid ids and a 404 for
+// unknown ones.
+func selectTenant(w http.ResponseWriter, r *http.Request, store model.TenantStore, id string) (model.Tenant, bool) {
+	if !validTenantID(id) {
+		respondError(w, r, http.StatusBadRequest, "invalid tenant id: %s", id)
+		return model.Tenant{}, false
+	}
+	tenant, err := store.SelectTenant(id)
+	switch err {
+	case nil:
+		return tenant, true
+	case sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "tenant id %s not found", id)
+	default:
+		log.Error().Msgf("error getting tenant %s: %v", id, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get tenant")
+	}
+	return model.Tenant{}, false
+}
+
+// quotaExceeded reports whether err is a tenant going over its quota, responding with a
+// 403 when it is.
+func quotaExceeded(w http.ResponseWriter, r *http.Request, err error) bool {
+	var quota model.QuotaError
+	if !errors.As(err, &quota) {
+		return false
+	}
+	respond(w, r, http.StatusForbidden, Response{
+		Error: Error{
+			Status:  http.StatusForbidden,
+			Message: quota.Error()},
+	})
+	return true
+}
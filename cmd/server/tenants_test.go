@@ -0,0 +1,340 @@
+package server
+
+import (
+	"bufio"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	json "github.com/json-iterator/go"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/model"
+)
+
+const (
+	tenantID = "5b1f5a3e-2c4d-4e8f-9a1b-3c5d7e9f1a2b"
+	apiKey   = "secret-key"
+)
+
+// tenantStorage is storage able to persist tenants and scope cats to them.
+type tenantStorage struct {
+	*model.MockStorage
+	*model.MockTenantStore
+	*model.MockTenanted
+}
+
+func tenantConfig() conf.Config {
+	config := conf.SaneDefaults()
+	config.Tenancy.Enabled = true
+	config.Tenancy.AdminToken = "admin"
+	return config
+}
+
+func TestApp_Tenant(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		trustHeader bool
+		method      string
+		url         string
+		body        string
+		headers     map[string]string
+		mock        func(tenants *model.MockTenantStore, scoped *model.MockStorage)
+		// then
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description:    "without an api key",
+			method:         http.MethodGet,
+			url:            "/cats/v1/cats",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"error":{"status":401,"message":"an api key or tenant is required"}}`,
+		},
+		{
+			description: "with an unknown api key",
+			method:      http.MethodGet,
+			url:         "/cats/v1/cats",
+			headers:     map[string]string{"Authorization": "Bearer other"},
+			mock: func(tenants *model.MockTenantStore, scoped *model.MockStorage) {
+				tenants.EXPECT().SelectTenantByKey(hashKey("other")).Return(model.Tenant{}, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			description: "with an api key",
+			method:      http.MethodGet,
+			url:         "/cats/v1/cats",
+			headers:     map[string]string{"Authorization": "Bearer " + apiKey},
+			mock: func(tenants *model.MockTenantStore, scoped *model.MockStorage) {
+				tenants.EXPECT().SelectTenantByKey(hashKey(apiKey)).Return(model.Tenant{ID: tenantID}, nil)
+				scoped.EXPECT().SelectAll(10, 0).Return([]byte(`[{"id":"1","name":"tom"}]`), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"id":"1","name":"tom"}]`,
+		},
+		{
+			description:    "with an untrusted header",
+			method:         http.MethodGet,
+			url:            "/cats/v1/cats",
+			headers:        map[string]string{"X-Tenant-ID": tenantID},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			description: "with a trusted header",
+			trustHeader: true,
+			method:      http.MethodGet,
+			url:         "/cats/v1/cats",
+			headers:     map[string]string{"X-Tenant-ID": tenantID},
+			mock: func(tenants *model.MockTenantStore, scoped *model.MockStorage) {
+				tenants.EXPECT().SelectTenant(tenantID).Return(model.Tenant{ID: tenantID}, nil)
+				scoped.EXPECT().SelectAll(10, 0).Return([]byte(`[]`), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[]`,
+		},
+		{
+			description: "over quota",
+			method:      http.MethodPost,
+			url:         "/cats/v1/",
+			body:        `{"name":"tom","color":"grey","age":2}`,
+			headers:     map[string]string{"Authorization": "Bearer " + apiKey},
+			mock: func(tenants *model.MockTenantStore, scoped *model.MockStorage) {
+				tenants.EXPECT().SelectTenantByKey(hashKey(apiKey)).Return(model.Tenant{ID: tenantID}, nil)
+				scoped.EXPECT().Insert(gomock.Any()).Return("", model.QuotaError{MaxCats: 2})
+			},
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"error":{"status":403,"message":"the tenant's quota of 2 cats is reached"}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			tenants := model.NewMockTenantStore(ctrl)
+			scoped := model.NewMockStorage(ctrl)
+			tenanted := model.NewMockTenanted(ctrl)
+			tenanted.EXPECT().ForTenant(tenantID).Return(scoped).AnyTimes()
+			if tt.mock != nil {
+				tt.mock(tenants, scoped)
+			}
+			config := tenantConfig()
+			config.Tenancy.TrustHeader = tt.trustHeader
+			a := App{Storage: tenantStorage{model.NewMockStorage(ctrl), tenants, tenanted}, Config: config}
+			a.BootstrapServer()
+
+			req, _ := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, tt.expectedStatus, response.Body)
+			}
+			if tt.expectedBody != "" && response.Body.String() != tt.expectedBody {
+				t.Errorf("unxpected response body: got %s, expected %s", response.Body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestApp_Tenants(t *testing.T) {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tenant := model.Tenant{ID: tenantID, Name: "shelter", MaxCats: 10, CreatedAt: created}
+
+	tests := []struct {
+		description string
+		// given
+		method     string
+		url        string
+		body       string
+		adminToken string
+		token      string
+		mock       func(s *model.MockTenantStore)
+		// then
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description:    "administration disabled",
+			method:         http.MethodGet,
+			url:            "/cats/v1/tenants",
+			token:          "admin",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			description:    "without the admin token",
+			method:         http.MethodGet,
+			url:            "/cats/v1/tenants",
+			adminToken:     "admin",
+			token:          "other",
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			description: "list",
+			method:      http.MethodGet,
+			url:         "/cats/v1/tenants",
+			adminToken:  "admin",
+			token:       "admin",
+			mock: func(s *model.MockTenantStore) {
+				s.EXPECT().SelectTenants().Return([]model.Tenant{tenant}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"id":"` + tenantID + `","name":"shelter","maxCats":10,"createdAt":"2020-01-02T03:04:05Z"}]`,
+		},
+		{
+			description: "create with the name of another",
+			method:      http.MethodPost,
+			url:         "/cats/v1/tenants",
+			body:        `{"name":"shelter"}`,
+			adminToken:  "admin",
+			token:       "admin",
+			mock: func(s *model.MockTenantStore) {
+				s.EXPECT().InsertTenant(model.Tenant{Name: "shelter"}, gomock.Any()).Return("", model.ErrTenantExists)
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			description:    "create without a name",
+			method:         http.MethodPost,
+			url:            "/cats/v1/tenants",
+			body:           `{"maxCats":1}`,
+			adminToken:     "admin",
+			token:          "admin",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description: "get unknown",
+			method:      http.MethodGet,
+			url:         "/cats/v1/tenants/" + tenantID,
+			adminToken:  "admin",
+			token:       "admin",
+			mock: func(s *model.MockTenantStore) {
+				s.EXPECT().SelectTenant(tenantID).Return(model.Tenant{}, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			description: "update quota",
+			method:      http.MethodPatch,
+			url:         "/cats/v1/tenants/" + tenantID,
+			body:        `{"maxCats":20}`,
+			adminToken:  "admin",
+			token:       "admin",
+			mock: func(s *model.MockTenantStore) {
+				s.EXPECT().SelectTenant(tenantID).Return(tenant, nil)
+				s.EXPECT().UpdateTenantQuota(tenantID, 20).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"id":"` + tenantID + `","name":"shelter","maxCats":20,"createdAt":"2020-01-02T03:04:05Z"}`,
+		},
+		{
+			description:    "update with a negative quota",
+			method:         http.MethodPatch,
+			url:            "/cats/v1/tenants/" + tenantID,
+			body:           `{"maxCats":-1}`,
+			adminToken:     "admin",
+			token:          "admin",
+			expectedStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockTenantStore(ctrl)
+			if tt.mock != nil {
+				tt.mock(s)
+			}
+			config := conf.SaneDefaults()
+			config.Tenancy.AdminToken = tt.adminToken
+			a := App{Storage: tenantStorage{model.NewMockStorage(ctrl), s, model.NewMockTenanted(ctrl)}, Config: config}
+			a.BootstrapServer()
+
+			req, _ := http.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, tt.expectedStatus, response.Body)
+			}
+			if tt.expectedBody != "" && response.Body.String() != tt.expectedBody {
+				t.Errorf("unxpected response body: got %s, expected %s", response.Body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestApp_CreateTenant_ReturnsAPIKey(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockTenantStore(ctrl)
+	var keyHash string
+	s.EXPECT().InsertTenant(model.Tenant{Name: "shelter", MaxCats: 5}, gomock.Any()).DoAndReturn(
+		func(t model.Tenant, hash string) (string, error) {
+			keyHash = hash
+			return tenantID, nil
+		})
+	s.EXPECT().SelectTenant(tenantID).Return(model.Tenant{ID: tenantID, Name: "shelter", MaxCats: 5}, nil)
+	a := App{Storage: tenantStorage{model.NewMockStorage(ctrl), s, model.NewMockTenanted(ctrl)}, Config: tenantConfig()}
+	a.BootstrapServer()
+
+	req, _ := http.NewRequest(http.MethodPost, "/cats/v1/tenants", strings.NewReader(`{"name":"shelter","maxCats":5}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer admin")
+	response := httptest.NewRecorder()
+	a.Router.ServeHTTP(response, req)
+
+	var tenant model.Tenant
+	_ = json.Unmarshal(response.Body.Bytes(), &tenant)
+	if response.Code != http.StatusCreated || len(tenant.APIKey) != 64 || hashKey(tenant.APIKey) != keyHash {
+		t.Errorf("unxpected response: got %d %s, expected an api key stored hashed", response.Code, response.Body)
+	}
+}
+
+func TestApp_Stream_Tenant(t *testing.T) {
+	a, server := newStreamServer(t, time.Hour)
+	defer server.Close()
+	a.Config.Tenancy.Enabled = true
+	a.Config.Tenancy.TrustHeader = true
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	tenants := model.NewMockTenantStore(ctrl)
+	tenants.EXPECT().SelectTenant(tenantID).Return(model.Tenant{ID: tenantID}, nil)
+	a.Storage = tenantStorage{model.NewMockStorage(ctrl), tenants, model.NewMockTenanted(ctrl)}
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/cats/v1/cats/stream", nil)
+	req.Header.Set("X-Tenant-ID", tenantID)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	r := bufio.NewReader(resp.Body)
+	_ = readSSE(t, r)
+
+	other := events.New(events.Created, "1", &model.Cat{ID: "1"})
+	other.Tenant = "other"
+	own := events.New(events.Created, "2", &model.Cat{ID: "2"})
+	own.Tenant = tenantID
+	a.Events.Publish(other)
+	a.Events.Publish(own)
+
+	if got := readSSE(t, r); got != sseEvent(own) {
+		t.Errorf("unxpected event: got %q, expected %q", got, sseEvent(own))
+	}
+}
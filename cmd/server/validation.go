@@ -0,0 +1,313 @@
+package server
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/legacy"
+	json "github.com/json-iterator/go"
+	"github.com/rs/zerolog/log"
+	"github.com/waikco/cats-v1/codec"
+	"github.com/waikco/cats-v1/openapi"
+)
+
+func init() {
+	// bodies in the other media types the api speaks are validated as if they were json
+	for _, mediaType := range codecs.ContentTypes() {
+		c, _ := codecs.ForContentType(mediaType)
+		switch c {
+		case codec.JSON:
+		case codec.XML:
+			openapi3filter.RegisterBodyDecoder(mediaType, xmlBodyDecoder)
+		default:
+			openapi3filter.RegisterBodyDecoder(mediaType, codecBodyDecoder(c))
+		}
+	}
+}
+
+// Validator checks requests, and optionally responses, against the openapi spec.
+type Validator struct {
+	router            routers.Router
+	validateResponses bool
+	options           *openapi3filter.Options
+}
+
+// NewValidator creates a Validator for the embedded openapi spec.
+func NewValidator(validateResponses bool) (*Validator, error) {
+	doc, err := openapi.Load()
+	if err != nil {
+		return nil, err
+	}
+	router, err := legacy.NewRouter(doc)
+	if err != nil {
+		return nil, err
+	}
+	return &Validator{
+		router:            router,
+		validateResponses: validateResponses,
+		options: &openapi3filter.Options{
+			MultiError:         true,
+			AuthenticationFunc: openapi3filter.NoopAuthenticationFunc,
+		},
+	}, nil
+}
+
+// Middleware rejects requests not matching their openapi operation with a 400,
+// and logs any response not matching the spec when response validation is enabled.
+func (v *Validator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route, pathParams, err := v.router.FindRoute(r)
+		if err != nil {
+			// unknown routes and methods are left for the router to reject
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// clients have always been able to omit the content type of json bodies, and aliases
+		// of media types, such as text/xml, are only documented under their canonical type
+		if ct := r.Header.Get("Content-Type"); r.ContentLength != 0 && ct == "" {
+			r.Header.Set("Content-Type", "application/json")
+		} else if c, ok := codecs.ForContentType(ct); ok && !strings.HasPrefix(ct, c.ContentType()) {
+			r.Header.Set("Content-Type", c.ContentType())
+		} else if !ok && ct != "" {
+			// bodies in media types the api doesn't speak are rejected by the handler with a 415
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		input := &openapi3filter.RequestValidationInput{
+			Request:    r,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    v.options,
+		}
+		if err := openapi3filter.ValidateRequest(r.Context(), input); err != nil {
+			log.Debug().Msgf("request %s %s failed validation: %v", r.Method, r.URL.Path, err)
+			respondWithJson(w, http.StatusBadRequest, Response{
+				Error: Error{
+					Status:  http.StatusBadRequest,
+					Message: "request does not match api specification",
+					Details: validationDetails(err),
+				},
+			})
+			return
+		}
+
+		// streamed responses must reach the client as they are written, so are never buffered
+		if !v.validateResponses || streaming(route.Operation) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		responseInput := &openapi3filter.ResponseValidationInput{
+			RequestValidationInput: input,
+			Status:                 recorder.status,
+			Header:                 recorder.Header(),
+			Options:                &openapi3filter.Options{IncludeResponseStatus: true, MultiError: true},
+		}
+		responseInput.SetBodyBytes(recorder.body.Bytes())
+		if err := openapi3filter.ValidateResponse(r.Context(), responseInput); err != nil {
+			log.Error().Msgf("contract violation: response to %s %s with status %d does not match api specification: %v",
+				r.Method, r.URL.Path, recorder.status, err)
+		}
+
+		w.WriteHeader(recorder.status)
+		_, _ = w.Write(recorder.body.Bytes())
+	})
+}
+
+// streaming reports whether an operation is marked with x-streaming in the spec.
+func streaming(op *openapi3.Operation) bool {
+	s, _ := op.Extensions["x-streaming"].(bool)
+	return s
+}
+
+// responseRecorder buffers a response so it can be validated before being sent.
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	return r.body.Write(b)
+}
+
+// validationDetails flattens a validation error into one detail per problem.
+func validationDetails(err error) []ErrorDetail {
+	if multi, ok := err.(openapi3.MultiError); ok {
+		var details []ErrorDetail
+		for _, e := range multi {
+			details = append(details, validationDetails(e)...)
+		}
+		return details
+	}
+
+	var requestErr *openapi3filter.RequestError
+	if !errors.As(err, &requestErr) {
+		return []ErrorDetail{{Reason: err.Error()}}
+	}
+
+	detail := ErrorDetail{Reason: requestErr.Reason}
+	switch {
+	case requestErr.Parameter != nil:
+		detail.Location = requestErr.Parameter.In
+		detail.Field = requestErr.Parameter.Name
+	case requestErr.RequestBody != nil:
+		detail.Location = "body"
+	}
+
+	if requestErr.Err != nil {
+		var nested openapi3.MultiError
+		if errors.As(requestErr.Err, &nested) && len(nested) > 0 {
+			var details []ErrorDetail
+			for _, e := range nested {
+				d := detail
+				d.Reason, d.Field = schemaReason(e, detail.Field)
+				details = append(details, d)
+			}
+			return details
+		}
+		detail.Reason, detail.Field = schemaReason(requestErr.Err, detail.Field)
+	}
+	if detail.Reason == "" {
+		detail.Reason = requestErr.Error()
+	}
+	return []ErrorDetail{detail}
+}
+
+// schemaReason extracts the reason and the offending field from a schema error.
+func schemaReason(err error, field string) (string, string) {
+	var schemaErr *openapi3.SchemaError
+	if !errors.As(err, &schemaErr) {
+		return err.Error(), field
+	}
+	if pointer := schemaErr.JSONPointer(); len(pointer) > 0 {
+		field = strings.Join(pointer, ".")
+	}
+	return schemaErr.Reason, field
+}
+
+// codecBodyDecoder decodes bodies with a codec into the values a json body would decode to.
+func codecBodyDecoder(c codec.Codec) openapi3filter.BodyDecoder {
+	return func(body io.Reader, _ http.Header, _ *openapi3.SchemaRef, _ openapi3filter.EncodingFn) (interface{}, error) {
+		b, err := ioutil.ReadAll(body)
+		if err != nil {
+			return nil, err
+		}
+		var value interface{}
+		if err := c.Unmarshal(b, &value); err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+		}
+		if b, err = json.Marshal(value); err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+		}
+		return openapi3filter.JSONBodyDecoder(bytes.NewReader(b), nil, nil, nil)
+	}
+}
+
+// xmlBodyDecoder decodes xml bodies into the values a json body would decode to, using the
+// schema to tell the types of elements, since xml has none of its own.
+func xmlBodyDecoder(body io.Reader, _ http.Header, schema *openapi3.SchemaRef, _ openapi3filter.EncodingFn) (interface{}, error) {
+	d := xml.NewDecoder(body)
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+		}
+		if start, ok := t.(xml.StartElement); ok {
+			value, err := decodeXMLElement(d, start, schema)
+			if err != nil {
+				return nil, &openapi3filter.ParseError{Kind: openapi3filter.KindInvalidFormat, Cause: err}
+			}
+			return value, nil
+		}
+	}
+}
+
+// decodeXMLElement decodes the element opened by start. Arrays are a child element per item,
+// objects a child element per property, with array properties repeating their element.
+func decodeXMLElement(d *xml.Decoder, start xml.StartElement, ref *openapi3.SchemaRef) (interface{}, error) {
+	var schema *openapi3.Schema
+	if ref != nil {
+		schema = ref.Value
+	}
+
+	switch {
+	case schema != nil && schema.Type.Is(openapi3.TypeArray):
+		items := []interface{}{}
+		err := eachXMLChild(d, func(child xml.StartElement) error {
+			item, err := decodeXMLElement(d, child, schema.Items)
+			items = append(items, item)
+			return err
+		})
+		return items, err
+	case schema != nil && (schema.Type.Is(openapi3.TypeObject) || len(schema.Properties) > 0):
+		object := map[string]interface{}{}
+		err := eachXMLChild(d, func(child xml.StartElement) error {
+			name := child.Name.Local
+			property := schema.Properties[name]
+			if property != nil && property.Value != nil && property.Value.Type.Is(openapi3.TypeArray) {
+				item, err := decodeXMLElement(d, child, property.Value.Items)
+				items, _ := object[name].([]interface{})
+				object[name] = append(items, item)
+				return err
+			}
+			value, err := decodeXMLElement(d, child, property)
+			object[name] = value
+			return err
+		})
+		return object, err
+	}
+
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return nil, err
+	}
+	switch {
+	case schema != nil && (schema.Type.Is(openapi3.TypeInteger) || schema.Type.Is(openapi3.TypeNumber)):
+		if n, err := strconv.ParseFloat(strings.TrimSpace(text), 64); err == nil {
+			return n, nil
+		}
+	case schema != nil && schema.Type.Is(openapi3.TypeBoolean):
+		if b, err := strconv.ParseBool(strings.TrimSpace(text)); err == nil {
+			return b, nil
+		}
+	}
+	// left as text for the schema to reject
+	return text, nil
+}
+
+// eachXMLChild calls fn with every child element of the current element, up to its end.
+func eachXMLChild(d *xml.Decoder, fn func(xml.StartElement) error) error {
+	for {
+		t, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := t.(type) {
+		case xml.StartElement:
+			if err := fn(t); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
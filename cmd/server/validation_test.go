@@ -0,0 +1,134 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	json "github.com/json-iterator/go"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/model"
+)
+
+func TestValidator_Middleware(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		method      string
+		request     string
+		contentType string
+		requestBody []byte
+		// then
+		expectedStatus    int
+		expectedDetails   []ErrorDetail
+		expectedMockCalls int
+	}{
+		{
+			description:       "valid request is passed on",
+			method:            http.MethodPost,
+			request:           "/cats/v1/",
+			contentType:       "application/json",
+			requestBody:       []byte(`{"name":"cat-1","color":"color-1","age":1}`),
+			expectedStatus:    http.StatusCreated,
+			expectedMockCalls: 1,
+		},
+		{
+			description:       "missing content type is treated as json",
+			method:            http.MethodPost,
+			request:           "/cats/v1/",
+			requestBody:       []byte(`{"name":"cat-1","color":"color-1","age":1}`),
+			expectedStatus:    http.StatusCreated,
+			expectedMockCalls: 1,
+		},
+		{
+			description:    "body field with wrong type",
+			method:         http.MethodPost,
+			request:        "/cats/v1/",
+			contentType:    "application/json",
+			requestBody:    []byte(`{"name":"cat-1","color":"color-1","age":"one"}`),
+			expectedStatus: http.StatusBadRequest,
+			expectedDetails: []ErrorDetail{{
+				Location: "body",
+				Field:    "age",
+				Reason:   `value must be an integer`,
+			}},
+		},
+		{
+			description:    "query parameter with wrong type",
+			method:         http.MethodGet,
+			request:        "/cats/v1/cats?count=ten",
+			expectedStatus: http.StatusBadRequest,
+			expectedDetails: []ErrorDetail{{
+				Location: "query",
+				Field:    "count",
+				Reason:   `value ten: an invalid integer: invalid syntax`,
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			a := App{Config: conf.SaneDefaults()}
+			a.Config.Environment = conf.EnvProd
+			a.BootstrapServer()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockStorage(ctrl)
+			s.EXPECT().
+				Insert(gomock.Any()).
+				Return("fe271e7e-83ca-477b-92fc-d0c3fa602d7d", nil).
+				Times(tt.expectedMockCalls)
+			a.Storage = s
+
+			req := httptest.NewRequest(tt.method, tt.request, bytes.NewBuffer(tt.requestBody))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d", response.Code, tt.expectedStatus)
+			}
+
+			var got Response
+			err := json.Unmarshal(response.Body.Bytes(), &got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got.Error.Details, tt.expectedDetails) {
+				t.Errorf("unxpected error details: got %+v, expected %+v", got.Error.Details, tt.expectedDetails)
+			}
+		})
+	}
+}
+
+func TestValidator_MiddlewarePassesInvalidResponsesThrough(t *testing.T) {
+	v, err := NewValidator(true)
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	body := []byte(`{"name":"cat-1","color":"color-1","age":"one"}`)
+	handler := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(body)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/cats/v1/cats/fe271e7e-83ca-477b-92fc-d0c3fa602d7d", nil)
+	response := httptest.NewRecorder()
+	handler.ServeHTTP(response, req)
+
+	if response.Code != http.StatusOK {
+		t.Errorf("unxpected status code: got %d, expected %d", response.Code, http.StatusOK)
+	}
+	if !bytes.Equal(response.Body.Bytes(), body) {
+		t.Errorf("unxpected body: got %s, expected %s", response.Body.Bytes(), body)
+	}
+	if got := response.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("unxpected content type: got %s, expected application/json", got)
+	}
+}
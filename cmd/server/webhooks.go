@@ -0,0 +1,231 @@
+package server
+
+import (
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/model"
+)
+
+const (
+	defaultDeliveryLogSize = 50
+	maxDeliveryLogSize     = 500
+)
+
+// subscribableEvents are the event types webhooks and streams may subscribe to.
+var subscribableEvents = map[string]bool{
+	string(events.Created): true,
+	string(events.Updated): true,
+	string(events.Deleted): true,
+}
+
+// webhookStore returns the storage of the webhooks of the tenant of a request, which are not
+// supported either when they have no dispatcher.
+func (a *App) webhookStore(w http.ResponseWriter, r *http.Request) (model.WebhookStore, bool) {
+	if a.Webhooks == nil {
+		respondError(w, r, http.StatusNotImplemented, "webhooks are not supported by the configured storage")
+		return nil, false
+	}
+	return optional[model.WebhookStore](w, r, a.storage(r.Context()), "webhooks")
+}
+
+// CreateWebhook subscribes a url to events, generating a secret to sign deliveries with
+// unless one is given. The secret is only ever returned in this response.
+func (a *App) CreateWebhook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.webhookStore(w, r)
+	if !ok {
+		return
+	}
+
+	var webhook model.Webhook
+	body, err := ioutil.ReadAll(r.Body)
+	if err == nil {
+		_, err = decodeBody(r, body, &webhook)
+	}
+	if err != nil {
+		respondError(w, r, http.StatusBadRequest, "invalid webhook in request body")
+		return
+	}
+	if err := validateWebhook(webhook); err != nil {
+		respondError(w, r, http.StatusBadRequest, "%v", err)
+		return
+	}
+	if webhook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			respondError(w, r, http.StatusInternalServerError, "unable to generate a secret")
+			return
+		}
+		webhook.Secret = hex.EncodeToString(secret)
+	}
+
+	id, err := store.InsertWebhook(webhook)
+	if err != nil {
+		log.Error().Msgf("error creating webhook: %v", err)
+		respondError(w, r, http.StatusInternalServerError, "unable to create webhook")
+		return
+	}
+	created, err := store.SelectWebhook(id)
+	if err != nil {
+		log.Error().Msgf("error getting created webhook %s: %v", id, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to create webhook")
+		return
+	}
+	respond(w, r, http.StatusCreated, created)
+}
+
+func validateWebhook(webhook model.Webhook) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https url")
+	}
+	if len(webhook.Events) == 0 {
+		return fmt.Errorf("events must list at least one event")
+	}
+	for _, e := range webhook.Events {
+		if !subscribableEvents[e] {
+			return fmt.Errorf("unknown event %s, use cat.created, cat.updated or cat.deleted", e)
+		}
+	}
+	return nil
+}
+
+// GetWebhooks lists every webhook, without their secrets.
+func (a *App) GetWebhooks(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.webhookStore(w, r)
+	if !ok {
+		return
+	}
+	webhooks, err := store.SelectWebhooks()
+	if err != nil {
+		log.Error().Msgf("error getting webhooks: %v", err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get webhooks")
+		return
+	}
+	for i := range webhooks {
+		webhooks[i].Secret = ""
+	}
+	respond(w, r, http.StatusOK, webhooks)
+}
+
+// GetWebhook retrieves a webhook, without its secret.
+func (a *App) GetWebhook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.webhookStore(w, r)
+	if !ok {
+		return
+	}
+	webhook, ok := selectWebhook(w, r, store, ps.ByName("id"))
+	if !ok {
+		return
+	}
+	webhook.Secret = ""
+	respond(w, r, http.StatusOK, webhook)
+}
+
+// DeleteWebhook deletes a webhook along with its delivery log.
+func (a *App) DeleteWebhook(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.webhookStore(w, r)
+	if !ok {
+		return
+	}
+	webhook, ok := selectWebhook(w, r, store, ps.ByName("id"))
+	if !ok {
+		return
+	}
+	if err := store.DeleteWebhook(webhook.ID); err != nil {
+		log.Error().Msgf("error deleting webhook %s: %v", webhook.ID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to delete webhook")
+		return
+	}
+	respond(w, r, http.StatusOK, Response{Result: "success"})
+}
+
+// GetWebhookDeliveries lists the latest deliveries of a webhook, newest first.
+func (a *App) GetWebhookDeliveries(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.webhookStore(w, r)
+	if !ok {
+		return
+	}
+	limit := defaultDeliveryLogSize
+	if s := r.URL.Query().Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxDeliveryLogSize {
+			respondError(w, r, http.StatusBadRequest, "limit must be between 1 and %d", maxDeliveryLogSize)
+			return
+		}
+		limit = n
+	}
+	webhook, ok := selectWebhook(w, r, store, ps.ByName("id"))
+	if !ok {
+		return
+	}
+
+	deliveries, err := store.SelectDeliveries(webhook.ID, limit)
+	if err != nil {
+		log.Error().Msgf("error getting deliveries of webhook %s: %v", webhook.ID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get deliveries")
+		return
+	}
+	respond(w, r, http.StatusOK, deliveries)
+}
+
+// ReplayWebhookDelivery queues the event of an earlier delivery to be delivered again.
+func (a *App) ReplayWebhookDelivery(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	store, ok := a.webhookStore(w, r)
+	if !ok {
+		return
+	}
+	id, deliveryID := ps.ByName("id"), ps.ByName("deliveryId")
+	if uuid.FromStringOrNil(deliveryID) == uuid.Nil {
+		respondError(w, r, http.StatusBadRequest, "invalid delivery id: %s", deliveryID)
+		return
+	}
+	if _, ok := selectWebhook(w, r, store, id); !ok {
+		return
+	}
+
+	delivery, err := store.SelectDelivery(deliveryID)
+	if err == sql.ErrNoRows || err == nil && delivery.WebhookID != id {
+		respondError(w, r, http.StatusNotFound, "delivery id %s not found", deliveryID)
+		return
+	}
+	if err == nil {
+		delivery, err = a.Webhooks.Replay(delivery)
+	}
+	if err != nil {
+		log.Error().Msgf("error replaying delivery %s: %v", deliveryID, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to replay delivery")
+		return
+	}
+	respond(w, r, http.StatusAccepted, delivery)
+}
+
+// selectWebhook selects a webhook, responding with a 400 for invalid ids and a 404 for
+// unknown ones.
+func selectWebhook(w http.ResponseWriter, r *http.Request, store model.WebhookStore, id string) (model.Webhook, bool) {
+	if uuid.FromStringOrNil(id) == uuid.Nil {
+		respondError(w, r, http.StatusBadRequest, "invalid webhook id: %s", id)
+		return model.Webhook{}, false
+	}
+	webhook, err := store.SelectWebhook(id)
+	switch err {
+	case nil:
+		return webhook, true
+	case sql.ErrNoRows:
+		respondError(w, r, http.StatusNotFound, "webhook id %s not found", id)
+	default:
+		log.Error().Msgf("error getting webhook %s: %v", id, err)
+		respondError(w, r, http.StatusInternalServerError, "unable to get webhook")
+	}
+	return model.Webhook{}, false
+}
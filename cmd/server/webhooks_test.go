@@ -0,0 +1,220 @@
+package server
+
+import (
+	"bytes"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	json "github.com/json-iterator/go"
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/model"
+)
+
+// webhookStorage is storage able to persist webhooks.
+type webhookStorage struct {
+	*model.MockStorage
+	*model.MockWebhookStore
+}
+
+func TestApp_Webhooks(t *testing.T) {
+	const (
+		webhookID  = "9a0c4bd4-63c8-4bb1-9c8b-c1a9a4b8ac7d"
+		deliveryID = "2f0b6a1e-1d2c-4b8e-a1f5-0f5e7f6f0b11"
+	)
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	webhook := model.Webhook{ID: webhookID, URL: "https://example.com/hook", Events: []string{"cat.created"},
+		Secret: "secret", CreatedAt: created}
+
+	tests := []struct {
+		description string
+		// given
+		method string
+		url    string
+		body   string
+		mock   func(s *model.MockWebhookStore)
+		// then
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			description: "create with a secret",
+			method:      http.MethodPost,
+			url:         "/cats/v1/webhooks",
+			body:        `{"url":"https://example.com/hook","events":["cat.created"],"secret":"secret"}`,
+			mock: func(s *model.MockWebhookStore) {
+				s.EXPECT().InsertWebhook(model.Webhook{URL: "https://example.com/hook", Events: []string{"cat.created"},
+					Secret: "secret"}).Return(webhookID, nil)
+				s.EXPECT().SelectWebhook(webhookID).Return(webhook, nil)
+			},
+			expectedStatus: http.StatusCreated,
+			expectedBody: `{"id":"` + webhookID + `","url":"https://example.com/hook","events":["cat.created"],` +
+				`"secret":"secret","createdAt":"2020-01-02T03:04:05Z"}`,
+		},
+		{
+			description:    "create with an unknown event",
+			method:         http.MethodPost,
+			url:            "/cats/v1/webhooks",
+			body:           `{"url":"https://example.com/hook","events":["cat.adopted"]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description:    "create with a relative url",
+			method:         http.MethodPost,
+			url:            "/cats/v1/webhooks",
+			body:           `{"url":"/hook","events":["cat.created"]}`,
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"error":{"status":400,"message":"url must be an absolute http or https url"}}`,
+		},
+		{
+			description: "list without secrets",
+			method:      http.MethodGet,
+			url:         "/cats/v1/webhooks",
+			mock: func(s *model.MockWebhookStore) {
+				s.EXPECT().SelectWebhooks().Return([]model.Webhook{webhook}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"id":"` + webhookID + `","url":"https://example.com/hook","events":["cat.created"],` +
+				`"createdAt":"2020-01-02T03:04:05Z"}]`,
+		},
+		{
+			description: "get unknown",
+			method:      http.MethodGet,
+			url:         "/cats/v1/webhooks/" + webhookID,
+			mock: func(s *model.MockWebhookStore) {
+				s.EXPECT().SelectWebhook(webhookID).Return(model.Webhook{}, sql.ErrNoRows)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"error":{"status":404,"message":"webhook id ` + webhookID + ` not found"}}`,
+		},
+		{
+			description: "delete",
+			method:      http.MethodDelete,
+			url:         "/cats/v1/webhooks/" + webhookID,
+			mock: func(s *model.MockWebhookStore) {
+				s.EXPECT().SelectWebhook(webhookID).Return(webhook, nil)
+				s.EXPECT().DeleteWebhook(webhookID).Return(nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"result":"success","error":{}}`,
+		},
+		{
+			description: "delivery log",
+			method:      http.MethodGet,
+			url:         "/cats/v1/webhooks/" + webhookID + "/deliveries?limit=1",
+			mock: func(s *model.MockWebhookStore) {
+				s.EXPECT().SelectWebhook(webhookID).Return(webhook, nil)
+				s.EXPECT().SelectDeliveries(webhookID, 1).Return([]model.Delivery{{
+					ID: deliveryID, WebhookID: webhookID, EventID: "e1", EventType: "cat.created",
+					Payload: []byte(`{"id":"e1"}`), Status: model.DeliveryDead, Attempts: 8, NextAttempt: created,
+					LastStatus: 500, LastError: "unexpected response 500", CreatedAt: created,
+				}}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `[{"id":"` + deliveryID + `","webhookId":"` + webhookID + `","eventId":"e1","eventType":"cat.created",` +
+				`"payload":{"id":"e1"},"status":"dead","attempts":8,"nextAttemptAt":"2020-01-02T03:04:05Z",` +
+				`"lastStatus":500,"lastError":"unexpected response 500","createdAt":"2020-01-02T03:04:05Z"}]`,
+		},
+		{
+			description:    "delivery log too long",
+			method:         http.MethodGet,
+			url:            "/cats/v1/webhooks/" + webhookID + "/deliveries?limit=1000",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			description: "replay a delivery of another webhook",
+			method:      http.MethodPost,
+			url:         "/cats/v1/webhooks/" + webhookID + "/deliveries/" + deliveryID + "/replay",
+			mock: func(s *model.MockWebhookStore) {
+				s.EXPECT().SelectWebhook(webhookID).Return(webhook, nil)
+				s.EXPECT().SelectDelivery(deliveryID).Return(model.Delivery{ID: deliveryID, WebhookID: "other"}, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			description: "replay",
+			method:      http.MethodPost,
+			url:         "/cats/v1/webhooks/" + webhookID + "/deliveries/" + deliveryID + "/replay",
+			mock: func(s *model.MockWebhookStore) {
+				delivery := model.Delivery{ID: deliveryID, WebhookID: webhookID, EventID: "e1", EventType: "cat.created",
+					Payload: []byte(`{"id":"e1"}`), Status: model.DeliveryDead, Attempts: 8}
+				s.EXPECT().SelectWebhook(webhookID).Return(webhook, nil)
+				s.EXPECT().SelectDelivery(deliveryID).Return(delivery, nil)
+				s.EXPECT().InsertDeliveries(gomock.Any()).Return(nil)
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockWebhookStore(ctrl)
+			if tt.mock != nil {
+				tt.mock(s)
+			}
+			a := App{Storage: webhookStorage{model.NewMockStorage(ctrl), s}, Config: conf.SaneDefaults()}
+			a.BootstrapServer()
+
+			req, _ := http.NewRequest(tt.method, tt.url, bytes.NewBufferString(tt.body))
+			if tt.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			response := httptest.NewRecorder()
+			a.Router.ServeHTTP(response, req)
+
+			if response.Code != tt.expectedStatus {
+				t.Errorf("unxpected status code: got %d, expected %d: %s", response.Code, tt.expectedStatus, response.Body)
+			}
+			if tt.expectedBody != "" && response.Body.String() != tt.expectedBody {
+				t.Errorf("unxpected response body: got %s, expected %s", response.Body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestApp_CreateWebhook_GeneratesSecret(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockWebhookStore(ctrl)
+	var secret string
+	s.EXPECT().InsertWebhook(gomock.Any()).DoAndReturn(func(w model.Webhook) (string, error) {
+		secret = w.Secret
+		return "9a0c4bd4-63c8-4bb1-9c8b-c1a9a4b8ac7d", nil
+	})
+	s.EXPECT().SelectWebhook(gomock.Any()).DoAndReturn(func(id string) (model.Webhook, error) {
+		return model.Webhook{ID: id, Secret: secret}, nil
+	})
+	a := App{Storage: webhookStorage{model.NewMockStorage(ctrl), s}}
+	a.BootstrapServer()
+
+	req, _ := http.NewRequest(http.MethodPost, "/cats/v1/webhooks",
+		strings.NewReader(`{"url":"http://example.com","events":["cat.deleted"]}`))
+	response := httptest.NewRecorder()
+	a.Router.ServeHTTP(response, req)
+
+	var webhook model.Webhook
+	_ = json.Unmarshal(response.Body.Bytes(), &webhook)
+	if response.Code != http.StatusCreated || len(secret) != 64 || webhook.Secret != secret {
+		t.Errorf("unxpected response: got %d %s, expected a generated secret", response.Code, response.Body)
+	}
+}
+
+func TestApp_Webhooks_Unsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	a := App{Storage: model.NewMockStorage(ctrl)}
+	a.BootstrapServer()
+
+	req, _ := http.NewRequest(http.MethodGet, "/cats/v1/webhooks", nil)
+	response := httptest.NewRecorder()
+	a.Router.ServeHTTP(response, req)
+
+	if response.Code != http.StatusNotImplemented {
+		t.Errorf("unxpected status code: got %d, expected %d", response.Code, http.StatusNotImplemented)
+	}
+}
@@ -0,0 +1,47 @@
+package cmd
+
+import (
+	"database/sql"
+	"fmt"
+
+	uuid "github.com/satori/go.uuid"
+	"github.com/waikco/cats-v1/model"
+)
+
+// openStorage connects to the storage described by the config, for commands working on it directly.
+// Given a tenant, every query is scoped to it, and the default tenant is used otherwise.
+func openStorage(tenant string) (model.Storage, error) {
+	config, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	storage, err := model.BootstrapPostgres(config.Database)
+	if err != nil {
+		return nil, err
+	}
+	return forTenant(storage, tenant)
+}
+
+// forTenant scopes storage to a tenant, which must exist, leaving it as it is without one.
+func forTenant(storage model.Storage, tenant string) (model.Storage, error) {
+	if tenant == "" {
+		return storage, nil
+	}
+	// the nil uuid is the id of the default tenant
+	if _, err := uuid.FromString(tenant); err != nil {
+		return nil, fmt.Errorf("invalid tenant id: %s", tenant)
+	}
+	tenants, ok := storage.(model.TenantStore)
+	scoped, scopes := storage.(model.Tenanted)
+	if !ok || !scopes {
+		return nil, fmt.Errorf("tenants are not supported by the configured storage")
+	}
+	switch _, err := tenants.SelectTenant(tenant); err {
+	case nil:
+		return scoped.ForTenant(tenant), nil
+	case sql.ErrNoRows:
+		return nil, fmt.Errorf("tenant id %s not found", tenant)
+	default:
+		return nil, fmt.Errorf("unable to get tenant %s: %v", tenant, err)
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/model"
+)
+
+// tenantedStorage is storage able to scope queries to tenants.
+type tenantedStorage struct {
+	*model.MockStorage
+	*model.MockTenantStore
+	*model.MockTenanted
+}
+
+func TestForTenant(t *testing.T) {
+	const tenant = "7d2b1b7e-3c1a-4d8e-9f59-0f3f3b0c6a11"
+	tests := []struct {
+		description string
+		// given
+		tenant string
+		mock   func(s tenantedStorage, scoped model.Storage)
+		// then
+		expectedScoped bool
+		expectedErr    string
+	}{
+		{
+			description: "no tenant",
+		},
+		{
+			description: "invalid tenant",
+			tenant:      "not-a-uuid",
+			expectedErr: "invalid tenant id: not-a-uuid",
+		},
+		{
+			description: "default tenant",
+			tenant:      model.DefaultTenant,
+			mock: func(s tenantedStorage, scoped model.Storage) {
+				s.MockTenantStore.EXPECT().SelectTenant(model.DefaultTenant).Return(model.Tenant{ID: model.DefaultTenant}, nil)
+				s.MockTenanted.EXPECT().ForTenant(model.DefaultTenant).Return(scoped)
+			},
+			expectedScoped: true,
+		},
+		{
+			description: "unknown tenant",
+			tenant:      tenant,
+			mock: func(s tenantedStorage, scoped model.Storage) {
+				s.MockTenantStore.EXPECT().SelectTenant(tenant).Return(model.Tenant{}, sql.ErrNoRows)
+			},
+			expectedErr: "tenant id " + tenant + " not found",
+		},
+		{
+			description: "tenant",
+			tenant:      tenant,
+			mock: func(s tenantedStorage, scoped model.Storage) {
+				s.MockTenantStore.EXPECT().SelectTenant(tenant).Return(model.Tenant{ID: tenant}, nil)
+				s.MockTenanted.EXPECT().ForTenant(tenant).Return(scoped)
+			},
+			expectedScoped: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := tenantedStorage{model.NewMockStorage(ctrl), model.NewMockTenantStore(ctrl), model.NewMockTenanted(ctrl)}
+			scoped := model.NewMockStorage(ctrl)
+			if tt.mock != nil {
+				tt.mock(s, scoped)
+			}
+
+			storage, err := forTenant(s, tt.tenant)
+
+			if tt.expectedErr != "" {
+				if err == nil || err.Error() != tt.expectedErr {
+					t.Errorf("unexpected error: got %v, expected %s", err, tt.expectedErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.expectedScoped && storage != model.Storage(scoped) || !tt.expectedScoped && storage != model.Storage(s) {
+				t.Errorf("unexpected storage: %v", storage)
+			}
+		})
+	}
+}
+
+func TestForTenantUnsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	if _, err := forTenant(model.NewMockStorage(ctrl), "7d2b1b7e-3c1a-4d8e-9f59-0f3f3b0c6a11"); err == nil {
+		t.Errorf("unexpected success scoping storage without tenants")
+	}
+}
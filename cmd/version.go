@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"fmt"
+
+	json "github.com/json-iterator/go"
+	"github.com/spf13/cobra"
+	"github.com/waikco/cats-v1/buildinfo"
+)
+
+// versionCmd prints the build information of the binary
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print build and version information",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info := buildinfo.Get()
+		if asJson, _ := cmd.Flags().GetBool("json"); asJson {
+			b, err := json.MarshalIndent(info, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), string(b))
+			return nil
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "cats-v1 %s\n", info)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+	versionCmd.Flags().Bool("json", false, "print build information as json")
+}
@@ -0,0 +1,76 @@
+// Package codec encodes and decodes api values in the media types the api supports,
+// picking one from the Accept or Content-Type header of a request.
+package codec
+
+import (
+	"mime"
+	"strings"
+)
+
+// Codec encodes and decodes values in a single media type.
+type Codec interface {
+	// Name is a short name of the encoding, such as json.
+	Name() string
+	// ContentType is the media type sent in the Content-Type header of encoded values.
+	ContentType() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(b []byte, v interface{}) error
+}
+
+// Registry holds codecs by media type, in order of preference.
+type Registry struct {
+	offers []string
+	codecs map[string]Codec
+}
+
+// NewRegistry creates a registry of codecs, the first being preferred when clients
+// accept any media type.
+func NewRegistry(codecs ...Codec) *Registry {
+	r := &Registry{codecs: map[string]Codec{}}
+	for _, c := range codecs {
+		r.Register(c)
+	}
+	return r
+}
+
+// Default returns a registry of the json, xml, messagepack and cbor codecs, preferring json.
+func Default() *Registry {
+	r := NewRegistry(JSON, XML, MessagePack, CBOR)
+	r.Register(XML, "text/xml")
+	r.Register(MessagePack, "application/x-msgpack", "application/vnd.msgpack")
+	return r
+}
+
+// Register adds a codec for its content type, and for any aliases of it.
+func (r *Registry) Register(c Codec, aliases ...string) {
+	for _, mediaType := range append([]string{c.ContentType()}, aliases...) {
+		if _, ok := r.codecs[mediaType]; !ok {
+			r.offers = append(r.offers, mediaType)
+		}
+		r.codecs[mediaType] = c
+	}
+}
+
+// ContentTypes returns every media type a codec is registered for, in order of preference.
+func (r *Registry) ContentTypes() []string {
+	return append([]string(nil), r.offers...)
+}
+
+// Negotiate returns the codec the Accept header prefers.
+func (r *Registry) Negotiate(accept string) (Codec, bool) {
+	mediaType, ok := Negotiate(accept, r.offers...)
+	if !ok {
+		return nil, false
+	}
+	return r.codecs[mediaType], true
+}
+
+// ForContentType returns the codec for a Content-Type header, ignoring any parameters.
+func (r *Registry) ForContentType(contentType string) (Codec, bool) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	c, ok := r.codecs[strings.ToLower(mediaType)]
+	return c, ok
+}
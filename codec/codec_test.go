@@ -0,0 +1,121 @@
+package codec
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/waikco/cats-v1/model"
+)
+
+func TestCodecs_RoundTrip(t *testing.T) {
+	cats := []model.Cat{
+		{ID: "fe271e7e-83ca-477b-92fc-d0c3fa602d7d", Name: "cat-1", Color: "color-1", Age: 1},
+		{Name: "cat-2", Color: "color-2"},
+	}
+	for _, c := range []Codec{JSON, XML, MessagePack, CBOR} {
+		t.Run(c.Name(), func(t *testing.T) {
+			b, err := c.Marshal(cats[0])
+			if err != nil {
+				t.Fatalf("unxpected error: %v", err)
+			}
+			var got model.Cat
+			if err := c.Unmarshal(b, &got); err != nil {
+				t.Fatalf("unxpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, cats[0]) {
+				t.Errorf("unxpected cat: got %+v, expected %+v", got, cats[0])
+			}
+
+			if c == XML {
+				// xml lists are only written, as the api never reads them
+				return
+			}
+			b, err = c.Marshal(cats)
+			if err != nil {
+				t.Fatalf("unxpected error: %v", err)
+			}
+			var list []model.Cat
+			if err := c.Unmarshal(b, &list); err != nil {
+				t.Fatalf("unxpected error: %v", err)
+			}
+			if !reflect.DeepEqual(list, cats) {
+				t.Errorf("unxpected cats: got %+v, expected %+v", list, cats)
+			}
+		})
+	}
+}
+
+func TestXML_Marshal(t *testing.T) {
+	tests := []struct {
+		description string
+		value       interface{}
+		expected    string
+	}{
+		{
+			description: "struct named after its type",
+			value:       model.Cat{Name: "cat-1", Age: 2},
+			expected:    `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<cat><name>cat-1</name><age>2</age></cat>`,
+		},
+		{
+			description: "slice wrapped in a list",
+			value:       []model.Cat{{Name: "cat-1"}, {Name: "cat-2"}},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<list><cat><name>cat-1</name></cat><cat><name>cat-2</name></cat></list>`,
+		},
+		{
+			description: "empty slice",
+			value:       []model.Cat{},
+			expected:    `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<list></list>`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			b, err := XML.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("unxpected error: %v", err)
+			}
+			if string(b) != tt.expected {
+				t.Errorf("unxpected xml: got %s, expected %s", b, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := Default()
+
+	tests := []struct {
+		accept   string
+		expected Codec
+	}{
+		{accept: "", expected: JSON},
+		{accept: "*/*", expected: JSON},
+		{accept: "text/xml", expected: XML},
+		{accept: "application/json;q=0.5, application/cbor", expected: CBOR},
+		{accept: "application/x-msgpack", expected: MessagePack},
+		{accept: "text/html", expected: nil},
+	}
+	for _, tt := range tests {
+		t.Run("accept "+tt.accept, func(t *testing.T) {
+			got, ok := r.Negotiate(tt.accept)
+			if got != tt.expected || ok != (tt.expected != nil) {
+				t.Errorf("unxpected codec: got %v %v, expected %v", got, ok, tt.expected)
+			}
+		})
+	}
+
+	for contentType, expected := range map[string]Codec{
+		"application/json; charset=utf-8": JSON,
+		"Application/XML":                 XML,
+		"application/vnd.msgpack":         MessagePack,
+		"text/plain":                      nil,
+		"not a media type":                nil,
+	} {
+		t.Run("content type "+contentType, func(t *testing.T) {
+			got, ok := r.ForContentType(contentType)
+			if got != expected || ok != (expected != nil) {
+				t.Errorf("unxpected codec: got %v %v, expected %v", got, ok, expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/xml"
+	"reflect"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/fxamacker/cbor/v2"
+	json "github.com/json-iterator/go"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var (
+	// JSON encodes values as application/json.
+	JSON Codec = jsonCodec{}
+	// XML encodes values as application/xml, see xmlCodec for how values map to elements.
+	XML Codec = xmlCodec{}
+	// MessagePack encodes values as application/msgpack, using their json field names.
+	MessagePack Codec = msgpackCodec{}
+	// CBOR encodes values as application/cbor, using their json field names.
+	CBOR Codec = cborCodec{}
+)
+
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string                            { return "json" }
+func (jsonCodec) ContentType() string                     { return "application/json" }
+func (jsonCodec) Marshal(v interface{}) ([]byte, error)   { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(b []byte, v interface{}) error { return json.Unmarshal(b, v) }
+
+// xmlCodec encodes a value as an element named after its type, such as <cat> for a Cat,
+// and a slice as a <list> element holding one element per item.
+type xmlCodec struct{}
+
+func (xmlCodec) Name() string        { return "xml" }
+func (xmlCodec) ContentType() string { return "application/xml" }
+
+func (xmlCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	enc := xml.NewEncoder(&buf)
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() != reflect.Uint8 {
+		list := xml.StartElement{Name: xml.Name{Local: "list"}}
+		if err := enc.EncodeToken(list); err != nil {
+			return nil, err
+		}
+		item := xml.StartElement{Name: xml.Name{Local: elementName(rv.Type().Elem())}}
+		for i := 0; i < rv.Len(); i++ {
+			if err := enc.EncodeElement(rv.Index(i).Interface(), item); err != nil {
+				return nil, err
+			}
+		}
+		if err := enc.EncodeToken(list.End()); err != nil {
+			return nil, err
+		}
+	} else {
+		start := xml.StartElement{Name: xml.Name{Local: elementName(reflect.TypeOf(v))}}
+		if err := enc.EncodeElement(v, start); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (xmlCodec) Unmarshal(b []byte, v interface{}) error { return xml.Unmarshal(b, v) }
+
+// elementName names the xml element of a type after the type, starting in lower case.
+func elementName(t reflect.Type) string {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Name() == "" {
+		return "value"
+	}
+	r, size := utf8.DecodeRuneInString(t.Name())
+	return string(unicode.ToLower(r)) + t.Name()[size:]
+}
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string        { return "msgpack" }
+func (msgpackCodec) ContentType() string { return "application/msgpack" }
+
+func (msgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackCodec) Unmarshal(b []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(b))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+type cborCodec struct{}
+
+// cborDecMode decodes maps of unknown type with string keys, as json does.
+var cborDecMode, _ = cbor.DecOptions{DefaultMapType: reflect.TypeOf(map[string]interface{}(nil))}.DecMode()
+
+func (cborCodec) Name() string                            { return "cbor" }
+func (cborCodec) ContentType() string                     { return "application/cbor" }
+func (cborCodec) Marshal(v interface{}) ([]byte, error)   { return cbor.Marshal(v) }
+func (cborCodec) Unmarshal(b []byte, v interface{}) error { return cborDecMode.Unmarshal(b, v) }
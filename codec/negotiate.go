@@ -0,0 +1,90 @@
+package codec
+
+import (
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// mediaRange is a single entry of an Accept header.
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// parseAccept parses an Accept header, ordering media ranges by preference.
+func parseAccept(header string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if s, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(s, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+		slash := strings.IndexByte(mediaType, '/')
+		if slash < 0 {
+			// some clients send a bare * for */*
+			if mediaType != "*" {
+				continue
+			}
+			mediaType, slash = "*/*", 1
+		}
+		ranges = append(ranges, mediaRange{typ: mediaType[:slash], subtype: mediaType[slash+1:], q: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q || ranges[i].q == ranges[j].q && ranges[i].specificity() > ranges[j].specificity()
+	})
+	return ranges
+}
+
+func (m mediaRange) specificity() int {
+	switch {
+	case m.typ == "*":
+		return 0
+	case m.subtype == "*":
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (m mediaRange) matches(mediaType string) bool {
+	slash := strings.IndexByte(mediaType, '/')
+	typ, subtype := mediaType[:slash], mediaType[slash+1:]
+	return (m.typ == "*" || m.typ == typ) && (m.subtype == "*" || m.subtype == subtype)
+}
+
+// Negotiate picks the offered media type the Accept header prefers, with ties going to
+// the earliest offer. A missing Accept header accepts the first offer.
+func Negotiate(accept string, offers ...string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return offers[0], true
+	}
+	for _, r := range parseAccept(accept) {
+		if r.q == 0 {
+			continue
+		}
+		for _, offer := range offers {
+			if r.matches(offer) && !refused(accept, offer) {
+				return offer, true
+			}
+		}
+	}
+	return "", false
+}
+
+// refused reports whether the Accept header explicitly gives the media type a q of 0.
+func refused(accept, mediaType string) bool {
+	for _, r := range parseAccept(accept) {
+		if r.specificity() == 2 && r.matches(mediaType) {
+			return r.q == 0
+		}
+	}
+	return false
+}
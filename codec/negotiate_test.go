@@ -0,0 +1,29 @@
+package codec
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/json", "application/x-ndjson", "text/csv"}
+	tests := []struct {
+		accept   string
+		expected string
+		ok       bool
+	}{
+		{accept: "", expected: "application/json", ok: true},
+		{accept: "*/*", expected: "application/json", ok: true},
+		{accept: "text/*", expected: "text/csv", ok: true},
+		{accept: "text/csv;q=0.9, application/x-ndjson", expected: "application/x-ndjson", ok: true},
+		{accept: "*/*;q=0.1, text/csv;q=0.5", expected: "text/csv", ok: true},
+		{accept: "*/*, application/json;q=0", expected: "application/x-ndjson", ok: true},
+		{accept: "application/xml", ok: false},
+		{accept: "text/csv;q=0", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			got, ok := Negotiate(tt.accept, offers...)
+			if got != tt.expected || ok != tt.ok {
+				t.Errorf("unxpected negotiation: got %q %v, expected %q %v", got, ok, tt.expected, tt.ok)
+			}
+		})
+	}
+}
@@ -1,10 +1,39 @@
 package conf
 
+import "time"
+
+// Environments the application can run in. Configs without an environment, which predate
+// it, run in prod.
+const (
+	EnvDev  = "dev"
+	EnvTest = "test"
+	EnvProd = "prod"
+)
+
 // Config is application config
 type Config struct {
-	Server   Server   `json:"server" yaml:"server"`
-	Database Database `json:"database" yaml:"database"`
-	Logging  Logging  `json:"logging" yaml:"logging"`
+	Environment string      `json:"environment" yaml:"environment"`
+	Server      Server      `json:"server" yaml:"server"`
+	Database    Database    `json:"database" yaml:"database"`
+	Logging     Logging     `json:"logging" yaml:"logging"`
+	Health      Health      `json:"health" yaml:"health"`
+	Validation  Validation  `json:"validation" yaml:"validation"`
+	Client      Client      `json:"client" yaml:"client"`
+	GraphQL     GraphQL     `json:"graphql" yaml:"graphql"`
+	Webhooks    Webhooks    `json:"webhooks" yaml:"webhooks"`
+	Stream      Stream      `json:"stream" yaml:"stream"`
+	Cache       Cache       `json:"cache" yaml:"cache"`
+	Outbox      Outbox      `json:"outbox" yaml:"outbox"`
+	Idempotency Idempotency `json:"idempotency" yaml:"idempotency"`
+	Tenancy     Tenancy     `json:"tenancy" yaml:"tenancy"`
+	Photos      Photos      `json:"photos" yaml:"photos"`
+	Reference   Reference   `json:"reference" yaml:"reference"`
+}
+
+// ValidateResponses reports whether outgoing responses should be checked against the api spec,
+// which is only done when validation is enabled in the dev and test environments.
+func (c Config) ValidateResponses() bool {
+	return c.Validation.Enabled && (c.Environment == EnvDev || c.Environment == EnvTest)
 }
 
 type Server struct {
@@ -12,6 +41,8 @@ type Server struct {
 	Cert string `json:"cert" yaml:"cert"`
 	Key  string `json:"key" yaml:"key"`
 	TLS  bool   `json:"tls" yaml:"tls"`
+	// GRPCPort is the port of the grpc api, which is not served when empty.
+	GRPCPort string `json:"grpcPort" yaml:"grpcPort"`
 }
 
 type Database struct {
@@ -19,24 +50,174 @@ type Database struct {
 	Host         string `json:"host" yaml:"host"`
 	Port         int    `json:"port" yaml:"port"`
 	User         string `json:"user" yaml:"user"`
-	Password     string `json:"password" yaml:"password"`
+	Password     string `json:"password" yaml:"password" secret:"true"`
 	DatabaseName string `json:"databaseName" yaml:"databaseName"`
 	SslMode      string `json:"sslMode" yaml:"sslMode"`
 	SslFactory   string `json:"sslFactory" yaml:"sslFactory"`
+	// Listen follows changes made by other instances sharing the database.
+	Listen bool `json:"listen" yaml:"listen"`
+	// Outbox writes an event to the outbox with every change to a cat, for the outbox
+	// relay to publish.
+	Outbox bool `json:"outbox" yaml:"outbox"`
 }
 
 type Logging struct {
 	Level string `json:"level" yaml:"level"`
 }
 
+// Validation configures enforcement of the openapi spec on incoming requests.
+type Validation struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+}
+
+// Client configures how the cli subcommands reach a running server.
+type Client struct {
+	URL      string `json:"url" yaml:"url"`
+	Token    string `json:"token" yaml:"token" secret:"true"`
+	Username string `json:"username" yaml:"username"`
+	Password string `json:"password" yaml:"password" secret:"true"`
+}
+
+// GraphQL bounds the cost of graphql queries, values of 0 using the defaults.
+type GraphQL struct {
+	MaxDepth      int `json:"maxDepth" yaml:"maxDepth"`
+	MaxComplexity int `json:"maxComplexity" yaml:"maxComplexity"`
+}
+
+// Webhooks configures the delivery of events to webhooks, values of 0 using the defaults.
+type Webhooks struct {
+	MaxAttempts  int           `json:"maxAttempts" yaml:"maxAttempts"`
+	MinBackoff   time.Duration `json:"minBackoff" yaml:"minBackoff"`
+	MaxBackoff   time.Duration `json:"maxBackoff" yaml:"maxBackoff"`
+	Timeout      time.Duration `json:"timeout" yaml:"timeout"`
+	PollInterval time.Duration `json:"pollInterval" yaml:"pollInterval"`
+}
+
+// Stream configures the change stream, values of 0 using the defaults.
+type Stream struct {
+	// Heartbeat is how often idle streams are sent a heartbeat.
+	Heartbeat time.Duration `json:"heartbeat" yaml:"heartbeat"`
+	// Buffer is how many events a client may fall behind by before it is disconnected.
+	Buffer int `json:"buffer" yaml:"buffer"`
+	// History is how many of the latest events are kept for clients resuming a stream.
+	History int `json:"history" yaml:"history"`
+}
+
+// Cache configures the cache of cats read by id, which is disabled when the size is 0.
+type Cache struct {
+	// Size is the size of the cache in bytes.
+	Size int           `json:"size" yaml:"size"`
+	TTL  time.Duration `json:"ttl" yaml:"ttl"`
+}
+
+// Outbox configures the relay publishing the events written to the outbox when
+// Database.Outbox is enabled, values of 0 using the defaults.
+type Outbox struct {
+	// Publisher is where events are published: stdout, file, nats or kafka.
+	Publisher string `json:"publisher" yaml:"publisher"`
+	// Source is the source of the published cloudevents.
+	Source       string        `json:"source" yaml:"source"`
+	BatchSize    int           `json:"batchSize" yaml:"batchSize"`
+	PollInterval time.Duration `json:"pollInterval" yaml:"pollInterval"`
+	// Timeout bounds the publishing of every batch.
+	Timeout    time.Duration `json:"timeout" yaml:"timeout"`
+	MinBackoff time.Duration `json:"minBackoff" yaml:"minBackoff"`
+	MaxBackoff time.Duration `json:"maxBackoff" yaml:"maxBackoff"`
+	// File is the file events are appended to by the file publisher.
+	File  string `json:"file" yaml:"file"`
+	NATS  NATS   `json:"nats" yaml:"nats"`
+	Kafka Kafka  `json:"kafka" yaml:"kafka"`
+}
+
+// NATS configures the nats publisher, which publishes events to the subject suffixed with
+// their type.
+type NATS struct {
+	URL     string `json:"url" yaml:"url"`
+	Subject string `json:"subject" yaml:"subject"`
+}
+
+// Kafka configures the kafka publisher.
+type Kafka struct {
+	Brokers []string `json:"brokers" yaml:"brokers"`
+	Topic   string   `json:"topic" yaml:"topic"`
+}
+
+// Idempotency configures how long idempotency keys are kept, values of 0 using the defaults.
+type Idempotency struct {
+	// TTL is how long the response to a request made with a key is replayed for.
+	TTL time.Duration `json:"ttl" yaml:"ttl"`
+	// LockTimeout is how long a key stays reserved for a request that never completes.
+	LockTimeout time.Duration `json:"lockTimeout" yaml:"lockTimeout"`
+}
+
+// Tenancy scopes cats to the tenant of each request, authenticated by its api key, or named
+// by a header when the header is trusted.
+type Tenancy struct {
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// Header names the header holding the id of the tenant of a request.
+	Header string `json:"header" yaml:"header"`
+	// TrustHeader accepts the tenant named by the header from callers without an api key,
+	// which is only safe behind a gateway authenticating them.
+	TrustHeader bool `json:"trustHeader" yaml:"trustHeader"`
+	// AdminToken authenticates the tenant administration endpoints, which are disabled
+	// without one.
+	AdminToken string `json:"adminToken" yaml:"adminToken" secret:"true"`
+}
+
+// Photos configures the photos of cats, which are not supported without a blob store.
+type Photos struct {
+	// MaxSize is the largest photo accepted, in bytes.
+	MaxSize int64 `json:"maxSize" yaml:"maxSize"`
+	// ThumbnailSize is the largest width and height of thumbnails, in pixels.
+	ThumbnailSize int  `json:"thumbnailSize" yaml:"thumbnailSize"`
+	Blob          Blob `json:"blob" yaml:"blob"`
+}
+
+// Blob configures where photos are stored.
+type Blob struct {
+	// Store is fs, for a directory of the local filesystem, or s3, for an s3 compatible
+	// object store. Photos are disabled when it is empty.
+	Store string `json:"store" yaml:"store"`
+	Dir   string `json:"dir" yaml:"dir"`
+	S3    S3     `json:"s3" yaml:"s3"`
+}
+
+// S3 configures an s3 compatible object store.
+type S3 struct {
+	Endpoint  string `json:"endpoint" yaml:"endpoint"`
+	Bucket    string `json:"bucket" yaml:"bucket"`
+	Region    string `json:"region" yaml:"region"`
+	AccessKey string `json:"accessKey" yaml:"accessKey" secret:"true"`
+	SecretKey string `json:"secretKey" yaml:"secretKey" secret:"true"`
+	UseSSL    bool   `json:"useSSL" yaml:"useSSL"`
+	// PathStyle addresses buckets in the path rather than the host, as local stand-ins
+	// such as minio require.
+	PathStyle bool `json:"pathStyle" yaml:"pathStyle"`
+}
+
+// Reference configures how cats are normalised against the breeds and colors of the
+// reference data.
+type Reference struct {
+	// Strict rejects cats of unknown breeds and colors, which are otherwise kept as given.
+	Strict bool `json:"strict" yaml:"strict"`
+}
+
+// Health configures the readiness checks.
+type Health struct {
+	Timeout  time.Duration `json:"timeout" yaml:"timeout"`
+	CacheTTL time.Duration `json:"cacheTTL" yaml:"cacheTTL"`
+}
+
 // SaneDefaults provides base config for testing
 func SaneDefaults() Config {
 	var config = Config{
+		Environment: EnvTest,
 		Server: Server{
-			Port: "8090",
-			Cert: "certs/cert.crt",
-			Key:  "certs/cert.key",
-			TLS:  false,
+			Port:     "8090",
+			Cert:     "certs/cert.crt",
+			Key:      "certs/cert.key",
+			TLS:      false,
+			GRPCPort: "9090",
 		},
 		Database: Database{
 			Host:         "127.0.0.1",
@@ -46,10 +227,73 @@ func SaneDefaults() Config {
 			DatabaseName: "test",
 			SslMode:      "disable",
 			SslFactory:   "org.postgresql.ssl.NonValidatingFactory",
+			Listen:       true,
 		},
 		Logging: Logging{
 			Level: "debug",
 		},
+		Health: Health{
+			Timeout:  2 * time.Second,
+			CacheTTL: 5 * time.Second,
+		},
+		Validation: Validation{
+			Enabled: true,
+		},
+		Client: Client{
+			URL: "http://localhost:8090",
+		},
+		GraphQL: GraphQL{
+			MaxDepth:      8,
+			MaxComplexity: 500,
+		},
+		Webhooks: Webhooks{
+			MaxAttempts:  8,
+			MinBackoff:   5 * time.Second,
+			MaxBackoff:   time.Hour,
+			Timeout:      10 * time.Second,
+			PollInterval: time.Second,
+		},
+		Stream: Stream{
+			Heartbeat: 15 * time.Second,
+			Buffer:    64,
+			History:   1024,
+		},
+		Outbox: Outbox{
+			Publisher:    "stdout",
+			Source:       "/cats/v1",
+			BatchSize:    100,
+			PollInterval: time.Second,
+			Timeout:      10 * time.Second,
+			MinBackoff:   time.Second,
+			MaxBackoff:   5 * time.Minute,
+			NATS: NATS{
+				URL:     "nats://127.0.0.1:4222",
+				Subject: "cats",
+			},
+			Kafka: Kafka{
+				Brokers: []string{"127.0.0.1:9092"},
+				Topic:   "cats",
+			},
+		},
+		Idempotency: Idempotency{
+			TTL:         24 * time.Hour,
+			LockTimeout: time.Minute,
+		},
+		Tenancy: Tenancy{
+			Header: "X-Tenant-ID",
+		},
+		Photos: Photos{
+			MaxSize:       10 << 20,
+			ThumbnailSize: 256,
+			Blob: Blob{
+				Dir: "photos",
+				S3: S3{
+					Endpoint: "127.0.0.1:9000",
+					Bucket:   "cats",
+					Region:   "us-east-1",
+				},
+			},
+		},
 	}
 	return config
 }
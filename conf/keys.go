@@ -0,0 +1,53 @@
+package conf
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Setting is the value of a single key of the config.
+type Setting struct {
+	Key   string
+	Value interface{}
+	// Secret settings, tagged secret:"true", are redacted when shown.
+	Secret bool
+}
+
+// Settings returns every setting of the config, such as database.password, in the order of
+// the fields of Config.
+func Settings(c Config) []Setting {
+	return settings(reflect.ValueOf(c), "")
+}
+
+func settings(v reflect.Value, prefix string) []Setting {
+	var s []Setting
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := prefix + fieldKey(f)
+		if f.Type.Kind() == reflect.Struct {
+			s = append(s, settings(v.Field(i), key+".")...)
+			continue
+		}
+		s = append(s, Setting{Key: key, Value: v.Field(i).Interface(), Secret: f.Tag.Get("secret") == "true"})
+	}
+	return s
+}
+
+// Keys returns the key of every setting of the config, in the order of the fields of
+// Config.
+func Keys() []string {
+	var keys []string
+	for _, s := range Settings(Config{}) {
+		keys = append(keys, s.Key)
+	}
+	return keys
+}
+
+// fieldKey returns the key of the setting of a field of the config, named by its json tag.
+func fieldKey(f reflect.StructField) string {
+	if name := strings.Split(f.Tag.Get("json"), ",")[0]; name != "" {
+		return name
+	}
+	return f.Name
+}
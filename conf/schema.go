@@ -0,0 +1,64 @@
+package conf
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+)
+
+// durationPattern matches durations as parsed by time.ParseDuration, such as 1m30s.
+const durationPattern = `^(0|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+
+// enums lists the values of the settings taking one of a few, empty ones using the default.
+var enums = map[string][]string{
+	"environment":       {EnvDev, EnvTest, EnvProd},
+	"logging.level":     {"", "trace", "debug", "info", "warn", "error", "fatal", "panic"},
+	"outbox.publisher":  {"", "stdout", "file", "nats", "kafka"},
+	"photos.blob.store": {"", "fs", "s3"},
+}
+
+// Schema returns an indented JSON Schema of config files, generated from Config, for
+// editors to complete and check them.
+func Schema() ([]byte, error) {
+	s := objectSchema(reflect.TypeOf(Config{}), "")
+	s["$schema"] = "http://json-schema.org/draft-07/schema#"
+	s["title"] = "cats-v1 config"
+	return json.MarshalIndent(s, "", "  ")
+}
+
+func objectSchema(t reflect.Type, prefix string) map[string]interface{} {
+	properties := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := fieldKey(f)
+		properties[key] = fieldSchema(f.Type, prefix+key)
+	}
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
+
+func fieldSchema(t reflect.Type, key string) map[string]interface{} {
+	if t == reflect.TypeOf(time.Duration(0)) {
+		return map[string]interface{}{"type": "string", "pattern": durationPattern}
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		return objectSchema(t, key+".")
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": fieldSchema(t.Elem(), key)}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return map[string]interface{}{"type": "integer", "minimum": 0}
+	case reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	s := map[string]interface{}{"type": "string"}
+	if values, ok := enums[key]; ok {
+		s["enum"] = values
+	}
+	return s
+}
@@ -0,0 +1,169 @@
+package conf
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// FieldError describes a single invalid setting of the config, named by its key.
+type FieldError struct {
+	Key    string `json:"key"`
+	Reason string `json:"reason"`
+}
+
+// ValidationError lists every invalid setting of the config.
+type ValidationError []FieldError
+
+func (v ValidationError) Error() string {
+	reasons := make([]string, len(v))
+	for i, e := range v {
+		reasons[i] = fmt.Sprintf("%s %s", e.Key, e.Reason)
+	}
+	return "invalid config: " + strings.Join(reasons, ", ")
+}
+
+// Validate checks that the application can be bootstrapped with the config, returning a
+// ValidationError listing every invalid setting. Settings whose zero value means the
+// default are only checked when they are set.
+func (c Config) Validate() error {
+	var errs ValidationError
+	invalid := func(key, reason string) {
+		errs = append(errs, FieldError{Key: key, Reason: reason})
+	}
+	notNegative := func(key string, n int64) {
+		if n < 0 {
+			invalid(key, "must not be negative")
+		}
+	}
+	backoff := func(prefix string, minimum, maximum time.Duration) {
+		notNegative(prefix+".minBackoff", int64(minimum))
+		notNegative(prefix+".maxBackoff", int64(maximum))
+		if minimum > 0 && maximum > 0 && minimum > maximum {
+			invalid(prefix+".maxBackoff", "must not be less than "+prefix+".minBackoff")
+		}
+	}
+
+	switch c.Environment {
+	case "", EnvDev, EnvTest, EnvProd:
+	default:
+		invalid("environment", fmt.Sprintf("must be %s, %s or %s", EnvDev, EnvTest, EnvProd))
+	}
+
+	if !validPort(c.Server.Port) {
+		invalid("server.port", "must be a port from 1 to 65535")
+	}
+	if c.Server.GRPCPort != "" {
+		if !validPort(c.Server.GRPCPort) {
+			invalid("server.grpcPort", "must be a port from 1 to 65535")
+		} else if c.Server.GRPCPort == c.Server.Port {
+			invalid("server.grpcPort", "must differ from server.port")
+		}
+	}
+	if c.Server.TLS {
+		if c.Server.Cert == "" {
+			invalid("server.cert", "is required when server.tls is enabled")
+		}
+		if c.Server.Key == "" {
+			invalid("server.key", "is required when server.tls is enabled")
+		}
+	}
+
+	if c.Database.Host == "" {
+		invalid("database.host", "is required")
+	}
+	if c.Database.Port < 1 || c.Database.Port > 65535 {
+		invalid("database.port", "must be a port from 1 to 65535")
+	}
+	if c.Database.DatabaseName == "" {
+		invalid("database.databaseName", "is required")
+	}
+
+	if c.Logging.Level != "" {
+		if _, err := zerolog.ParseLevel(c.Logging.Level); err != nil {
+			invalid("logging.level", "must be trace, debug, info, warn, error, fatal or panic")
+		}
+	}
+	notNegative("health.timeout", int64(c.Health.Timeout))
+	notNegative("health.cacheTTL", int64(c.Health.CacheTTL))
+
+	if c.Client.URL != "" {
+		if u, err := url.Parse(c.Client.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("client.url", "must be an http or https url")
+		}
+	}
+
+	notNegative("graphql.maxDepth", int64(c.GraphQL.MaxDepth))
+	notNegative("graphql.maxComplexity", int64(c.GraphQL.MaxComplexity))
+
+	notNegative("webhooks.maxAttempts", int64(c.Webhooks.MaxAttempts))
+	backoff("webhooks", c.Webhooks.MinBackoff, c.Webhooks.MaxBackoff)
+	notNegative("webhooks.timeout", int64(c.Webhooks.Timeout))
+	notNegative("webhooks.pollInterval", int64(c.Webhooks.PollInterval))
+
+	notNegative("stream.heartbeat", int64(c.Stream.Heartbeat))
+	notNegative("stream.buffer", int64(c.Stream.Buffer))
+	notNegative("stream.history", int64(c.Stream.History))
+
+	notNegative("cache.size", int64(c.Cache.Size))
+	notNegative("cache.ttl", int64(c.Cache.TTL))
+
+	switch c.Outbox.Publisher {
+	case "", "stdout":
+	case "file":
+		if c.Database.Outbox && c.Outbox.File == "" {
+			invalid("outbox.file", "is required by the file publisher")
+		}
+	case "nats":
+		if c.Database.Outbox && c.Outbox.NATS.URL == "" {
+			invalid("outbox.nats.url", "is required by the nats publisher")
+		}
+	case "kafka":
+		if c.Database.Outbox && len(c.Outbox.Kafka.Brokers) == 0 {
+			invalid("outbox.kafka.brokers", "are required by the kafka publisher")
+		}
+	default:
+		invalid("outbox.publisher", "must be stdout, file, nats or kafka")
+	}
+	notNegative("outbox.batchSize", int64(c.Outbox.BatchSize))
+	notNegative("outbox.pollInterval", int64(c.Outbox.PollInterval))
+	notNegative("outbox.timeout", int64(c.Outbox.Timeout))
+	backoff("outbox", c.Outbox.MinBackoff, c.Outbox.MaxBackoff)
+
+	notNegative("idempotency.ttl", int64(c.Idempotency.TTL))
+	notNegative("idempotency.lockTimeout", int64(c.Idempotency.LockTimeout))
+
+	notNegative("photos.maxSize", c.Photos.MaxSize)
+	notNegative("photos.thumbnailSize", int64(c.Photos.ThumbnailSize))
+	switch c.Photos.Blob.Store {
+	case "":
+	case "fs":
+		if c.Photos.Blob.Dir == "" {
+			invalid("photos.blob.dir", "is required by the fs store")
+		}
+	case "s3":
+		if c.Photos.Blob.S3.Endpoint == "" {
+			invalid("photos.blob.s3.endpoint", "is required by the s3 store")
+		}
+		if c.Photos.Blob.S3.Bucket == "" {
+			invalid("photos.blob.s3.bucket", "is required by the s3 store")
+		}
+	default:
+		invalid("photos.blob.store", "must be fs or s3")
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// validPort reports whether port is a tcp port other than 0.
+func validPort(port string) bool {
+	n, err := strconv.Atoi(port)
+	return err == nil && n >= 1 && n <= 65535
+}
@@ -0,0 +1,99 @@
+package conf
+
+import (
+	"testing"
+	"time"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		change func(c *Config)
+		// then
+		expectedErr string
+	}{
+		{
+			description: "sane defaults",
+			change:      func(c *Config) {},
+		},
+		{
+			description: "no environment",
+			change:      func(c *Config) { c.Environment = "" },
+		},
+		{
+			description: "out of range port",
+			change:      func(c *Config) { c.Server.Port = "70000" },
+			expectedErr: "invalid config: server.port must be a port from 1 to 65535",
+		},
+		{
+			description: "tls without a cert",
+			change: func(c *Config) {
+				c.Server.TLS = true
+				c.Server.Cert = ""
+			},
+			expectedErr: "invalid config: server.cert is required when server.tls is enabled",
+		},
+		{
+			description: "every invalid setting is reported",
+			change: func(c *Config) {
+				c.Environment = "staging"
+				c.Server.GRPCPort = c.Server.Port
+				c.Database.Port = 0
+				c.Logging.Level = "loud"
+				c.Webhooks.MinBackoff = time.Hour
+				c.Webhooks.MaxBackoff = time.Minute
+				c.Photos.Blob.Store = "s3"
+				c.Photos.Blob.S3.Bucket = ""
+			},
+			expectedErr: "invalid config: environment must be dev, test or prod, server.grpcPort must differ from server.port, " +
+				"database.port must be a port from 1 to 65535, logging.level must be trace, debug, info, warn, error, fatal or panic, " +
+				"webhooks.maxBackoff must not be less than webhooks.minBackoff, photos.blob.s3.bucket is required by the s3 store",
+		},
+		{
+			description: "publishers are only checked with the outbox enabled",
+			change: func(c *Config) {
+				c.Outbox.Publisher = "kafka"
+				c.Outbox.Kafka.Brokers = nil
+			},
+		},
+		{
+			description: "kafka publisher without brokers",
+			change: func(c *Config) {
+				c.Database.Outbox = true
+				c.Outbox.Publisher = "kafka"
+				c.Outbox.Kafka.Brokers = nil
+			},
+			expectedErr: "invalid config: outbox.kafka.brokers are required by the kafka publisher",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			config := SaneDefaults()
+			tt.change(&config)
+
+			err := config.Validate()
+			if tt.expectedErr == "" && err != nil {
+				t.Errorf("unxpected error: %v", err)
+			}
+			if tt.expectedErr != "" && (err == nil || err.Error() != tt.expectedErr) {
+				t.Errorf("unxpected error: got %v, expected %s", err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestKeys(t *testing.T) {
+	keys := map[string]bool{}
+	for _, key := range Keys() {
+		keys[key] = true
+	}
+	for _, key := range []string{"environment", "database.password", "outbox.kafka.brokers", "photos.blob.s3.secretKey"} {
+		if !keys[key] {
+			t.Errorf("unxpected keys: %s is missing from %v", key, Keys())
+		}
+	}
+	if keys["database"] || keys["photos.blob"] {
+		t.Errorf("unxpected keys: sections are listed in %v", Keys())
+	}
+}
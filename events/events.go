@@ -0,0 +1,174 @@
+// Package events broadcasts changes to cats to subscribers within the process.
+package events
+
+import (
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+	"github.com/waikco/cats-v1/model"
+)
+
+// Type is the kind of change an event describes.
+type Type string
+
+const (
+	Created Type = model.CatCreated
+	Updated Type = model.CatUpdated
+	Deleted Type = model.CatDeleted
+)
+
+const (
+	// DefaultBuffer is the number of events a subscriber may fall behind by before events are dropped.
+	DefaultBuffer = 64
+	// DefaultHistory is the number of latest events kept for subscribers resuming a subscription.
+	DefaultHistory = 1024
+)
+
+// Event describes a change to a cat.
+type Event struct {
+	ID    string `json:"id"`
+	Type  Type   `json:"type"`
+	CatID string `json:"catId"`
+	// Tenant is the tenant owning the cat, when tenancy is enabled.
+	Tenant string `json:"tenant,omitempty"`
+	// Cat is the cat after the change, and is nil for deletions.
+	Cat  *model.Cat `json:"cat,omitempty"`
+	Time time.Time  `json:"time"`
+}
+
+// New creates an event with a new id, happening now.
+func New(t Type, catID string, cat *model.Cat) Event {
+	return Event{
+		ID:    uuid.NewV4().String(),
+		Type:  t,
+		CatID: catID,
+		Cat:   cat,
+		Time:  time.Now().UTC(),
+	}
+}
+
+// Bus delivers published events to every current subscriber, and keeps a bounded history
+// of the latest events for subscribers resuming after an earlier subscription.
+type Bus struct {
+	mu          sync.RWMutex
+	subscribers map[chan Event]*subscriber
+	history     []Event
+	historySize int
+}
+
+type subscriber struct {
+	// evict ends the subscription when its buffer is full, rather than dropping events.
+	evict bool
+	close func()
+}
+
+// NewBus creates a bus without subscribers, keeping the DefaultHistory latest events.
+func NewBus() *Bus {
+	return NewBusWithHistory(DefaultHistory)
+}
+
+// NewBusWithHistory creates a bus without subscribers, keeping the size latest events.
+func NewBusWithHistory(size int) *Bus {
+	if size < 0 {
+		size = 0
+	}
+	return &Bus{subscribers: map[chan Event]*subscriber{}, historySize: size}
+}
+
+// Publish delivers an event to every subscriber without blocking. Subscribers whose buffer
+// is full have the event dropped, or their subscription ended when they resumed it.
+func (b *Bus) Publish(e Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.historySize > 0 {
+		if len(b.history) == b.historySize {
+			b.history = append(b.history[:0], b.history[1:]...)
+		}
+		b.history = append(b.history, e)
+	}
+	for ch, sub := range b.subscribers {
+		select {
+		case ch <- e:
+		default:
+			if sub.evict {
+				log.Warn().Msgf("ended the subscription of a slow subscriber at %s event %s", e.Type, e.ID)
+				delete(b.subscribers, ch)
+				sub.close()
+				continue
+			}
+			log.Warn().Msgf("dropped %s event %s for a slow subscriber", e.Type, e.ID)
+		}
+	}
+}
+
+// Subscribe returns a channel receiving every event published from now on, buffering up
+// to buffer events, and a function ending the subscription and closing the channel.
+func (b *Bus) Subscribe(buffer int) (<-chan Event, func()) {
+	ch, cancel := b.subscribe(buffer, false)
+	b.mu.Unlock()
+	return ch, cancel
+}
+
+// Subscription is a subscription resumed after an earlier one.
+type Subscription struct {
+	// Events receives every event published after the subscription was made, and is
+	// closed once the subscriber falls further behind than its buffer.
+	Events <-chan Event
+	// Replay holds the events published after the last one seen, oldest first.
+	Replay []Event
+	// Missed reports the last event seen is no longer in the history, so that events
+	// published since may be missing from Replay.
+	Missed bool
+	// Close ends the subscription and closes Events.
+	Close func()
+}
+
+// Resume subscribes to every event published after the one with id lastID, replaying those
+// still in the history. An empty lastID starts a new subscription without replay. Rather
+// than dropping events, the subscription is ended when the subscriber falls behind, so it
+// can resume from the last event it received.
+func (b *Bus) Resume(lastID string, buffer int) *Subscription {
+	ch, cancel := b.subscribe(buffer, true)
+	defer b.mu.Unlock()
+
+	sub := &Subscription{Events: ch, Close: cancel}
+	if lastID == "" {
+		return sub
+	}
+	sub.Missed = true
+	for i, e := range b.history {
+		if e.ID == lastID {
+			sub.Replay = append([]Event(nil), b.history[i+1:]...)
+			sub.Missed = false
+			break
+		}
+	}
+	if sub.Missed {
+		sub.Replay = append([]Event(nil), b.history...)
+	}
+	return sub
+}
+
+// subscribe registers a subscriber, returning with the lock held so callers can read the
+// history consistently with the events the subscriber will receive.
+func (b *Bus) subscribe(buffer int, evict bool) (chan Event, func()) {
+	if buffer < 1 {
+		buffer = DefaultBuffer
+	}
+	ch := make(chan Event, buffer)
+
+	var once sync.Once
+	closeCh := func() { once.Do(func() { close(ch) }) }
+
+	b.mu.Lock()
+	b.subscribers[ch] = &subscriber{evict: evict, close: closeCh}
+
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		b.mu.Unlock()
+		closeCh()
+	}
+}
@@ -0,0 +1,98 @@
+package events
+
+import (
+	"testing"
+	"time"
+
+	"github.com/waikco/cats-v1/model"
+)
+
+func TestBus(t *testing.T) {
+	bus := NewBus()
+	first, cancelFirst := bus.Subscribe(1)
+	second, cancelSecond := bus.Subscribe(1)
+	defer cancelSecond()
+
+	created := New(Created, "1", &model.Cat{ID: "1", Name: "cat-1"})
+	bus.Publish(created)
+	for _, ch := range []<-chan Event{first, second} {
+		select {
+		case got := <-ch:
+			if got.ID != created.ID || got.Type != Created || got.Cat.Name != "cat-1" {
+				t.Errorf("unxpected event: got %+v, expected %+v", got, created)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("event was not delivered")
+		}
+	}
+
+	// the first subscription has ended, and the second's buffer fills after one event
+	cancelFirst()
+	cancelFirst()
+	if _, ok := <-first; ok {
+		t.Error("unxpected event after cancelling the subscription")
+	}
+	bus.Publish(New(Updated, "1", &model.Cat{ID: "1"}))
+	bus.Publish(New(Deleted, "1", nil))
+	if got := <-second; got.Type != Updated {
+		t.Errorf("unxpected event type: got %s, expected %s", got.Type, Updated)
+	}
+	select {
+	case got := <-second:
+		t.Errorf("unxpected event delivered to a full subscriber: %+v", got)
+	default:
+	}
+}
+
+func TestBus_Resume(t *testing.T) {
+	bus := NewBusWithHistory(2)
+	first := New(Created, "1", &model.Cat{ID: "1"})
+	second := New(Updated, "1", &model.Cat{ID: "1"})
+	third := New(Deleted, "1", nil)
+	bus.Publish(first)
+	bus.Publish(second)
+
+	tests := []struct {
+		description    string
+		lastID         string
+		expectedReplay []Event
+		expectedMissed bool
+	}{
+		{description: "new subscription"},
+		{description: "from the history", lastID: first.ID, expectedReplay: []Event{second}},
+		{description: "up to date", lastID: second.ID},
+		{description: "from beyond the history", lastID: "unknown", expectedReplay: []Event{first, second}, expectedMissed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			sub := bus.Resume(tt.lastID, 1)
+			defer sub.Close()
+			if len(sub.Replay) != len(tt.expectedReplay) {
+				t.Fatalf("unxpected replay: got %+v, expected %+v", sub.Replay, tt.expectedReplay)
+			}
+			for i, e := range sub.Replay {
+				if e.ID != tt.expectedReplay[i].ID {
+					t.Errorf("unxpected replayed event %d: got %s, expected %s", i, e.ID, tt.expectedReplay[i].ID)
+				}
+			}
+			if sub.Missed != tt.expectedMissed {
+				t.Errorf("unxpected missed: got %t, expected %t", sub.Missed, tt.expectedMissed)
+			}
+		})
+	}
+
+	// the oldest event leaves the history, and a subscriber falling behind is ended
+	sub := bus.Resume("", 1)
+	bus.Publish(third)
+	bus.Publish(New(Created, "2", &model.Cat{ID: "2"}))
+	if got := <-sub.Events; got.ID != third.ID {
+		t.Errorf("unxpected event: got %s, expected %s", got.ID, third.ID)
+	}
+	if _, ok := <-sub.Events; ok {
+		t.Error("unxpected event after falling behind")
+	}
+	sub.Close()
+	if resumed := bus.Resume(first.ID, 1); !resumed.Missed {
+		t.Errorf("unxpected resumption from an event no longer in the history: %+v", resumed.Replay)
+	}
+}
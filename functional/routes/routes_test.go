@@ -1,26 +1,29 @@
 package routes
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"io/ioutil"
 	"net/http"
 	"os"
+	"reflect"
 	"testing"
 
 	uuid "github.com/satori/go.uuid"
 
 	"github.com/jmoiron/sqlx"
-	json "github.com/json-iterator/go"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"github.com/waikco/cats-v1/client"
 	"github.com/waikco/cats-v1/functional"
 	"github.com/waikco/cats-v1/model"
 )
 
 const urlStart = "http://localhost:8080"
 
-var db *sqlx.DB
+var (
+	db  *sqlx.DB
+	api *client.Client
+)
 
 func TestMain(m *testing.M) {
 	process, output, err := functional.StartBinary("config.yaml")
@@ -31,6 +34,12 @@ func TestMain(m *testing.M) {
 			output.String())
 	}
 
+	c, err := client.New(urlStart)
+	if err != nil {
+		log.Fatal().Err(err)
+	}
+	api = c
+
 	if err := confirmTable(); err != nil {
 		log.Fatal().Err(err)
 	}
@@ -58,139 +67,93 @@ func purgeTable() {
 func TestEmptyTable(t *testing.T) {
 	purgeTable()
 
-	req, err := http.NewRequest("GET", urlStart+"/cats/v1/cats", nil)
-	if err != nil {
-		t.Fatalf("error creating request: %v", err)
-	}
-	client := http.DefaultClient
-	response, err := client.Do(req)
+	cats, err := api.ListCats(context.Background(), client.ListOptions{})
 	if err != nil {
 		t.Fatalf("err making request: %v", err)
 	}
-
-	if response.StatusCode != http.StatusOK {
-		t.Errorf("unexpcted status code: got %d, want %d", response.StatusCode, http.StatusOK)
-	}
-	if body, err := ioutil.ReadAll(response.Body); err != nil && string(body) != `[]` {
-		t.Errorf("unexpcted body: got %s, want %s", string(body), `[]`)
+	if len(cats) != 0 {
+		t.Errorf("unexpected cats: got %+v, want none", cats)
 	}
 }
 
 func TestGetNonExistentCat(t *testing.T) {
-	req, _ := http.NewRequest(http.MethodGet, urlStart+"/cats/v1/cats/9C679C16-C38B-48A3-A645-A6F2457A49BC", nil)
-	client := http.DefaultClient
-	response, err := client.Do(req)
-	if err != nil {
-		t.Errorf("err making request: %v", err)
-	}
-
-	if response.StatusCode != http.StatusNotFound {
-		t.Errorf("unexpected status code: got %d, want %d", response.StatusCode, http.StatusNotFound)
-	}
-	if body, err := ioutil.ReadAll(response.Body); err != nil && string(body) != `[]` {
-		t.Errorf("unexpected body: got %s, want %s", string(body), `[]`)
+	_, err := api.GetCat(context.Background(), "9C679C16-C38B-48A3-A645-A6F2457A49BC")
+	if !client.IsNotFound(err) {
+		t.Errorf("unexpected error: got %v, want status %d", err, http.StatusNotFound)
 	}
 }
 
 func TestCRUD(t *testing.T) {
-	payload := `{"name":"cat-1","color":"color-1","age":1}`
+	ctx := context.Background()
+	cat := model.Cat{Name: "cat-1", Color: "color-1", Age: 1}
 	var id uuid.UUID
 
 	t.Run("create cat", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodPost,
-			urlStart+"/cats/v1/",
-			bytes.NewBuffer([]byte(payload)))
-		client := http.DefaultClient
-		response, err := client.Do(req)
+		result, err := api.CreateCat(ctx, cat)
 		if err != nil {
-			t.Errorf("err making request: %v", err)
-		}
-
-		if response.StatusCode != http.StatusCreated {
-			t.Errorf("unexpected status code: got %d, want %d", response.StatusCode, http.StatusCreated)
+			t.Fatalf("err making request: %v", err)
 		}
 
-		var m map[string]interface{}
-		if err := json.NewDecoder(response.Body).Decode(&m); err != nil {
-			t.Fatalf("error reading bod: %v", err)
-		}
-		id, _ = uuid.FromString(m["result"].(string))
+		id, _ = uuid.FromString(result)
 		if id == uuid.Nil {
-			t.Errorf("unexpected result: got %s, want valid UUID", m["result"].(string))
-		}
-		if errs := m["errors"]; errs != nil {
-			t.Errorf("unexpected errors in response: %v", m["errors"])
+			t.Errorf("unexpected result: got %s, want valid UUID", result)
 		}
 	})
 
 	t.Run("get cat", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodGet, urlStart+"/cats/v1/cats/"+id.String(), nil)
-		client := http.DefaultClient
-		response, err := client.Do(req)
+		got, err := api.GetCat(ctx, id.String())
 		if err != nil {
-			t.Errorf("err making request: %v", err)
+			t.Fatalf("err making request: %v", err)
 		}
-
-		if response.StatusCode != http.StatusOK {
-			t.Errorf("unexpcted status code: got %d, want %d", response.StatusCode, http.StatusOK)
-		}
-		if body, _ := ioutil.ReadAll(response.Body); string(body) != payload {
-			t.Errorf("unexpected body: got %s, want %s", string(body), payload)
+		// new cats start at intake
+		want := cat
+		want.Status = model.StatusIntake
+		if !reflect.DeepEqual(*got, want) {
+			t.Errorf("unexpected cat: got %+v, want %+v", *got, want)
 		}
 	})
 
-	var newCat = `{"name":"new-cat-1","color":"orange","age":3}`
+	var newCat = model.Cat{Name: "new-cat-1", Color: "ginger", Age: 3}
 	t.Run("update cat", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodPut, urlStart+"/cats/v1/"+id.String(),
-			bytes.NewBuffer([]byte(newCat)))
-		client := http.DefaultClient
-		response, err := client.Do(req)
-		if err != nil {
-			t.Errorf("err making request: %v", err)
+		if err := api.UpdateCat(ctx, id.String(), newCat); err != nil {
+			t.Fatalf("err making request: %v", err)
 		}
 
-		if response.StatusCode != http.StatusOK {
-			t.Errorf("unexpcted status code: got %d, want %d", response.StatusCode, http.StatusOK)
-		}
-		var m map[string]interface{}
-		if err := json.NewDecoder(response.Body).Decode(&m); err != nil {
-			t.Fatalf("error reading body: %v", err)
+		got, err := api.GetCat(ctx, id.String())
+		if err != nil {
+			t.Fatalf("err making request: %v", err)
 		}
-		if m["result"] != "success" {
-			t.Errorf("unexpected result: got %+v, want `result` to equal `success`", m)
+		// updates leave the status as it is
+		want := newCat
+		want.Status = model.StatusIntake
+		if !reflect.DeepEqual(*got, want) {
+			t.Errorf("unexpected new cat: got %+v, want %+v", *got, want)
 		}
+	})
 
-		req, _ = http.NewRequest(http.MethodGet, urlStart+"/cats/v1/cats/"+id.String(), nil)
-		response, err = client.Do(req)
-		if err != nil {
-			t.Errorf("err making request: %v", err)
+	t.Run("list cats", func(t *testing.T) {
+		var found bool
+		it := api.Cats(ctx, client.ListOptions{})
+		for it.Next() {
+			if it.Cat().ID == id.String() {
+				found = true
+			}
 		}
-
-		if response.StatusCode != http.StatusOK {
-			t.Errorf("unexpected status code: got %d, want %d", response.StatusCode, http.StatusOK)
+		if err := it.Err(); err != nil {
+			t.Fatalf("err making request: %v", err)
 		}
-		if body, _ := ioutil.ReadAll(response.Body); string(body) != newCat {
-			t.Errorf("unexpected new cat: got %s, want %s", string(body), newCat)
+		if !found {
+			t.Errorf("expected cat %s to be listed", id)
 		}
 	})
 
 	t.Run("delete cat", func(t *testing.T) {
-		req, _ := http.NewRequest(http.MethodDelete, urlStart+"/cats/v1/cats/"+id.String(), nil)
-		client := http.DefaultClient
-		response, err := client.Do(req)
-		if err != nil {
-			t.Errorf("err making request: %v", err)
+		if err := api.DeleteCat(ctx, id.String()); err != nil {
+			t.Fatalf("err making request: %v", err)
 		}
 
-		if response.StatusCode != http.StatusOK {
-			t.Errorf("unexpcted status code: got %d, want %d", response.StatusCode, http.StatusOK)
-		}
-		var m map[string]interface{}
-		if err := json.NewDecoder(response.Body).Decode(&m); err != nil {
-			t.Fatalf("error reading body: %v", err)
-		}
-		if m["result"] != "success" {
-			t.Errorf("unexpected result: got %+v, want `result` to equal `success`", m)
+		if _, err := api.GetCat(ctx, id.String()); !client.IsNotFound(err) {
+			t.Errorf("unexpected error: got %v, want status %d", err, http.StatusNotFound)
 		}
 	})
 }
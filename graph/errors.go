@@ -0,0 +1,31 @@
+package graph
+
+// Error codes reported in the extensions of graphql errors.
+const (
+	codeBadUserInput     = "BAD_USER_INPUT"
+	codeNotFound         = "NOT_FOUND"
+	codeQueryTooComplex  = "QUERY_TOO_COMPLEX"
+	codeMethodNotAllowed = "METHOD_NOT_ALLOWED"
+	codeQuotaExceeded    = "QUOTA_EXCEEDED"
+	codeConflict         = "CONFLICT"
+	codeInternal         = "INTERNAL"
+)
+
+// Error is a graphql error carrying a code, and any other details, in its extensions.
+type Error struct {
+	message    string
+	extensions map[string]interface{}
+}
+
+func newError(code, message string) *Error {
+	return &Error{message: message, extensions: map[string]interface{}{"code": code}}
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+// Extensions implements gqlerrors.ExtendedError.
+func (e *Error) Extensions() map[string]interface{} {
+	return e.extensions
+}
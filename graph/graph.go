@@ -0,0 +1,105 @@
+// Package graph serves the cats model through a graphql schema, batching the cats
+// requested by id and limiting the depth and complexity of queries.
+package graph
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/model"
+	"github.com/waikco/cats-v1/reference"
+)
+
+// Request is a graphql request, as sent in the body of a POST request.
+type Request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
+
+// Executor executes graphql requests against storage.
+type Executor struct {
+	schema graphql.Schema
+	limits Limits
+}
+
+// New creates an executor announcing the changes made by mutations to publish, normalising
+// the cats they write against catalog.
+func New(publish Publisher, catalog *reference.Catalog, limits Limits) (*Executor, error) {
+	if publish == nil {
+		publish = func(context.Context, events.Type, string, *model.Cat) {}
+	}
+	schema, err := newSchema(publish, catalog)
+	if err != nil {
+		return nil, err
+	}
+	return &Executor{schema: schema, limits: limits.withDefaults()}, nil
+}
+
+// Execute runs a request against storage. Mutations are refused unless allowed, as they must not be sent
+// in GET requests.
+func (e *Executor) Execute(ctx context.Context, storage model.Storage, req Request, allowMutations bool) *graphql.Result {
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	if result := graphql.ValidateDocument(&e.schema, doc, nil); !result.IsValid {
+		return &graphql.Result{Errors: result.Errors}
+	}
+
+	op := operation(doc, req.OperationName)
+	if op == nil {
+		return errorResult(newError(codeBadUserInput, "unknown operation "+req.OperationName))
+	}
+	if op.Operation == ast.OperationTypeMutation && !allowMutations {
+		return errorResult(newError(codeMethodNotAllowed, "mutations must be sent with POST"))
+	}
+	if err := e.limits.check(doc, op, req.Variables); err != nil {
+		return errorResult(err)
+	}
+
+	return graphql.Execute(graphql.ExecuteParams{
+		Schema:        e.schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       context.WithValue(ctx, loaderKey{}, newLoader(storage)),
+	})
+}
+
+// operation finds the operation to execute, which must be named unless it is the only one.
+func operation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
+
+func errorResult(err *Error) *graphql.Result {
+	return &graphql.Result{Errors: []gqlerrors.FormattedError{{
+		Message:    err.Error(),
+		Locations:  []location.SourceLocation{},
+		Extensions: err.Extensions(),
+	}}}
+}
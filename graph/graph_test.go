@@ -0,0 +1,244 @@
+package graph
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	json "github.com/json-iterator/go"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/model"
+)
+
+const (
+	id1 = "fe271e7e-83ca-477b-92fc-d0c3fa602d7d"
+	id2 = "2c9a1d25-8f0c-4cc5-93a3-1d8cb1b6e6a4"
+)
+
+// batchStorage is storage able to select several cats at once, recording every batch.
+type batchStorage struct {
+	*model.MockStorage
+	cats    map[string]model.Cat
+	batches [][]string
+}
+
+func (b *batchStorage) SelectMany(ids []string) ([]model.Cat, error) {
+	b.batches = append(b.batches, ids)
+	var cats []model.Cat
+	for _, id := range ids {
+		if cat, ok := b.cats[id]; ok {
+			cats = append(cats, cat)
+		}
+	}
+	return cats, nil
+}
+
+// execute runs a query, returning its result as json for comparison.
+func execute(t *testing.T, e *Executor, s model.Storage, req Request, allowMutations bool) string {
+	t.Helper()
+	// map keys are sorted for comparison
+	b, err := json.ConfigCompatibleWithStandardLibrary.Marshal(e.Execute(context.Background(), s, req, allowMutations))
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	return string(b)
+}
+
+func TestExecutor_BatchesCatsByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := &batchStorage{
+		MockStorage: model.NewMockStorage(ctrl),
+		cats: map[string]model.Cat{
+			id1: {ID: id1, Name: "cat-1", Color: "color-1", Age: 1},
+			id2: {ID: id2, Name: "cat-2", Color: "color-2", Age: 2},
+		},
+	}
+	e, err := New(nil, nil, Limits{})
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+
+	got := execute(t, e, s, Request{
+		Query:     `query($id: ID!) { a: cat(id: $id) { name } b: cat(id: "` + id2 + `") { name age } c: cat(id: "` + id1 + `") { id } }`,
+		Variables: map[string]interface{}{"id": id1},
+	}, false)
+	expected := `{"data":{"a":{"name":"cat-1"},"b":{"age":2,"name":"cat-2"},"c":{"id":"` + id1 + `"}}}`
+	if got != expected {
+		t.Errorf("unxpected result: got %s, expected %s", got, expected)
+	}
+	if len(s.batches) != 1 {
+		t.Fatalf("unxpected batches: got %v, expected a single batch", s.batches)
+	}
+	sort.Strings(s.batches[0])
+	if !reflect.DeepEqual(s.batches[0], []string{id2, id1}) {
+		t.Errorf("unxpected batch: got %v, expected %v", s.batches[0], []string{id2, id1})
+	}
+}
+
+func TestExecutor_Query(t *testing.T) {
+	page := []byte(`[{"id":"` + id1 + `","name":"tom","color":"grey","age":3},` +
+		`{"id":"` + id2 + `","name":"kitty","color":"black","age":9},` +
+		`{"id":"3","name":"felix","color":"black","age":5}]`)
+
+	tests := []struct {
+		description string
+		// given
+		query string
+		// then
+		expected string
+	}{
+		{
+			description: "cat without a batch selector",
+			query:       `{ cat(id: "` + id1 + `") { name color } }`,
+			expected:    `{"data":{"cat":{"color":"grey","name":"tom"}}}`,
+		},
+		{
+			description: "first page of cats",
+			query:       `{ cats(first: 2) { nodes { name } pageInfo { hasNextPage endCursor } } }`,
+			expected: `{"data":{"cats":{"nodes":[{"name":"tom"},{"name":"kitty"}],` +
+				`"pageInfo":{"endCursor":"b2Zmc2V0OjI=","hasNextPage":true}}}}`,
+		},
+		{
+			description: "last page of cats",
+			query:       `{ cats(first: 2, after: "b2Zmc2V0OjI=") { nodes { name } pageInfo { hasNextPage } } }`,
+			expected:    `{"data":{"cats":{"nodes":[{"name":"felix"}],"pageInfo":{"hasNextPage":false}}}}`,
+		},
+		{
+			description: "filtered cats",
+			query:       `{ cats(filter: {color: "BLACK", maxAge: 6}) { nodes { name age } } }`,
+			expected:    `{"data":{"cats":{"nodes":[{"age":5,"name":"felix"}]}}}`,
+		},
+		{
+			description: "invalid cursor",
+			query:       `{ cats(after: "nope") { nodes { name } } }`,
+			expected: `{"data":null,"errors":[{"message":"invalid cursor: nope","locations":[{"line":1,"column":3}],` +
+				`"path":["cats"],"extensions":{"code":"BAD_USER_INPUT"}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			s := model.NewMockStorage(ctrl)
+			s.EXPECT().SelectAll(100, 0).Return(page, nil).AnyTimes()
+			s.EXPECT().Select(id1).Return([]byte(`{"name":"tom","color":"grey","age":3}`), nil).AnyTimes()
+			e, err := New(nil, nil, Limits{})
+			if err != nil {
+				t.Fatalf("unxpected error: %v", err)
+			}
+
+			if got := execute(t, e, s, Request{Query: tt.query}, false); got != tt.expected {
+				t.Errorf("unxpected result: got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExecutor_Mutations(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockStorage(ctrl)
+	s.EXPECT().Insert([]byte(`{"name":"cat-1","color":"color-1","age":1}`)).Return(id1, nil)
+	s.EXPECT().Delete(id1).Return(nil)
+
+	var published []events.Type
+	e, err := New(func(ctx context.Context, t events.Type, id string, cat *model.Cat) { published = append(published, t) }, nil, Limits{})
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+
+	tests := []struct {
+		description    string
+		query          string
+		allowMutations bool
+		expected       string
+	}{
+		{
+			description:    "create cat",
+			query:          `mutation { createCat(input: {name: "cat-1", color: "color-1", age: 1}) { id name } }`,
+			allowMutations: true,
+			expected:       `{"data":{"createCat":{"id":"` + id1 + `","name":"cat-1"}}}`,
+		},
+		{
+			description:    "invalid cat",
+			query:          `mutation { createCat(input: {name: " ", color: "color-1", age: -1}) { id } }`,
+			allowMutations: true,
+			expected: `{"data":null,"errors":[{"message":"invalid cat: name is required, age must not be negative",` +
+				`"locations":[{"line":1,"column":12}],"path":["createCat"],"extensions":{"code":"BAD_USER_INPUT",` +
+				`"fields":[{"field":"name","reason":"is required"},{"field":"age","reason":"must not be negative"}]}}]}`,
+		},
+		{
+			description:    "delete cat",
+			query:          `mutation { deleteCat(id: "` + id1 + `") }`,
+			allowMutations: true,
+			expected:       `{"data":{"deleteCat":"` + id1 + `"}}`,
+		},
+		{
+			description: "mutation refused",
+			query:       `mutation { deleteCat(id: "` + id1 + `") }`,
+			expected: `{"data":null,"errors":[{"message":"mutations must be sent with POST","locations":[],` +
+				`"extensions":{"code":"METHOD_NOT_ALLOWED"}}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := execute(t, e, s, Request{Query: tt.query}, tt.allowMutations); got != tt.expected {
+				t.Errorf("unxpected result: got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+
+	if !reflect.DeepEqual(published, []events.Type{events.Created, events.Deleted}) {
+		t.Errorf("unxpected events: got %v, expected created and deleted", published)
+	}
+}
+
+func TestExecutor_Limits(t *testing.T) {
+	tests := []struct {
+		description string
+		limits      Limits
+		query       string
+		variables   map[string]interface{}
+		expected    string
+	}{
+		{
+			description: "too deep",
+			limits:      Limits{MaxDepth: 2},
+			query:       `{ cats { pageInfo { hasNextPage } nodes { ...names } } } fragment names on Cat { name }`,
+			expected: `{"data":null,"errors":[{"message":"query depth 3 exceeds the limit of 2","locations":[],` +
+				`"extensions":{"code":"QUERY_TOO_COMPLEX","depth":3}}]}`,
+		},
+		{
+			description: "too complex",
+			limits:      Limits{MaxComplexity: 25},
+			query:       `query($first: Int) { cats(first: $first) { nodes { id name } } }`,
+			variables:   map[string]interface{}{"first": float64(20)},
+			expected: `{"data":null,"errors":[{"message":"query complexity 61 exceeds the limit of 25","locations":[],` +
+				`"extensions":{"code":"QUERY_TOO_COMPLEX","complexity":61}}]}`,
+		},
+		{
+			description: "introspection is free",
+			limits:      Limits{MaxDepth: 1, MaxComplexity: 1},
+			query:       `{ __schema { queryType { fields { name type { ofType { name } } } } } }`,
+			expected: `{"data":{"__schema":{"queryType":{"fields":[{"name":"cat","type":{"ofType":null}},` +
+				`{"name":"cats","type":{"ofType":{"name":"CatConnection"}}}]}}}}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			e, err := New(nil, nil, tt.limits)
+			if err != nil {
+				t.Fatalf("unxpected error: %v", err)
+			}
+
+			if got := execute(t, e, model.NewMockStorage(ctrl), Request{Query: tt.query, Variables: tt.variables}, false); got != tt.expected {
+				t.Errorf("unxpected result: got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	DefaultMaxDepth      = 8
+	DefaultMaxComplexity = 500
+)
+
+// Limits bounds the cost of a query, values of 0 or less using the defaults.
+type Limits struct {
+	// MaxDepth is the deepest nesting of fields allowed.
+	MaxDepth int
+	// MaxComplexity is the highest complexity allowed, where every field costs 1 and the
+	// fields selected within a list cost once per item it may hold.
+	MaxComplexity int
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.MaxDepth <= 0 {
+		l.MaxDepth = DefaultMaxDepth
+	}
+	if l.MaxComplexity <= 0 {
+		l.MaxComplexity = DefaultMaxComplexity
+	}
+	return l
+}
+
+// listSizes holds the default number of items of list fields without a first argument.
+var listSizes = map[string]int{"cats": DefaultPageSize}
+
+// measure computes the depth and complexity of an operation. Introspection fields are
+// free, so that tools can always load the schema.
+type measure struct {
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	visiting  map[string]bool
+}
+
+func (m *measure) selectionSet(set *ast.SelectionSet) (depth, complexity int) {
+	if set == nil {
+		return 0, 0
+	}
+	for _, selection := range set.Selections {
+		var d, c int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name.Value, "__") {
+				continue
+			}
+			d, c = m.selectionSet(s.SelectionSet)
+			d++
+			c = 1 + c*m.listSize(s)
+		case *ast.InlineFragment:
+			d, c = m.selectionSet(s.SelectionSet)
+		case *ast.FragmentSpread:
+			fragment, ok := m.fragments[s.Name.Value]
+			if !ok || m.visiting[s.Name.Value] {
+				continue
+			}
+			m.visiting[s.Name.Value] = true
+			d, c = m.selectionSet(fragment.SelectionSet)
+			delete(m.visiting, s.Name.Value)
+		}
+		if d > depth {
+			depth = d
+		}
+		complexity += c
+	}
+	return depth, complexity
+}
+
+// listSize is the number of items a field may hold, from its first argument when given.
+func (m *measure) listSize(field *ast.Field) int {
+	size, ok := listSizes[field.Name.Value]
+	if !ok {
+		return 1
+	}
+	for _, arg := range field.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			if n, err := strconv.Atoi(v.Value); err == nil {
+				size = n
+			}
+		case *ast.Variable:
+			if n, ok := m.variables[v.Name.Value].(float64); ok {
+				size = int(n)
+			} else if n, ok := m.variables[v.Name.Value].(int); ok {
+				size = n
+			}
+		}
+	}
+	if size < 1 {
+		return 1
+	}
+	return size
+}
+
+// check rejects operations deeper or more complex than the limits allow.
+func (l Limits) check(doc *ast.Document, op *ast.OperationDefinition, variables map[string]interface{}) *Error {
+	m := &measure{
+		fragments: map[string]*ast.FragmentDefinition{},
+		variables: variables,
+		visiting:  map[string]bool{},
+	}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			m.fragments[f.Name.Value] = f
+		}
+	}
+
+	depth, complexity := m.selectionSet(op.SelectionSet)
+	if depth > l.MaxDepth {
+		e := newError(codeQueryTooComplex, fmt.Sprintf("query depth %d exceeds the limit of %d", depth, l.MaxDepth))
+		e.extensions["depth"] = depth
+		return e
+	}
+	if complexity > l.MaxComplexity {
+		e := newError(codeQueryTooComplex, fmt.Sprintf("query complexity %d exceeds the limit of %d", complexity, l.MaxComplexity))
+		e.extensions["complexity"] = complexity
+		return e
+	}
+	return nil
+}
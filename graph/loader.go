@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+
+	json "github.com/json-iterator/go"
+	"github.com/waikco/cats-v1/model"
+)
+
+type loaderKey struct{}
+
+// loader batches the cats requested by id while resolving a query, fetching every id
+// requested at the same depth of the query with a single storage call, and caches them
+// for the rest of the query.
+type loader struct {
+	storage model.Storage
+
+	mu      sync.Mutex
+	pending []string
+	entries map[string]*entry
+}
+
+type entry struct {
+	cat *model.Cat
+	err error
+}
+
+func newLoader(storage model.Storage) *loader {
+	return &loader{storage: storage, entries: map[string]*entry{}}
+}
+
+func loaderFrom(ctx context.Context) *loader {
+	l, _ := ctx.Value(loaderKey{}).(*loader)
+	return l
+}
+
+// load registers an id to be fetched, returning a thunk resolving to its cat, or nil when
+// there is none, once the executor has collected every other id at the same depth.
+func (l *loader) load(id string) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, ok := l.entries[id]; !ok {
+		l.entries[id] = nil
+		l.pending = append(l.pending, id)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.flush()
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		switch e := l.entries[id]; {
+		case e == nil || e.cat == nil && e.err == nil:
+			return nil, nil
+		case e.err != nil:
+			return nil, storageError(e.err)
+		default:
+			return e.cat, nil
+		}
+	}
+}
+
+// prime caches cats already fetched, such as those of a listed page, unless already requested.
+func (l *loader) prime(cats []model.Cat) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for i := range cats {
+		if _, ok := l.entries[cats[i].ID]; !ok {
+			l.entries[cats[i].ID] = &entry{cat: &cats[i]}
+		}
+	}
+}
+
+// flush fetches every pending id.
+func (l *loader) flush() {
+	l.mu.Lock()
+	ids := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+	if len(ids) == 0 {
+		return
+	}
+
+	found, err := l.fetch(ids)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range ids {
+		e := &entry{err: err}
+		if cat, ok := found[id]; ok {
+			e.cat = &cat
+		}
+		l.entries[id] = e
+	}
+}
+
+func (l *loader) fetch(ids []string) (map[string]model.Cat, error) {
+	found := map[string]model.Cat{}
+	if b, ok := l.storage.(model.BatchSelector); ok {
+		cats, err := b.SelectMany(ids)
+		for _, cat := range cats {
+			found[cat.ID] = cat
+		}
+		return found, err
+	}
+
+	for _, id := range ids {
+		body, err := l.storage.Select(id)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return found, err
+		}
+		var cat model.Cat
+		if err := json.Unmarshal(body, &cat); err != nil {
+			return found, err
+		}
+		cat.ID = id
+		found[id] = cat
+	}
+	return found, nil
+}
@@ -0,0 +1,413 @@
+package graph
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	json "github.com/json-iterator/go"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+	"github.com/waikco/cats-v1/events"
+	"github.com/waikco/cats-v1/model"
+	"github.com/waikco/cats-v1/reference"
+)
+
+const (
+	// DefaultPageSize is the number of cats listed when first is not given.
+	DefaultPageSize = 10
+	// MaxPageSize is the largest number of cats listed at once.
+	MaxPageSize = 100
+)
+
+// Publisher announces changes made by mutations.
+type Publisher func(ctx context.Context, t events.Type, id string, cat *model.Cat)
+
+var catType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Cat",
+	Fields: graphql.Fields{
+		"id":    &graphql.Field{Type: graphql.NewNonNull(graphql.ID)},
+		"name":  &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"color": &graphql.Field{Type: graphql.NewNonNull(graphql.String)},
+		"age":   &graphql.Field{Type: graphql.NewNonNull(graphql.Int)},
+		"breed": &graphql.Field{Type: graphql.String, Resolve: optional(func(c *model.Cat) string { return c.Breed })},
+		"birthDate": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Date the cat was born, such as 2019-04-01, from which its age is computed.",
+			Resolve:     optional(func(c *model.Cat) string { return c.BirthDate }),
+		},
+		"sex":      &graphql.Field{Type: graphql.String, Resolve: optional(func(c *model.Cat) string { return c.Sex })},
+		"neutered": &graphql.Field{Type: graphql.Boolean, Description: "Null when it is unknown."},
+		"weightKg": &graphql.Field{Type: graphql.Float},
+		"microchip": &graphql.Field{
+			Type:    graphql.String,
+			Resolve: optional(func(c *model.Cat) string { return c.Microchip }),
+		},
+		"tags": &graphql.Field{
+			Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String))),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				if cat := catOf(p.Source); cat != nil && cat.Tags != nil {
+					return cat.Tags, nil
+				}
+				return []string{}, nil
+			},
+		},
+		// ownerId is null for cats without an owner
+		"ownerId": &graphql.Field{Type: graphql.ID, Resolve: optional(func(c *model.Cat) string { return c.OwnerID })},
+		"status": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Where the cat is in its adoption, changed through transitions.",
+			Resolve:     optional(func(c *model.Cat) string { return c.Status }),
+		},
+	},
+})
+
+// catOf returns the cat a field is resolved on.
+func catOf(source interface{}) *model.Cat {
+	switch cat := source.(type) {
+	case model.Cat:
+		return &cat
+	case *model.Cat:
+		return cat
+	}
+	return nil
+}
+
+// optional resolves a string field of a cat, which is null when empty.
+func optional(field func(*model.Cat) string) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		if cat := catOf(p.Source); cat != nil && field(cat) != "" {
+			return field(cat), nil
+		}
+		return nil, nil
+	}
+}
+
+var pageInfoType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "PageInfo",
+	Fields: graphql.Fields{
+		"hasNextPage": &graphql.Field{Type: graphql.NewNonNull(graphql.Boolean)},
+		"endCursor": &graphql.Field{
+			Type:        graphql.String,
+			Description: "Cursor to pass as after to list the next page, null when the page is empty.",
+		},
+	},
+})
+
+var catConnectionType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "CatConnection",
+	Fields: graphql.Fields{
+		"nodes":    &graphql.Field{Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(catType)))},
+		"pageInfo": &graphql.Field{Type: graphql.NewNonNull(pageInfoType)},
+	},
+})
+
+var catFilterType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name:        "CatFilter",
+	Description: "Selects cats, names, colors and breeds being compared ignoring case.",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":      &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"color":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"minAge":    &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"maxAge":    &graphql.InputObjectFieldConfig{Type: graphql.Int},
+		"breed":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"sex":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"neutered":  &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+		"microchip": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"tags": &graphql.InputObjectFieldConfig{
+			Type:        graphql.NewList(graphql.NewNonNull(graphql.String)),
+			Description: "Selects cats with every one of the tags.",
+		},
+		"status": &graphql.InputObjectFieldConfig{Type: graphql.String},
+	},
+})
+
+var catInputType = graphql.NewInputObject(graphql.InputObjectConfig{
+	Name: "CatInput",
+	Fields: graphql.InputObjectConfigFieldMap{
+		"name":      &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"color":     &graphql.InputObjectFieldConfig{Type: graphql.NewNonNull(graphql.String)},
+		"age":       &graphql.InputObjectFieldConfig{Type: graphql.Int, DefaultValue: 0},
+		"breed":     &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"birthDate": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"sex":       &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"neutered":  &graphql.InputObjectFieldConfig{Type: graphql.Boolean},
+		"weightKg":  &graphql.InputObjectFieldConfig{Type: graphql.Float},
+		"microchip": &graphql.InputObjectFieldConfig{Type: graphql.String},
+		"tags":      &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.NewNonNull(graphql.String))},
+	},
+})
+
+// resolver resolves the root fields of the schema with the storage of the request's loader.
+type resolver struct {
+	publish Publisher
+	catalog *reference.Catalog
+}
+
+// newSchema creates the graphql schema of the cats model.
+func newSchema(publish Publisher, catalog *reference.Catalog) (graphql.Schema, error) {
+	r := &resolver{publish: publish, catalog: catalog}
+	return graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"cat": &graphql.Field{
+					Type:        catType,
+					Description: "A single cat, null when there is none with the id.",
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					},
+					Resolve: r.cat,
+				},
+				"cats": &graphql.Field{
+					Type:        graphql.NewNonNull(catConnectionType),
+					Description: "A page of the cats matching the filter, in id order.",
+					Args: graphql.FieldConfigArgument{
+						"filter": &graphql.ArgumentConfig{Type: catFilterType},
+						"first":  &graphql.ArgumentConfig{Type: graphql.Int, DefaultValue: DefaultPageSize},
+						"after":  &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: r.cats,
+				},
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"createCat": &graphql.Field{
+					Type: graphql.NewNonNull(catType),
+					Args: graphql.FieldConfigArgument{
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(catInputType)},
+					},
+					Resolve: r.createCat,
+				},
+				"updateCat": &graphql.Field{
+					Type: graphql.NewNonNull(catType),
+					Args: graphql.FieldConfigArgument{
+						"id":    &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+						"input": &graphql.ArgumentConfig{Type: graphql.NewNonNull(catInputType)},
+					},
+					Resolve: r.updateCat,
+				},
+				"deleteCat": &graphql.Field{
+					Type:        graphql.NewNonNull(graphql.ID),
+					Description: "Deletes a cat, returning its id.",
+					Args: graphql.FieldConfigArgument{
+						"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.ID)},
+					},
+					Resolve: r.deleteCat,
+				},
+			},
+		}),
+	})
+}
+
+func (r *resolver) cat(p graphql.ResolveParams) (interface{}, error) {
+	id, err := catID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	return loaderFrom(p.Context).load(id), nil
+}
+
+// errPageFull stops iterating once a page, and one more cat, have been collected.
+var errPageFull = errors.New("page full")
+
+func (r *resolver) cats(p graphql.ResolveParams) (interface{}, error) {
+	first, _ := p.Args["first"].(int)
+	if first < 0 || first > MaxPageSize {
+		return nil, newError(codeBadUserInput, fmt.Sprintf("first must be between 0 and %d", MaxPageSize))
+	}
+	offset, err := decodeCursor(p.Args["after"])
+	if err != nil {
+		return nil, err
+	}
+	filter := filterFrom(p.Args["filter"])
+
+	// one more cat than the page holds tells whether there is a next page
+	var page []model.Cat
+	skipped := 0
+	err = model.Each(p.Context, loaderFrom(p.Context).storage, filter, func(cat model.Cat) error {
+		if skipped < offset {
+			skipped++
+			return nil
+		}
+		page = append(page, cat)
+		if len(page) > first {
+			return errPageFull
+		}
+		return nil
+	})
+	if err != nil && err != errPageFull {
+		return nil, storageError(err)
+	}
+
+	info := map[string]interface{}{"hasNextPage": len(page) > first}
+	if len(page) > first {
+		page = page[:first]
+	}
+	if len(page) > 0 {
+		info["endCursor"] = encodeCursor(offset + len(page))
+	}
+	loaderFrom(p.Context).prime(page)
+	return map[string]interface{}{"nodes": page, "pageInfo": info}, nil
+}
+
+func (r *resolver) createCat(p graphql.ResolveParams) (interface{}, error) {
+	cat, err := r.catFrom(p.Args["input"])
+	if err != nil {
+		return nil, err
+	}
+	body, _ := json.Marshal(cat)
+	id, err := loaderFrom(p.Context).storage.Insert(body)
+	if err != nil {
+		return nil, storageError(err)
+	}
+	cat.ID = id
+	r.publish(p.Context, events.Created, id, &cat)
+	return cat, nil
+}
+
+func (r *resolver) updateCat(p graphql.ResolveParams) (interface{}, error) {
+	id, err := catID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	cat, err := r.catFrom(p.Args["input"])
+	if err != nil {
+		return nil, err
+	}
+	body, _ := json.Marshal(cat)
+	if err := loaderFrom(p.Context).storage.Update(id, body); err != nil {
+		return nil, storageError(err)
+	}
+	cat.ID = id
+	r.publish(p.Context, events.Updated, id, &cat)
+	return cat, nil
+}
+
+func (r *resolver) deleteCat(p graphql.ResolveParams) (interface{}, error) {
+	id, err := catID(p.Args["id"])
+	if err != nil {
+		return nil, err
+	}
+	if err := loaderFrom(p.Context).storage.Delete(id); err != nil {
+		return nil, storageError(err)
+	}
+	r.publish(p.Context, events.Deleted, id, nil)
+	return id, nil
+}
+
+func catID(arg interface{}) (string, error) {
+	id, _ := arg.(string)
+	if uuid.FromStringOrNil(id) == uuid.Nil {
+		return "", newError(codeBadUserInput, fmt.Sprintf("invalid cat id: %s", id))
+	}
+	return id, nil
+}
+
+func (r *resolver) catFrom(arg interface{}) (model.Cat, error) {
+	input, _ := arg.(map[string]interface{})
+	cat := model.Cat{}
+	cat.Name, _ = input["name"].(string)
+	cat.Color, _ = input["color"].(string)
+	cat.Age, _ = input["age"].(int)
+	cat.Breed, _ = input["breed"].(string)
+	cat.BirthDate, _ = input["birthDate"].(string)
+	cat.Sex, _ = input["sex"].(string)
+	if neutered, ok := input["neutered"].(bool); ok {
+		cat.Neutered = &neutered
+	}
+	cat.WeightKg, _ = input["weightKg"].(float64)
+	cat.Microchip, _ = input["microchip"].(string)
+	cat.Tags = stringList(input["tags"])
+	cat.Normalize(time.Now())
+	err := r.catalog.Normalize(&cat)
+	if err == nil {
+		err = cat.Validate()
+	}
+	if err != nil {
+		e := newError(codeBadUserInput, err.Error())
+		if fields, ok := err.(model.ValidationError); ok {
+			e.extensions["fields"] = fields
+		}
+		return cat, e
+	}
+	return cat, nil
+}
+
+func filterFrom(arg interface{}) model.Filter {
+	input, _ := arg.(map[string]interface{})
+	filter := model.Filter{}
+	filter.Name, _ = input["name"].(string)
+	filter.Color, _ = input["color"].(string)
+	if minAge, ok := input["minAge"].(int); ok {
+		filter.MinAge = &minAge
+	}
+	if maxAge, ok := input["maxAge"].(int); ok {
+		filter.MaxAge = &maxAge
+	}
+	filter.Breed, _ = input["breed"].(string)
+	filter.Sex, _ = input["sex"].(string)
+	if neutered, ok := input["neutered"].(bool); ok {
+		filter.Neutered = &neutered
+	}
+	filter.Microchip, _ = input["microchip"].(string)
+	filter.Tags = stringList(input["tags"])
+	filter.Status, _ = input["status"].(string)
+	return filter
+}
+
+// stringList returns the strings of a list argument.
+func stringList(arg interface{}) []string {
+	list, _ := arg.([]interface{})
+	var values []string
+	for _, v := range list {
+		if s, ok := v.(string); ok {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+const cursorPrefix = "offset:"
+
+func encodeCursor(offset int) string {
+	return base64.StdEncoding.EncodeToString([]byte(cursorPrefix + strconv.Itoa(offset)))
+}
+
+func decodeCursor(arg interface{}) (int, error) {
+	cursor, _ := arg.(string)
+	if cursor == "" {
+		return 0, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(cursor)
+	if err == nil && strings.HasPrefix(string(b), cursorPrefix) {
+		if offset, err := strconv.Atoi(strings.TrimPrefix(string(b), cursorPrefix)); err == nil && offset >= 0 {
+			return offset, nil
+		}
+	}
+	return 0, newError(codeBadUserInput, fmt.Sprintf("invalid cursor: %s", cursor))
+}
+
+// storageError hides the details of storage errors from clients, logging them instead.
+func storageError(err error) error {
+	if err == sql.ErrNoRows {
+		return newError(codeNotFound, "cat not found")
+	}
+	if err == model.ErrMicrochipExists {
+		return newError(codeConflict, err.Error())
+	}
+	var quota model.QuotaError
+	if errors.As(err, &quota) {
+		return newError(codeQuotaExceeded, quota.Error())
+	}
+	log.Error().Msgf("graphql storage error: %v", err)
+	return newError(codeInternal, "storage error")
+}
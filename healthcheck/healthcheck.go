@@ -0,0 +1,173 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp       = "up"
+	StatusDown     = "down"
+	StatusDegraded = "degraded"
+
+	DefaultTimeout  = 2 * time.Second
+	DefaultCacheTTL = 5 * time.Second
+)
+
+// ErrTimeout is reported for a check that did not complete within its timeout.
+var ErrTimeout = errors.New("check timed out")
+
+// CheckFunc reports the health of a single dependency, returning nil when healthy.
+type CheckFunc func(ctx context.Context) error
+
+// Check is a named dependency check. A failing critical check marks the whole
+// service as down, a failing non-critical check only marks it as degraded.
+type Check struct {
+	Name     string
+	Critical bool
+	Timeout  time.Duration
+	Func     CheckFunc
+}
+
+// Component is the result of running a single check.
+type Component struct {
+	Name      string  `json:"name" xml:"name"`
+	Status    string  `json:"status" xml:"status"`
+	Critical  bool    `json:"critical" xml:"critical"`
+	LatencyMs float64 `json:"latencyMs" xml:"latencyMs"`
+	Error     string  `json:"error,omitempty" xml:"error,omitempty"`
+}
+
+// Report is the aggregated result of running every registered check.
+type Report struct {
+	Status     string      `json:"status" xml:"status"`
+	CheckedAt  time.Time   `json:"checkedAt" xml:"checkedAt"`
+	Components []Component `json:"components" xml:"components"`
+}
+
+// Healthy reports whether every critical component is up.
+func (r Report) Healthy() bool {
+	return r.Status != StatusDown
+}
+
+// Registry holds dependency checks and caches their results.
+type Registry struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+
+	mu     sync.Mutex
+	checks []Check
+	last   *Report
+	now    func() time.Time
+}
+
+// NewRegistry creates a registry using the given default check timeout and result cache TTL,
+// falling back to DefaultTimeout and DefaultCacheTTL for zero values.
+func NewRegistry(timeout, cacheTTL time.Duration) *Registry {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultCacheTTL
+	}
+	return &Registry{
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+		now:      time.Now,
+	}
+}
+
+// Register adds a check, replacing any existing check with the same name.
+func (r *Registry) Register(c Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.checks {
+		if r.checks[i].Name == c.Name {
+			r.checks[i] = c
+			r.last = nil
+			return
+		}
+	}
+	r.checks = append(r.checks, c)
+	r.last = nil
+}
+
+// Run executes all checks concurrently, or returns the cached report if it is still fresh.
+func (r *Registry) Run(ctx context.Context) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.last != nil && r.now().Sub(r.last.CheckedAt) < r.cacheTTL {
+		return *r.last
+	}
+
+	components := make([]Component, len(r.checks))
+	var wg sync.WaitGroup
+	for i, c := range r.checks {
+		wg.Add(1)
+		go func(i int, c Check) {
+			defer wg.Done()
+			components[i] = r.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	sort.SliceStable(components, func(i, j int) bool {
+		return components[i].Name < components[j].Name
+	})
+
+	report := Report{
+		Status:     StatusUp,
+		CheckedAt:  r.now(),
+		Components: components,
+	}
+	for _, c := range components {
+		if c.Status == StatusUp {
+			continue
+		}
+		if c.Critical {
+			report.Status = StatusDown
+			break
+		}
+		report.Status = StatusDegraded
+	}
+
+	r.last = &report
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, c Check) Component {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = r.timeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- c.Func(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ErrTimeout
+	}
+
+	component := Component{
+		Name:      c.Name,
+		Status:    StatusUp,
+		Critical:  c.Critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		component.Status = StatusDown
+		component.Error = err.Error()
+	}
+	return component
+}
@@ -0,0 +1,102 @@
+package healthcheck
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRegistry_Run(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	slow := func(ctx context.Context) error {
+		time.Sleep(50 * time.Millisecond)
+		return nil
+	}
+
+	tests := []struct {
+		description string
+		// given
+		checks []Check
+		// then
+		expectedStatus  string
+		expectedHealthy bool
+		expectedErrors  map[string]string
+	}{
+		{
+			description:     "all checks pass",
+			checks:          []Check{{Name: "storage", Critical: true, Func: up}, {Name: "cache", Func: up}},
+			expectedStatus:  StatusUp,
+			expectedHealthy: true,
+			expectedErrors:  map[string]string{},
+		},
+		{
+			description:     "critical check fails",
+			checks:          []Check{{Name: "storage", Critical: true, Func: down}, {Name: "cache", Func: up}},
+			expectedStatus:  StatusDown,
+			expectedHealthy: false,
+			expectedErrors:  map[string]string{"storage": "connection refused"},
+		},
+		{
+			description:     "non-critical check fails",
+			checks:          []Check{{Name: "storage", Critical: true, Func: up}, {Name: "cache", Func: down}},
+			expectedStatus:  StatusDegraded,
+			expectedHealthy: true,
+			expectedErrors:  map[string]string{"cache": "connection refused"},
+		},
+		{
+			description:     "check times out",
+			checks:          []Check{{Name: "storage", Critical: true, Timeout: time.Millisecond, Func: slow}},
+			expectedStatus:  StatusDown,
+			expectedHealthy: false,
+			expectedErrors:  map[string]string{"storage": ErrTimeout.Error()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			r := NewRegistry(time.Second, 0)
+			for _, c := range tt.checks {
+				r.Register(c)
+			}
+
+			report := r.Run(context.Background())
+
+			if report.Status != tt.expectedStatus {
+				t.Errorf("unexpected status: got %s, expected %s", report.Status, tt.expectedStatus)
+			}
+			if report.Healthy() != tt.expectedHealthy {
+				t.Errorf("unexpected healthy: got %v, expected %v", report.Healthy(), tt.expectedHealthy)
+			}
+			if len(report.Components) != len(tt.checks) {
+				t.Fatalf("unexpected component count: got %d, expected %d", len(report.Components), len(tt.checks))
+			}
+			for _, c := range report.Components {
+				if c.Error != tt.expectedErrors[c.Name] {
+					t.Errorf("unexpected error for %s: got %q, expected %q", c.Name, c.Error, tt.expectedErrors[c.Name])
+				}
+			}
+		})
+	}
+}
+
+func TestRegistry_RunCachesResults(t *testing.T) {
+	calls := 0
+	r := NewRegistry(time.Second, time.Minute)
+	r.Register(Check{Name: "storage", Critical: true, Func: func(ctx context.Context) error {
+		calls++
+		return nil
+	}})
+
+	r.Run(context.Background())
+	r.Run(context.Background())
+	if calls != 1 {
+		t.Errorf("unexpected check calls within cache ttl: got %d, expected 1", calls)
+	}
+
+	r.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	r.Run(context.Background())
+	if calls != 2 {
+		t.Errorf("unexpected check calls after cache ttl: got %d, expected 2", calls)
+	}
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Statuses of cats through their adoption, cats starting at intake.
+const (
+	StatusIntake          = "intake"
+	StatusAvailable       = "available"
+	StatusOnHold          = "on-hold"
+	StatusPendingAdoption = "pending-adoption"
+	StatusAdopted         = "adopted"
+	StatusReturned        = "returned"
+)
+
+// MaxActorLength is the limit of the length of the actor of a transition.
+const MaxActorLength = 64
+
+// ErrStatusChanged is returned when transitioning a cat whose status was changed since it
+// was read.
+var ErrStatusChanged = errors.New("the status of the cat changed")
+
+// Statuses lists every status of cats, in the order cats usually go through them.
+var Statuses = []string{StatusIntake, StatusAvailable, StatusOnHold, StatusPendingAdoption, StatusAdopted, StatusReturned}
+
+// transitions lists the statuses cats may move to from each status.
+var transitions = map[string][]string{
+	StatusIntake:          {StatusAvailable, StatusOnHold},
+	StatusAvailable:       {StatusOnHold, StatusPendingAdoption},
+	StatusOnHold:          {StatusAvailable},
+	StatusPendingAdoption: {StatusAvailable, StatusAdopted},
+	StatusAdopted:         {StatusReturned},
+	StatusReturned:        {StatusIntake, StatusAvailable},
+}
+
+// NextStatuses returns the statuses a cat may move to from a status.
+func NextStatuses(status string) []string {
+	return transitions[status]
+}
+
+// ValidStatus reports whether status is one of Statuses.
+func ValidStatus(status string) bool {
+	_, ok := transitions[status]
+	return ok
+}
+
+// Transition is a move of a cat from one status to another, recorded along with whoever
+// made it.
+type Transition struct {
+	ID    string `json:"id,omitempty" xml:"id,omitempty"`
+	CatID string `json:"-" xml:"-"`
+	// From is the status of the cat before the transition, ignored when transitioning a cat.
+	From      string    `json:"from,omitempty" xml:"from,omitempty"`
+	To        string    `json:"to" xml:"to"`
+	Actor     string    `json:"actor" xml:"actor"`
+	Reason    string    `json:"reason,omitempty" xml:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt" xml:"createdAt"`
+}
+
+// TransitionValidationError lists every invalid field of a transition.
+type TransitionValidationError struct {
+	ValidationError
+}
+
+func (v TransitionValidationError) Error() string {
+	return "invalid transition: " + v.reasons()
+}
+
+// Unwrap returns the invalid fields.
+func (v TransitionValidationError) Unwrap() error {
+	return v.ValidationError
+}
+
+// Validate checks that the transition names a status and an actor, returning a
+// TransitionValidationError listing every invalid field.
+func (t Transition) Validate() error {
+	var errs ValidationError
+	if !ValidStatus(t.To) {
+		errs = append(errs, FieldError{Field: "to", Reason: "must be one of " + strings.Join(Statuses, ", ")})
+	}
+	if strings.TrimSpace(t.Actor) == "" {
+		errs = append(errs, FieldError{Field: "actor", Reason: "is required"})
+	} else if len(t.Actor) > MaxActorLength {
+		errs = append(errs, FieldError{Field: "actor", Reason: fmt.Sprintf("must be at most %d characters", MaxActorLength)})
+	}
+	if len(errs) > 0 {
+		return TransitionValidationError{errs}
+	}
+	return nil
+}
+
+// TransitionError is returned when a cat may not move to a status, either because the
+// status does not follow the current one or because a guard of the status rejects the cat.
+type TransitionError struct {
+	From   string
+	To     string
+	Reason string
+}
+
+func (e TransitionError) Error() string {
+	return fmt.Sprintf("cannot move cat from %s to %s: %s", e.From, e.To, e.Reason)
+}
+
+// CanTransition checks that the cat may move to a status, returning a TransitionError when
+// it may not. Cats without a status are at intake, and are only adopted, or pending
+// adoption, once assigned an owner.
+func (c Cat) CanTransition(to string) error {
+	from := c.Status
+	if from == "" {
+		from = StatusIntake
+	}
+	next := NextStatuses(from)
+	allowed := false
+	for _, s := range next {
+		if s == to {
+			allowed = true
+			break
+		}
+	}
+	switch {
+	case !allowed:
+		return TransitionError{From: from, To: to, Reason: "expected one of " + strings.Join(next, ", ")}
+	case RequiresOwner(to) && c.OwnerID == "":
+		return TransitionError{From: from, To: to, Reason: "the cat must be assigned an owner"}
+	}
+	return nil
+}
+
+// RequiresOwner reports whether cats at a status must be assigned an owner, which is the
+// case from the time they are pending adoption.
+func RequiresOwner(status string) bool {
+	return status == StatusPendingAdoption || status == StatusAdopted
+}
+
+// TransitionStore is implemented by storage backends able to track the status of cats.
+// Methods return sql.ErrNoRows for unknown cats.
+type TransitionStore interface {
+	// TransitionCat moves a cat from the From to the To status of the transition and
+	// records it, returning the recorded transition, ErrStatusChanged when the cat is no
+	// longer at the From status, or a TransitionError when the cat no longer has the owner
+	// the To status requires.
+	TransitionCat(Transition) (Transition, error)
+	// SelectTransitions selects every transition of a cat, oldest first.
+	SelectTransitions(catID string) ([]Transition, error)
+}
@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: model/adoption.go
+
+// Package model is a generated GoMock package.
+package model
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockTransitionStore is a mock of TransitionStore interface
+type MockTransitionStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTransitionStoreMockRecorder
+}
+
+// MockTransitionStoreMockRecorder is the mock recorder for MockTransitionStore
+type MockTransitionStoreMockRecorder struct {
+	mock *MockTransitionStore
+}
+
+// NewMockTransitionStore creates a new mock instance
+func NewMockTransitionStore(ctrl *gomock.Controller) *MockTransitionStore {
+	mock := &MockTransitionStore{ctrl: ctrl}
+	mock.recorder = &MockTransitionStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTransitionStore) EXPECT() *MockTransitionStoreMockRecorder {
+	return m.recorder
+}
+
+// TransitionCat mocks base method
+func (m *MockTransitionStore) TransitionCat(arg0 Transition) (Transition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransitionCat", arg0)
+	ret0, _ := ret[0].(Transition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// TransitionCat indicates an expected call of TransitionCat
+func (mr *MockTransitionStoreMockRecorder) TransitionCat(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransitionCat", reflect.TypeOf((*MockTransitionStore)(nil).TransitionCat), arg0)
+}
+
+// SelectTransitions mocks base method
+func (m *MockTransitionStore) SelectTransitions(catID string) ([]Transition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectTransitions", catID)
+	ret0, _ := ret[0].([]Transition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectTransitions indicates an expected call of SelectTransitions
+func (mr *MockTransitionStoreMockRecorder) SelectTransitions(catID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectTransitions", reflect.TypeOf((*MockTransitionStore)(nil).SelectTransitions), catID)
+}
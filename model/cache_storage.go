@@ -2,6 +2,8 @@ package model
 
 import (
 	"bytes"
+	"fmt"
+	"time"
 
 	json "github.com/json-iterator/go"
 
@@ -10,13 +12,27 @@ import (
 	uuid "github.com/satori/go.uuid"
 )
 
+const (
+	defaultCacheSize = 100 * 1024 * 1024
+	defaultCacheTTL  = 300 * time.Second
+)
+
+// pingKey is the key of the entry cached to check the cache is working.
+var pingKey = []byte("cats-v1:ping")
+
 type Cache struct {
 	cache *freecache.Cache
+	ttl   time.Duration
+}
+
+// NewCache creates a cache of size bytes, evicting entries ttl after they are set.
+func NewCache(size int, ttl time.Duration) *Cache {
+	return &Cache{cache: freecache.NewCache(size), ttl: ttl}
 }
 
 func (c *Cache) Initialize() error {
-	cacheSize := 100 * 1024 * 1024
-	c.cache = freecache.NewCache(cacheSize)
+	c.cache = freecache.NewCache(defaultCacheSize)
+	c.ttl = defaultCacheTTL
 	return nil
 }
 
@@ -54,7 +70,7 @@ func (c *Cache) Create(i interface{}) (string, error) {
 	} else {
 		id := uuid.NewV4().String()
 		log.Debug().Msgf("saving %s to cache", id)
-		return id, c.cache.Set([]byte(id), data, 300)
+		return id, c.Set(id, data)
 	}
 }
 
@@ -63,10 +79,33 @@ func (c *Cache) Update(s string, i interface{}) (string, error) {
 		return "", err
 	} else {
 		log.Debug().Msgf("updating %s in cache", s)
-		return s, c.cache.Set([]byte(s), data, 300)
+		return s, c.Set(s, data)
 	}
 }
 
+// Set caches data under s until the ttl of the cache has passed.
+func (c *Cache) Set(s string, data []byte) error {
+	return c.cache.Set([]byte(s), data, int(c.ttl/time.Second))
+}
+
 func (c *Cache) Delete(s string) error {
-	panic("implement me")
+	log.Debug().Msgf("deleting %s from cache", s)
+	c.cache.Del([]byte(s))
+	return nil
+}
+
+// Ping checks that entries are able to be cached and read back.
+func (c *Cache) Ping() error {
+	if err := c.cache.Set(pingKey, pingKey, 1); err != nil {
+		return err
+	}
+	if _, err := c.cache.Get(pingKey); err != nil {
+		return fmt.Errorf("unable to read back cached entry: %v", err)
+	}
+	return nil
+}
+
+// Clear deletes every entry.
+func (c *Cache) Clear() {
+	c.cache.Clear()
 }
@@ -0,0 +1,161 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	json "github.com/json-iterator/go"
+	"github.com/lib/pq"
+)
+
+// Filter selects a subset of cats. Empty fields match every cat, and names, colors and
+// breeds are compared case insensitively.
+type Filter struct {
+	Name      string
+	Color     string
+	MinAge    *int
+	MaxAge    *int
+	Breed     string
+	Sex       string
+	Neutered  *bool
+	Microchip string
+	// Tags selects cats with every one of the tags.
+	Tags   []string
+	Status string
+}
+
+// Matches reports whether the cat is selected by the filter.
+func (f Filter) Matches(c Cat) bool {
+	switch {
+	case f.Name != "" && !strings.EqualFold(f.Name, c.Name):
+		return false
+	case f.Color != "" && !strings.EqualFold(f.Color, c.Color):
+		return false
+	case f.MinAge != nil && c.Age < *f.MinAge:
+		return false
+	case f.MaxAge != nil && c.Age > *f.MaxAge:
+		return false
+	case f.Breed != "" && !strings.EqualFold(f.Breed, c.Breed):
+		return false
+	case f.Sex != "" && !strings.EqualFold(f.Sex, c.Sex):
+		return false
+	case f.Neutered != nil && (c.Neutered == nil || *c.Neutered != *f.Neutered):
+		return false
+	case f.Microchip != "" && !strings.EqualFold(f.Microchip, c.Microchip):
+		return false
+	case f.Status != "" && f.Status != c.Status:
+		return false
+	}
+	for _, tag := range f.Tags {
+		if !hasTag(c.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// Empty reports whether the filter matches every cat.
+func (f Filter) Empty() bool {
+	return f.Name == "" && f.Color == "" && f.MinAge == nil && f.MaxAge == nil && f.Breed == "" &&
+		f.Sex == "" && f.Neutered == nil && f.Microchip == "" && len(f.Tags) == 0 && f.Status == ""
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// where returns a sql WHERE clause applying the filter, along with its arguments.
+func (f Filter) where() (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if f.Name != "" {
+		add("lower(name) = lower($%d)", f.Name)
+	}
+	if f.Color != "" {
+		add("lower(color) = lower($%d)", f.Color)
+	}
+	if f.MinAge != nil {
+		add(ageColumn+" >= $%d", *f.MinAge)
+	}
+	if f.MaxAge != nil {
+		add(ageColumn+" <= $%d", *f.MaxAge)
+	}
+	if f.Breed != "" {
+		add("lower(breed) = lower($%d)", f.Breed)
+	}
+	if f.Sex != "" {
+		add("sex = lower($%d)", f.Sex)
+	}
+	if f.Neutered != nil {
+		add("neutered = $%d", *f.Neutered)
+	}
+	if f.Microchip != "" {
+		add("microchip = upper($%d)", f.Microchip)
+	}
+	if len(f.Tags) > 0 {
+		tags := make([]string, len(f.Tags))
+		for i, tag := range f.Tags {
+			tags[i] = strings.ToLower(tag)
+		}
+		add("tags @> $%d", pq.Array(tags))
+	}
+	if f.Status != "" {
+		add("status = $%d", f.Status)
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// eachPageSize is the page size used with storage unable to stream.
+const eachPageSize = 100
+
+// Each calls fn for every cat selected by the filter, streaming them from storage when it
+// is a Streamer and otherwise paging through them, so they are never all held in memory.
+func Each(ctx context.Context, s Storage, f Filter, fn func(Cat) error) error {
+	if streamer, ok := s.(Streamer); ok {
+		return streamer.Stream(ctx, f, fn)
+	}
+
+	for start := 0; ; start += eachPageSize {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b, err := s.SelectAll(eachPageSize, start)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		var cats []Cat
+		if err := json.Unmarshal(b, &cats); err != nil {
+			return err
+		}
+		for _, cat := range cats {
+			if !f.Matches(cat) {
+				continue
+			}
+			if err := fn(cat); err != nil {
+				return err
+			}
+		}
+		if len(cats) < eachPageSize {
+			return nil
+		}
+	}
+}
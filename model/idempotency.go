@@ -0,0 +1,82 @@
+package model
+
+import (
+	"sync"
+	"time"
+)
+
+// IdempotencyRecord is a request made with an idempotency key, along with its response once
+// the request has completed.
+type IdempotencyRecord struct {
+	Key string
+	// Fingerprint identifies the request, so a key reused for another request is told apart.
+	Fingerprint string
+	Completed   bool
+	Status      int
+	ContentType string
+	Body        []byte
+	ExpiresAt   time.Time
+}
+
+// IdempotencyStore keeps idempotency keys along with the responses to the requests made
+// with them, until they expire.
+type IdempotencyStore interface {
+	// ReserveIdempotencyKey reserves an unknown or expired key for a request until expiresAt,
+	// returning true. Otherwise it returns the record of the key as is, and false.
+	ReserveIdempotencyKey(key string, fingerprint string, now time.Time, expiresAt time.Time) (IdempotencyRecord, bool, error)
+	// CompleteIdempotencyKey stores the response of the request a key was reserved for.
+	CompleteIdempotencyKey(record IdempotencyRecord) error
+	// ReleaseIdempotencyKey forgets a key, so the request may be made again.
+	ReleaseIdempotencyKey(key string) error
+	// DeleteExpiredIdempotencyKeys deletes the keys expired by now.
+	DeleteExpiredIdempotencyKeys(now time.Time) error
+}
+
+// IdempotencyMemory keeps idempotency keys in memory, for storage backends unable to
+// persist them. Keys are only known to the instance they were used with.
+type IdempotencyMemory struct {
+	mu      sync.Mutex
+	records map[string]IdempotencyRecord
+}
+
+// NewIdempotencyMemory creates an empty in-memory store of idempotency keys.
+func NewIdempotencyMemory() *IdempotencyMemory {
+	return &IdempotencyMemory{records: map[string]IdempotencyRecord{}}
+}
+
+func (m *IdempotencyMemory) ReserveIdempotencyKey(key string, fingerprint string, now time.Time, expiresAt time.Time) (IdempotencyRecord, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if record, ok := m.records[key]; ok && record.ExpiresAt.After(now) {
+		return record, false, nil
+	}
+	record := IdempotencyRecord{Key: key, Fingerprint: fingerprint, ExpiresAt: expiresAt}
+	m.records[key] = record
+	return record, true, nil
+}
+
+func (m *IdempotencyMemory) CompleteIdempotencyKey(record IdempotencyRecord) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record.Completed = true
+	m.records[record.Key] = record
+	return nil
+}
+
+func (m *IdempotencyMemory) ReleaseIdempotencyKey(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.records, key)
+	return nil
+}
+
+func (m *IdempotencyMemory) DeleteExpiredIdempotencyKeys(now time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for key, record := range m.records {
+		if !record.ExpiresAt.After(now) {
+			delete(m.records, key)
+		}
+	}
+	return nil
+}
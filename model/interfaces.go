@@ -1,5 +1,7 @@
 package model
 
+import "context"
+
 //Storage
 type Storage interface {
 	Status() error
@@ -10,3 +12,33 @@ type Storage interface {
 	Delete(string) error
 	Purge(string) error
 }
+
+// StatusContexter is implemented by storage backends able to give up checking their status
+// once ctx is done.
+type StatusContexter interface {
+	StatusContext(ctx context.Context) error
+}
+
+// Migrator is implemented by storage backends with a versioned schema.
+type Migrator interface {
+	Migrate() error
+	PendingMigrations() ([]int, error)
+}
+
+// Streamer is implemented by storage backends able to stream every cat selected
+// by a filter without holding them all in memory.
+type Streamer interface {
+	Stream(ctx context.Context, f Filter, fn func(Cat) error) error
+}
+
+// BatchInserter is implemented by storage backends able to insert several cats
+// atomically, returning their ids in order.
+type BatchInserter interface {
+	InsertBatch([]Cat) ([]string, error)
+}
+
+// BatchSelector is implemented by storage backends able to select several cats by id
+// in one query. Ids without a cat are left out of the result.
+type BatchSelector interface {
+	SelectMany(ids []string) ([]Cat, error)
+}
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Kinds of medical records.
+const (
+	KindVaccination = "vaccination"
+	KindTreatment   = "treatment"
+	KindWeighIn     = "weigh-in"
+)
+
+// MedicalRecord is a vaccination, treatment or weigh-in of a cat. Vaccinations and
+// treatments name what was given and may be due again, weigh-ins record a weight.
+type MedicalRecord struct {
+	ID    string `json:"id,omitempty" xml:"id,omitempty"`
+	CatID string `json:"-" xml:"-"`
+	Kind  string `json:"kind" xml:"kind"`
+	// Name is the vaccine or treatment given.
+	Name string `json:"name,omitempty" xml:"name,omitempty"`
+	// Date is the day the record was made, as a DateLayout date.
+	Date string `json:"date" xml:"date"`
+	// DueDate is the day the next dose is due, as a DateLayout date.
+	DueDate   string    `json:"dueDate,omitempty" xml:"dueDate,omitempty"`
+	WeightKg  float64   `json:"weightKg,omitempty" xml:"weightKg,omitempty"`
+	Notes     string    `json:"notes,omitempty" xml:"notes,omitempty"`
+	CreatedAt time.Time `json:"createdAt" xml:"createdAt"`
+}
+
+// RecordValidationError lists every invalid field of a medical record.
+type RecordValidationError struct {
+	ValidationError
+}
+
+func (v RecordValidationError) Error() string {
+	return "invalid medical record: " + v.reasons()
+}
+
+// Unwrap returns the invalid fields.
+func (v RecordValidationError) Unwrap() error {
+	return v.ValidationError
+}
+
+// Validate checks that the record can be stored, returning a RecordValidationError listing
+// every invalid field.
+func (m MedicalRecord) Validate() error {
+	var errs ValidationError
+	switch m.Kind {
+	case KindVaccination, KindTreatment:
+		if strings.TrimSpace(m.Name) == "" {
+			errs = append(errs, FieldError{Field: "name", Reason: "is required"})
+		}
+		if m.WeightKg != 0 {
+			errs = append(errs, FieldError{Field: "weightKg", Reason: "is only recorded by weigh-ins"})
+		}
+	case KindWeighIn:
+		if m.WeightKg <= 0 || m.WeightKg > MaxWeightKg {
+			errs = append(errs, FieldError{Field: "weightKg", Reason: fmt.Sprintf("must be more than 0 and at most %d", MaxWeightKg)})
+		}
+		if m.DueDate != "" {
+			errs = append(errs, FieldError{Field: "dueDate", Reason: "is only recorded by vaccinations and treatments"})
+		}
+	default:
+		errs = append(errs, FieldError{Field: "kind", Reason: "must be vaccination, treatment or weigh-in"})
+	}
+	date, err := time.Parse(DateLayout, m.Date)
+	if err != nil {
+		errs = append(errs, FieldError{Field: "date", Reason: "must be a date such as 2019-04-01"})
+	} else if date.After(time.Now()) {
+		errs = append(errs, FieldError{Field: "date", Reason: "must not be in the future"})
+	}
+	if m.DueDate != "" {
+		if due, derr := time.Parse(DateLayout, m.DueDate); derr != nil {
+			errs = append(errs, FieldError{Field: "dueDate", Reason: "must be a date such as 2019-04-01"})
+		} else if err == nil && !due.After(date) {
+			errs = append(errs, FieldError{Field: "dueDate", Reason: "must be after the date"})
+		}
+	}
+	if len(errs) > 0 {
+		return RecordValidationError{errs}
+	}
+	return nil
+}
+
+// DueVaccination is the next dose of a vaccine due for a cat, from the latest vaccination
+// of the cat with the vaccine.
+type DueVaccination struct {
+	CatID   string `json:"catId" xml:"catId"`
+	CatName string `json:"catName" xml:"catName"`
+	Vaccine string `json:"vaccine" xml:"vaccine"`
+	// LastDate is the day the cat was last given the vaccine.
+	LastDate string `json:"lastDate" xml:"lastDate"`
+	DueDate  string `json:"dueDate" xml:"dueDate"`
+	Overdue  bool   `json:"overdue" xml:"overdue"`
+}
+
+// MedicalStore is implemented by storage backends able to persist the medical records of
+// cats. Methods return sql.ErrNoRows for unknown cats or records.
+type MedicalStore interface {
+	// InsertMedicalRecord stores a record of a cat, returning its id.
+	InsertMedicalRecord(MedicalRecord) (string, error)
+	SelectMedicalRecord(catID, id string) (MedicalRecord, error)
+	// SelectMedicalRecords selects the records of a cat of the given kind, or of every kind
+	// when it is empty, latest first.
+	SelectMedicalRecords(catID, kind string) ([]MedicalRecord, error)
+	DeleteMedicalRecord(catID, id string) error
+	// SelectDueVaccinations selects the next doses due on or before until, soonest first,
+	// including those overdue.
+	SelectDueVaccinations(until time.Time) ([]DueVaccination, error)
+}
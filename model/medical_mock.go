@@ -0,0 +1,108 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: model/medical.go
+
+// Package model is a generated GoMock package.
+package model
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockMedicalStore is a mock of MedicalStore interface
+type MockMedicalStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockMedicalStoreMockRecorder
+}
+
+// MockMedicalStoreMockRecorder is the mock recorder for MockMedicalStore
+type MockMedicalStoreMockRecorder struct {
+	mock *MockMedicalStore
+}
+
+// NewMockMedicalStore creates a new mock instance
+func NewMockMedicalStore(ctrl *gomock.Controller) *MockMedicalStore {
+	mock := &MockMedicalStore{ctrl: ctrl}
+	mock.recorder = &MockMedicalStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockMedicalStore) EXPECT() *MockMedicalStoreMockRecorder {
+	return m.recorder
+}
+
+// InsertMedicalRecord mocks base method
+func (m *MockMedicalStore) InsertMedicalRecord(arg0 MedicalRecord) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertMedicalRecord", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertMedicalRecord indicates an expected call of InsertMedicalRecord
+func (mr *MockMedicalStoreMockRecorder) InsertMedicalRecord(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertMedicalRecord", reflect.TypeOf((*MockMedicalStore)(nil).InsertMedicalRecord), arg0)
+}
+
+// SelectMedicalRecord mocks base method
+func (m *MockMedicalStore) SelectMedicalRecord(catID, id string) (MedicalRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectMedicalRecord", catID, id)
+	ret0, _ := ret[0].(MedicalRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectMedicalRecord indicates an expected call of SelectMedicalRecord
+func (mr *MockMedicalStoreMockRecorder) SelectMedicalRecord(catID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectMedicalRecord", reflect.TypeOf((*MockMedicalStore)(nil).SelectMedicalRecord), catID, id)
+}
+
+// SelectMedicalRecords mocks base method
+func (m *MockMedicalStore) SelectMedicalRecords(catID, kind string) ([]MedicalRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectMedicalRecords", catID, kind)
+	ret0, _ := ret[0].([]MedicalRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectMedicalRecords indicates an expected call of SelectMedicalRecords
+func (mr *MockMedicalStoreMockRecorder) SelectMedicalRecords(catID, kind interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectMedicalRecords", reflect.TypeOf((*MockMedicalStore)(nil).SelectMedicalRecords), catID, kind)
+}
+
+// DeleteMedicalRecord mocks base method
+func (m *MockMedicalStore) DeleteMedicalRecord(catID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMedicalRecord", catID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMedicalRecord indicates an expected call of DeleteMedicalRecord
+func (mr *MockMedicalStoreMockRecorder) DeleteMedicalRecord(catID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMedicalRecord", reflect.TypeOf((*MockMedicalStore)(nil).DeleteMedicalRecord), catID, id)
+}
+
+// SelectDueVaccinations mocks base method
+func (m *MockMedicalStore) SelectDueVaccinations(until time.Time) ([]DueVaccination, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDueVaccinations", until)
+	ret0, _ := ret[0].([]DueVaccination)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectDueVaccinations indicates an expected call of SelectDueVaccinations
+func (mr *MockMedicalStoreMockRecorder) SelectDueVaccinations(until interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDueVaccinations", reflect.TypeOf((*MockMedicalStore)(nil).SelectDueVaccinations), until)
+}
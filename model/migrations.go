@@ -0,0 +1,98 @@
+package model
+
+import (
+	"github.com/rs/zerolog/log"
+)
+
+// migration is a single, ordered schema change applied by PostGres.Migrate.
+type migration struct {
+	version     int
+	description string
+	query       string
+}
+
+// migrations lists every schema change in the order they must be applied.
+// New entries must only ever be appended.
+var migrations = []migration{
+	{version: 1, description: "create cats table", query: CreateTableQuery},
+	{version: 2, description: "create webhooks and webhook deliveries tables", query: createWebhooksQuery},
+	{version: 3, description: "notify changes to cats", query: createNotifyChangesQuery},
+	{version: 4, description: "create outbox table", query: createOutboxQuery},
+	{version: 5, description: "create idempotency keys table", query: createIdempotencyKeysQuery},
+	{version: 6, description: "scope cats to tenants", query: createTenantsQuery},
+	{version: 7, description: "create owners table", query: createOwnersQuery},
+	{version: 8, description: "create photos table", query: createPhotosQuery},
+	{version: 9, description: "describe cats in more detail", query: createCatDetailsQuery},
+	{version: 10, description: "create breeds and colors reference tables", query: createReferenceQuery},
+	{version: 11, description: "create medical records table", query: createMedicalRecordsQuery},
+	{version: 12, description: "track the adoption status of cats", query: createTransitionsQuery},
+	{version: 13, description: "scope webhooks to tenants", query: createWebhookTenantsQuery},
+}
+
+const createMigrationsTableQuery string = `
+CREATE TABLE IF NOT EXISTS schema_migrations (
+version INT PRIMARY KEY,
+description TEXT NOT NULL,
+applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);`
+
+// Migrate applies every migration that has not yet been recorded in schema_migrations.
+func (p *PostGres) Migrate() error {
+	if _, err := p.database.Exec(createMigrationsTableQuery); err != nil {
+		return err
+	}
+
+	pending, err := p.PendingMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		if !containsVersion(pending, m.version) {
+			continue
+		}
+		tx, err := p.database.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(m.query); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version, description) VALUES ($1, $2)`,
+			m.version, m.description); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+		log.Info().Msgf("applied migration %d: %s", m.version, m.description)
+	}
+	return nil
+}
+
+// PendingMigrations returns the versions of all migrations not yet applied.
+func (p *PostGres) PendingMigrations() ([]int, error) {
+	var applied []int
+	if err := p.database.Select(&applied, `SELECT version FROM schema_migrations`); err != nil {
+		return nil, err
+	}
+
+	var pending []int
+	for _, m := range migrations {
+		if !containsVersion(applied, m.version) {
+			pending = append(pending, m.version)
+		}
+	}
+	return pending, nil
+}
+
+func containsVersion(versions []int, version int) bool {
+	for _, v := range versions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
@@ -1,10 +1,81 @@
 package model
 
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Sexes of cats, which is unknown when empty.
+const (
+	SexFemale = "female"
+	SexMale   = "male"
+)
+
+// ErrMicrochipExists is returned when storing a cat with the microchip of another cat.
+var ErrMicrochipExists = errors.New("another cat has the microchip")
+
+// DateLayout is the layout of dates, such as birth dates.
+const DateLayout = "2006-01-02"
+
 type Cat struct {
-	ID    string `json:"id,omitempty"`
-	Name  string `json:"name,omitempty"`
-	Color string `json:"color,omitempty"`
-	Age   int    `json:"age,omitempty"`
+	ID    string `json:"id,omitempty" xml:"id,omitempty" yaml:"id,omitempty"`
+	Name  string `json:"name,omitempty" xml:"name,omitempty" yaml:"name,omitempty"`
+	Color string `json:"color,omitempty" xml:"color,omitempty" yaml:"color,omitempty"`
+	// Age is computed from the birth date of cats with one, and only stored as given for
+	// cats without.
+	Age   int    `json:"age,omitempty" xml:"age,omitempty" yaml:"age,omitempty"`
+	Breed string `json:"breed,omitempty" xml:"breed,omitempty" yaml:"breed,omitempty"`
+	// BirthDate is the date the cat was born, in DateLayout.
+	BirthDate string `json:"birthDate,omitempty" xml:"birthDate,omitempty" yaml:"birthDate,omitempty"`
+	Sex       string `json:"sex,omitempty" xml:"sex,omitempty" yaml:"sex,omitempty"`
+	// Neutered is nil when it is unknown whether the cat is neutered.
+	Neutered *bool   `json:"neutered,omitempty" xml:"neutered,omitempty" yaml:"neutered,omitempty"`
+	WeightKg float64 `json:"weightKg,omitempty" xml:"weightKg,omitempty" yaml:"weightKg,omitempty"`
+	// Microchip is the id of the microchip of the cat, unique to the tenant.
+	Microchip string   `json:"microchip,omitempty" xml:"microchip,omitempty" yaml:"microchip,omitempty"`
+	Tags      []string `json:"tags,omitempty" xml:"tag,omitempty" yaml:"tags,omitempty"`
+	// OwnerID is the id of the owner the cat is assigned to, if any, and is only changed
+	// through the owner.
+	OwnerID string `json:"ownerId,omitempty" xml:"ownerId,omitempty" yaml:"ownerId,omitempty"`
+	// Status is where the cat is in its adoption, one of Statuses, and is only changed
+	// through transitions.
+	Status string `json:"status,omitempty" xml:"status,omitempty" yaml:"status,omitempty"`
+	// Photos lists the photos of the cat, which are only changed through its photos.
+	Photos []Photo `json:"photos,omitempty" xml:"photo,omitempty" yaml:"photos,omitempty"`
+}
+
+// AgeOn returns the age in whole years of a cat born on birthDate, as of now.
+func AgeOn(birthDate time.Time, now time.Time) int {
+	age := now.Year() - birthDate.Year()
+	if now.Month() < birthDate.Month() || now.Month() == birthDate.Month() && now.Day() < birthDate.Day() {
+		age--
+	}
+	if age < 0 {
+		return 0
+	}
+	return age
+}
+
+// Normalize puts a cat in the form it is stored in: tags are trimmed, lowercased and
+// deduplicated, microchips uppercased, and the age computed from any valid birth date.
+func (c *Cat) Normalize(now time.Time) {
+	c.Breed = strings.TrimSpace(c.Breed)
+	c.Microchip = strings.ToUpper(strings.TrimSpace(c.Microchip))
+	c.Sex = strings.ToLower(strings.TrimSpace(c.Sex))
+	var tags []string
+	seen := map[string]bool{}
+	for _, tag := range c.Tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	c.Tags = tags
+	if born, err := time.Parse(DateLayout, c.BirthDate); err == nil {
+		c.Age = AgeOn(born, now)
+	}
 }
 
 // GetCat retrieves a single cat from the database
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	json "github.com/json-iterator/go"
+)
+
+// Types of the events describing changes to cats.
+const (
+	CatCreated = "cat.created"
+	CatUpdated = "cat.updated"
+	CatDeleted = "cat.deleted"
+)
+
+// OutboxEvent is an event written to the outbox in the same transaction as the change it
+// describes, kept until it has been published.
+type OutboxEvent struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	CatID string `json:"catId"`
+	// TenantID is the tenant owning the cat.
+	TenantID string `json:"tenantId"`
+	// Data is the cat after the change, and is empty for deletions.
+	Data      json.RawMessage `json:"data,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+	// Attempts counts the attempts to publish the event, the latest of which failed with LastError.
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// OutboxStore is implemented by storage backends writing an event to the outbox with every
+// change to a cat.
+type OutboxStore interface {
+	// ClaimOutbox hides the oldest events from other claims until lease has passed, and
+	// returns them oldest first. Events not deleted before then are claimed again.
+	ClaimOutbox(now time.Time, lease time.Duration, limit int) ([]OutboxEvent, error)
+	// DeleteOutbox deletes published events.
+	DeleteOutbox(ids []string) error
+	// FailOutbox records the error failing to publish events, which are claimed again from
+	// retryAt.
+	FailOutbox(ids []string, reason string, retryAt time.Time) error
+}
@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: model/outbox.go
+
+// Package model is a generated GoMock package.
+package model
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockOutboxStore is a mock of OutboxStore interface
+type MockOutboxStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxStoreMockRecorder
+}
+
+// MockOutboxStoreMockRecorder is the mock recorder for MockOutboxStore
+type MockOutboxStoreMockRecorder struct {
+	mock *MockOutboxStore
+}
+
+// NewMockOutboxStore creates a new mock instance
+func NewMockOutboxStore(ctrl *gomock.Controller) *MockOutboxStore {
+	mock := &MockOutboxStore{ctrl: ctrl}
+	mock.recorder = &MockOutboxStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockOutboxStore) EXPECT() *MockOutboxStoreMockRecorder {
+	return m.recorder
+}
+
+// ClaimOutbox mocks base method
+func (m *MockOutboxStore) ClaimOutbox(now time.Time, lease time.Duration, limit int) ([]OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimOutbox", now, lease, limit)
+	ret0, _ := ret[0].([]OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimOutbox indicates an expected call of ClaimOutbox
+func (mr *MockOutboxStoreMockRecorder) ClaimOutbox(now, lease, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimOutbox", reflect.TypeOf((*MockOutboxStore)(nil).ClaimOutbox), now, lease, limit)
+}
+
+// DeleteOutbox mocks base method
+func (m *MockOutboxStore) DeleteOutbox(ids []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOutbox", ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOutbox indicates an expected call of DeleteOutbox
+func (mr *MockOutboxStoreMockRecorder) DeleteOutbox(ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOutbox", reflect.TypeOf((*MockOutboxStore)(nil).DeleteOutbox), ids)
+}
+
+// FailOutbox mocks base method
+func (m *MockOutboxStore) FailOutbox(ids []string, reason string, retryAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailOutbox", ids, reason, retryAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FailOutbox indicates an expected call of FailOutbox
+func (mr *MockOutboxStoreMockRecorder) FailOutbox(ids, reason, retryAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailOutbox", reflect.TypeOf((*MockOutboxStore)(nil).FailOutbox), ids, reason, retryAt)
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"time"
+)
+
+// ErrOwnerHasCats is returned when deleting an owner still assigned cats.
+var ErrOwnerHasCats = errors.New("the owner still has cats")
+
+// ErrCatInAdoption is returned when unassigning a cat pending adoption, or adopted, from its
+// owner.
+var ErrCatInAdoption = errors.New("the cat is pending adoption or adopted")
+
+// Owner is a person owning or fostering cats.
+type Owner struct {
+	ID        string    `json:"id,omitempty" xml:"id,omitempty"`
+	Name      string    `json:"name" xml:"name"`
+	Email     string    `json:"email,omitempty" xml:"email,omitempty"`
+	Phone     string    `json:"phone,omitempty" xml:"phone,omitempty"`
+	CreatedAt time.Time `json:"createdAt" xml:"createdAt"`
+}
+
+// Validate checks that the owner can be stored.
+func (o Owner) Validate() error {
+	if strings.TrimSpace(o.Name) == "" {
+		return errors.New("name is required")
+	}
+	if o.Email != "" {
+		if _, err := mail.ParseAddress(o.Email); err != nil {
+			return errors.New("email must be an email address")
+		}
+	}
+	return nil
+}
+
+// OwnerStore is implemented by storage backends able to persist owners and the cats
+// assigned to them. Methods return sql.ErrNoRows for unknown owners or cats.
+type OwnerStore interface {
+	InsertOwner(Owner) (string, error)
+	SelectOwner(id string) (Owner, error)
+	SelectOwners(count, start int) ([]Owner, error)
+	UpdateOwner(Owner) error
+	// DeleteOwner returns ErrOwnerHasCats while the owner is still assigned cats.
+	DeleteOwner(id string) error
+	SelectOwnerCats(id string) ([]Cat, error)
+	// AssignCat assigns a cat to an owner, taking it from any previous owner.
+	AssignCat(catID, ownerID string) error
+	// UnassignCat takes a cat from its owner, returning sql.ErrNoRows unless the cat is
+	// assigned to the owner, and ErrCatInAdoption while it is pending adoption or adopted.
+	UnassignCat(catID, ownerID string) error
+}
@@ -0,0 +1,149 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: model/owners.go
+
+// Package model is a generated GoMock package.
+package model
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockOwnerStore is a mock of OwnerStore interface
+type MockOwnerStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockOwnerStoreMockRecorder
+}
+
+// MockOwnerStoreMockRecorder is the mock recorder for MockOwnerStore
+type MockOwnerStoreMockRecorder struct {
+	mock *MockOwnerStore
+}
+
+// NewMockOwnerStore creates a new mock instance
+func NewMockOwnerStore(ctrl *gomock.Controller) *MockOwnerStore {
+	mock := &MockOwnerStore{ctrl: ctrl}
+	mock.recorder = &MockOwnerStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockOwnerStore) EXPECT() *MockOwnerStoreMockRecorder {
+	return m.recorder
+}
+
+// InsertOwner mocks base method
+func (m *MockOwnerStore) InsertOwner(arg0 Owner) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertOwner", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertOwner indicates an expected call of InsertOwner
+func (mr *MockOwnerStoreMockRecorder) InsertOwner(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertOwner", reflect.TypeOf((*MockOwnerStore)(nil).InsertOwner), arg0)
+}
+
+// SelectOwner mocks base method
+func (m *MockOwnerStore) SelectOwner(id string) (Owner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectOwner", id)
+	ret0, _ := ret[0].(Owner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectOwner indicates an expected call of SelectOwner
+func (mr *MockOwnerStoreMockRecorder) SelectOwner(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectOwner", reflect.TypeOf((*MockOwnerStore)(nil).SelectOwner), id)
+}
+
+// SelectOwners mocks base method
+func (m *MockOwnerStore) SelectOwners(count, start int) ([]Owner, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectOwners", count, start)
+	ret0, _ := ret[0].([]Owner)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectOwners indicates an expected call of SelectOwners
+func (mr *MockOwnerStoreMockRecorder) SelectOwners(count, start interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectOwners", reflect.TypeOf((*MockOwnerStore)(nil).SelectOwners), count, start)
+}
+
+// UpdateOwner mocks base method
+func (m *MockOwnerStore) UpdateOwner(arg0 Owner) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOwner", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateOwner indicates an expected call of UpdateOwner
+func (mr *MockOwnerStoreMockRecorder) UpdateOwner(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOwner", reflect.TypeOf((*MockOwnerStore)(nil).UpdateOwner), arg0)
+}
+
+// DeleteOwner mocks base method
+func (m *MockOwnerStore) DeleteOwner(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOwner", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteOwner indicates an expected call of DeleteOwner
+func (mr *MockOwnerStoreMockRecorder) DeleteOwner(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOwner", reflect.TypeOf((*MockOwnerStore)(nil).DeleteOwner), id)
+}
+
+// SelectOwnerCats mocks base method
+func (m *MockOwnerStore) SelectOwnerCats(id string) ([]Cat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectOwnerCats", id)
+	ret0, _ := ret[0].([]Cat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectOwnerCats indicates an expected call of SelectOwnerCats
+func (mr *MockOwnerStoreMockRecorder) SelectOwnerCats(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectOwnerCats", reflect.TypeOf((*MockOwnerStore)(nil).SelectOwnerCats), id)
+}
+
+// AssignCat mocks base method
+func (m *MockOwnerStore) AssignCat(catID, ownerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignCat", catID, ownerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignCat indicates an expected call of AssignCat
+func (mr *MockOwnerStoreMockRecorder) AssignCat(catID, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignCat", reflect.TypeOf((*MockOwnerStore)(nil).AssignCat), catID, ownerID)
+}
+
+// UnassignCat mocks base method
+func (m *MockOwnerStore) UnassignCat(catID, ownerID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnassignCat", catID, ownerID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnassignCat indicates an expected call of UnassignCat
+func (mr *MockOwnerStoreMockRecorder) UnassignCat(catID, ownerID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnassignCat", reflect.TypeOf((*MockOwnerStore)(nil).UnassignCat), catID, ownerID)
+}
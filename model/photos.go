@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+// Photo is the metadata of a photo of a cat, whose files are kept in a blob store.
+type Photo struct {
+	ID          string    `json:"id" xml:"id" yaml:"id"`
+	CatID       string    `json:"-" xml:"-" yaml:"-"`
+	ContentType string    `json:"contentType" xml:"contentType" yaml:"contentType"`
+	Size        int64     `json:"size" xml:"size" yaml:"size"`
+	Width       int       `json:"width" xml:"width" yaml:"width"`
+	Height      int       `json:"height" xml:"height" yaml:"height"`
+	CreatedAt   time.Time `json:"createdAt" xml:"createdAt" yaml:"createdAt"`
+}
+
+// PhotoStore is implemented by storage backends able to persist the metadata of photos of
+// cats. Methods return sql.ErrNoRows for unknown cats or photos.
+type PhotoStore interface {
+	// InsertPhoto stores a photo, whose id and creation time are chosen by the caller, the
+	// id keying its files.
+	InsertPhoto(Photo) error
+	SelectPhoto(catID, id string) (Photo, error)
+	// SelectPhotos selects every photo of a cat, oldest first.
+	SelectPhotos(catID string) ([]Photo, error)
+	DeletePhoto(catID, id string) error
+}
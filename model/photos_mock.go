@@ -0,0 +1,91 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: model/photos.go
+
+// Package model is a generated GoMock package.
+package model
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockPhotoStore is a mock of PhotoStore interface
+type MockPhotoStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockPhotoStoreMockRecorder
+}
+
+// MockPhotoStoreMockRecorder is the mock recorder for MockPhotoStore
+type MockPhotoStoreMockRecorder struct {
+	mock *MockPhotoStore
+}
+
+// NewMockPhotoStore creates a new mock instance
+func NewMockPhotoStore(ctrl *gomock.Controller) *MockPhotoStore {
+	mock := &MockPhotoStore{ctrl: ctrl}
+	mock.recorder = &MockPhotoStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockPhotoStore) EXPECT() *MockPhotoStoreMockRecorder {
+	return m.recorder
+}
+
+// InsertPhoto mocks base method
+func (m *MockPhotoStore) InsertPhoto(arg0 Photo) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertPhoto", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertPhoto indicates an expected call of InsertPhoto
+func (mr *MockPhotoStoreMockRecorder) InsertPhoto(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertPhoto", reflect.TypeOf((*MockPhotoStore)(nil).InsertPhoto), arg0)
+}
+
+// SelectPhoto mocks base method
+func (m *MockPhotoStore) SelectPhoto(catID, id string) (Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectPhoto", catID, id)
+	ret0, _ := ret[0].(Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectPhoto indicates an expected call of SelectPhoto
+func (mr *MockPhotoStoreMockRecorder) SelectPhoto(catID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectPhoto", reflect.TypeOf((*MockPhotoStore)(nil).SelectPhoto), catID, id)
+}
+
+// SelectPhotos mocks base method
+func (m *MockPhotoStore) SelectPhotos(catID string) ([]Photo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectPhotos", catID)
+	ret0, _ := ret[0].([]Photo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectPhotos indicates an expected call of SelectPhotos
+func (mr *MockPhotoStoreMockRecorder) SelectPhotos(catID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectPhotos", reflect.TypeOf((*MockPhotoStore)(nil).SelectPhotos), catID)
+}
+
+// DeletePhoto mocks base method
+func (m *MockPhotoStore) DeletePhoto(catID, id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePhoto", catID, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePhoto indicates an expected call of DeletePhoto
+func (mr *MockPhotoStoreMockRecorder) DeletePhoto(catID, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePhoto", reflect.TypeOf((*MockPhotoStore)(nil).DeletePhoto), catID, id)
+}
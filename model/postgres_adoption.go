@@ -0,0 +1,88 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+)
+
+// createTransitionsQuery tracks the status of cats, every cat starting at intake, and
+// records their transitions, which are deleted along with their cat.
+const createTransitionsQuery string = `
+ALTER TABLE cats ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT '` + StatusIntake + `';
+CREATE INDEX IF NOT EXISTS cats_status ON cats (tenant_id, status);
+CREATE TABLE IF NOT EXISTS cat_transitions (
+id uuid PRIMARY KEY DEFAULT uuid_generate_v4 (),
+cat_id uuid NOT NULL REFERENCES cats (id) ON DELETE CASCADE,
+tenant_id uuid NOT NULL DEFAULT '` + DefaultTenant + `' REFERENCES tenants (id),
+from_status TEXT NOT NULL,
+to_status TEXT NOT NULL,
+actor TEXT NOT NULL,
+reason TEXT NOT NULL DEFAULT '',
+created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS cat_transitions_cat ON cat_transitions (cat_id, created_at);
+ALTER TABLE cat_transitions ENABLE ROW LEVEL SECURITY;
+ALTER TABLE cat_transitions FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS cat_transitions_tenant ON cat_transitions;
+CREATE POLICY cat_transitions_tenant ON cat_transitions
+USING (tenant_id::text = coalesce(nullif(current_setting('cats.tenant_id', true), ''), tenant_id::text));`
+
+// TransitionCat moves a cat of the tenant to another status and records the transition in
+// the same transaction, writing the change to the outbox. Cats are only moved to statuses
+// requiring an owner while they have one.
+func (p *PostGres) TransitionCat(t Transition) (Transition, error) {
+	err := p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		cat := Cat{ID: t.CatID}
+		// the owner is checked along with the status, as it may have been unassigned since
+		// the guards of the status were checked
+		err := tx.QueryRow(`UPDATE cats SET status=$3 WHERE id=$1 AND tenant_id=$2 AND status=$4
+AND (owner_id IS NOT NULL OR NOT $5) RETURNING `+catFields,
+			t.CatID, p.tenant, t.To, t.From, RequiresOwner(t.To)).Scan(catDest(&cat)...)
+		if err == sql.ErrNoRows {
+			var status string
+			err := tx.QueryRow(`SELECT status FROM cats WHERE id=$1 AND tenant_id=$2`, t.CatID, p.tenant).Scan(&status)
+			switch {
+			case err != nil:
+				return err
+			case status != t.From:
+				return ErrStatusChanged
+			}
+			return TransitionError{From: t.From, To: t.To, Reason: "the cat must be assigned an owner"}
+		}
+		if err != nil {
+			return err
+		}
+
+		err = tx.QueryRow(`INSERT INTO cat_transitions (cat_id, from_status, to_status, actor, reason, tenant_id)
+VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`,
+			t.CatID, t.From, t.To, t.Actor, t.Reason, p.tenant).Scan(&t.ID, &t.CreatedAt)
+		if err != nil {
+			return err
+		}
+		return p.writeChange(tx, CatUpdated, t.CatID, &cat)
+	})
+	return t, err
+}
+
+// SelectTransitions selects the transitions of a cat of the tenant, oldest first.
+func (p *PostGres) SelectTransitions(catID string) ([]Transition, error) {
+	transitions := []Transition{}
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT id, cat_id, from_status, to_status, actor, reason, created_at FROM cat_transitions
+WHERE cat_id=$1 AND tenant_id=$2 ORDER BY created_at, id`, catID, p.tenant)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			var t Transition
+			if err := rows.Scan(&t.ID, &t.CatID, &t.From, &t.To, &t.Actor, &t.Reason, &t.CreatedAt); err != nil {
+				return err
+			}
+			transitions = append(transitions, t)
+		}
+		return rows.Err()
+	})
+	return transitions, err
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"database/sql"
+	"time"
+)
+
+const createIdempotencyKeysQuery string = `
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+key TEXT PRIMARY KEY,
+fingerprint TEXT NOT NULL,
+completed BOOLEAN NOT NULL DEFAULT false,
+status INT NOT NULL DEFAULT 0,
+content_type TEXT NOT NULL DEFAULT '',
+body BYTEA,
+expires_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idempotency_keys_expiry ON idempotency_keys (expires_at);`
+
+// ReserveIdempotencyKey inserts the key, or takes it over once expired. Concurrent
+// reservations of a key wait on each other, so only one of them succeeds.
+func (p *PostGres) ReserveIdempotencyKey(key string, fingerprint string, now time.Time, expiresAt time.Time) (IdempotencyRecord, bool, error) {
+	var reserved bool
+	err := p.database.QueryRow(`INSERT INTO idempotency_keys (key, fingerprint, expires_at) VALUES ($1, $2, $3)
+ON CONFLICT (key) DO UPDATE SET fingerprint=EXCLUDED.fingerprint, completed=false, status=0, content_type='',
+body=NULL, expires_at=EXCLUDED.expires_at WHERE idempotency_keys.expires_at <= $4
+RETURNING true`, key, fingerprint, expiresAt, now).Scan(&reserved)
+	if err == nil {
+		return IdempotencyRecord{Key: key, Fingerprint: fingerprint, ExpiresAt: expiresAt}, true, nil
+	}
+	if err != sql.ErrNoRows {
+		return IdempotencyRecord{}, false, err
+	}
+
+	r := IdempotencyRecord{Key: key}
+	err = p.database.QueryRow(`SELECT fingerprint, completed, status, content_type, body, expires_at
+FROM idempotency_keys WHERE key=$1`, key).Scan(&r.Fingerprint, &r.Completed, &r.Status, &r.ContentType, &r.Body, &r.ExpiresAt)
+	return r, false, err
+}
+
+// CompleteIdempotencyKey stores the response to the request a key was reserved for.
+func (p *PostGres) CompleteIdempotencyKey(r IdempotencyRecord) error {
+	_, err := p.database.Exec(`UPDATE idempotency_keys SET completed=true, status=$2, content_type=$3, body=$4,
+expires_at=$5 WHERE key=$1`, r.Key, r.Status, r.ContentType, r.Body, r.ExpiresAt)
+	return err
+}
+
+// ReleaseIdempotencyKey deletes a key.
+func (p *PostGres) ReleaseIdempotencyKey(key string) error {
+	_, err := p.database.Exec(`DELETE FROM idempotency_keys WHERE key=$1`, key)
+	return err
+}
+
+// DeleteExpiredIdempotencyKeys deletes the keys expired by now.
+func (p *PostGres) DeleteExpiredIdempotencyKeys(now time.Time) error {
+	_, err := p.database.Exec(`DELETE FROM idempotency_keys WHERE expires_at <= $1`, now)
+	return err
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// createMedicalRecordsQuery creates the medical records of cats, which are deleted along
+// with their cat. Vaccinations are indexed by their due date for the schedule of doses.
+const createMedicalRecordsQuery string = `
+CREATE TABLE IF NOT EXISTS medical_records (
+id uuid PRIMARY KEY DEFAULT uuid_generate_v4 (),
+cat_id uuid NOT NULL REFERENCES cats (id) ON DELETE CASCADE,
+tenant_id uuid NOT NULL DEFAULT '` + DefaultTenant + `' REFERENCES tenants (id),
+kind TEXT NOT NULL,
+name TEXT NOT NULL DEFAULT '',
+date DATE NOT NULL,
+due_date DATE,
+weight_kg DOUBLE PRECISION NOT NULL DEFAULT 0,
+notes TEXT NOT NULL DEFAULT '',
+created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS medical_records_cat ON medical_records (cat_id, date);
+CREATE INDEX IF NOT EXISTS medical_records_due ON medical_records (tenant_id, due_date) WHERE kind = 'vaccination';
+ALTER TABLE medical_records ENABLE ROW LEVEL SECURITY;
+ALTER TABLE medical_records FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS medical_records_tenant ON medical_records;
+CREATE POLICY medical_records_tenant ON medical_records
+USING (tenant_id::text = coalesce(nullif(current_setting('cats.tenant_id', true), ''), tenant_id::text));`
+
+const medicalColumns = `id, cat_id, kind, name, to_char(date, 'YYYY-MM-DD'), coalesce(to_char(due_date, 'YYYY-MM-DD'), ''),
+weight_kg, notes, created_at`
+
+// InsertMedicalRecord stores a record of a cat of the tenant.
+func (p *PostGres) InsertMedicalRecord(m MedicalRecord) (string, error) {
+	var id string
+	err := p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		err := tx.QueryRow(`INSERT INTO medical_records (cat_id, kind, name, date, due_date, weight_kg, notes, tenant_id)
+SELECT id, $2, $3, $4::date, nullif($5, '')::date, $6, $7, tenant_id FROM cats WHERE id=$1 AND tenant_id=$8 RETURNING id`,
+			m.CatID, m.Kind, m.Name, m.Date, m.DueDate, m.WeightKg, m.Notes, p.tenant).Scan(&id)
+		if e, ok := err.(*pq.Error); ok && e.Code == "23503" {
+			return sql.ErrNoRows
+		}
+		return err
+	})
+	return id, err
+}
+
+// SelectMedicalRecord selects a record of a cat of the tenant.
+func (p *PostGres) SelectMedicalRecord(catID, id string) (MedicalRecord, error) {
+	var m MedicalRecord
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		var err error
+		m, err = scanMedicalRecord(tx.QueryRow(`SELECT `+medicalColumns+` FROM medical_records
+WHERE id=$1 AND cat_id=$2 AND tenant_id=$3`, id, catID, p.tenant))
+		return err
+	})
+	return m, err
+}
+
+// SelectMedicalRecords selects the records of a cat of the tenant, latest first.
+func (p *PostGres) SelectMedicalRecords(catID, kind string) ([]MedicalRecord, error) {
+	records := []MedicalRecord{}
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT `+medicalColumns+` FROM medical_records
+WHERE cat_id=$1 AND tenant_id=$2 AND ($3 = '' OR kind = $3) ORDER BY date DESC, created_at DESC`, catID, p.tenant, kind)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			m, err := scanMedicalRecord(rows)
+			if err != nil {
+				return err
+			}
+			records = append(records, m)
+		}
+		return rows.Err()
+	})
+	return records, err
+}
+
+// DeleteMedicalRecord deletes a record of a cat of the tenant.
+func (p *PostGres) DeleteMedicalRecord(catID, id string) error {
+	return p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		return affected(tx.Exec(`DELETE FROM medical_records WHERE id=$1 AND cat_id=$2 AND tenant_id=$3`, id, catID, p.tenant))
+	})
+}
+
+// SelectDueVaccinations selects the next doses due for the cats of the tenant on or before
+// until, from the latest vaccination of every cat with every vaccine.
+func (p *PostGres) SelectDueVaccinations(until time.Time) ([]DueVaccination, error) {
+	due := []DueVaccination{}
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT latest.cat_id, cats.name, latest.name, to_char(latest.date, 'YYYY-MM-DD'),
+to_char(latest.due_date, 'YYYY-MM-DD'), latest.due_date < current_date
+FROM (
+SELECT DISTINCT ON (cat_id, lower(name)) cat_id, name, date, due_date FROM medical_records
+WHERE kind = 'vaccination' AND tenant_id=$2
+ORDER BY cat_id, lower(name), date DESC, created_at DESC
+) latest
+JOIN cats ON cats.id = latest.cat_id
+WHERE latest.due_date IS NOT NULL AND latest.due_date <= $1::date
+ORDER BY latest.due_date, cats.name`, until.Format(DateLayout), p.tenant)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			var d DueVaccination
+			if err := rows.Scan(&d.CatID, &d.CatName, &d.Vaccine, &d.LastDate, &d.DueDate, &d.Overdue); err != nil {
+				return err
+			}
+			due = append(due, d)
+		}
+		return rows.Err()
+	})
+	return due, err
+}
+
+func scanMedicalRecord(row scanner) (MedicalRecord, error) {
+	var m MedicalRecord
+	err := row.Scan(&m.ID, &m.CatID, &m.Kind, &m.Name, &m.Date, &m.DueDate, &m.WeightKg, &m.Notes, &m.CreatedAt)
+	return m, err
+}
@@ -0,0 +1,152 @@
+package model
+
+import (
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+	"github.com/waikco/cats-v1/conf"
+)
+
+// ChangesChannel is the channel every change to the cats table is notified on.
+const ChangesChannel = "cats_changes"
+
+const createNotifyChangesQuery string = `
+CREATE OR REPLACE FUNCTION notify_cats_change() RETURNS trigger AS $$
+DECLARE
+cat_id uuid;
+BEGIN
+IF TG_OP = 'DELETE' THEN
+cat_id := OLD.id;
+ELSE
+cat_id := NEW.id;
+END IF;
+PERFORM pg_notify('` + ChangesChannel + `', json_build_object(
+'op', TG_OP, 'id', cat_id, 'origin', current_setting('application_name'))::text);
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+DROP TRIGGER IF EXISTS cats_changes ON cats;
+CREATE TRIGGER cats_changes AFTER INSERT OR UPDATE OR DELETE ON cats
+FOR EACH ROW EXECUTE PROCEDURE notify_cats_change();`
+
+// Operations of changes. A resync follows reconnecting to the database, when any change
+// made while disconnected may have been missed.
+const (
+	ChangeInsert = "INSERT"
+	ChangeUpdate = "UPDATE"
+	ChangeDelete = "DELETE"
+	ChangeResync = "RESYNC"
+)
+
+// instanceName names the connections of this instance, telling its changes apart from
+// those of other instances sharing the database.
+var instanceName = "cats-v1-" + uuid.NewV4().String()
+
+const (
+	listenerMinReconnect = time.Second
+	listenerMaxReconnect = time.Minute
+	// listenerPing is how often an idle listener checks its connection is still alive.
+	listenerPing = 90 * time.Second
+)
+
+// Change is a change to the cats table, as notified by the database.
+type Change struct {
+	Op     string `json:"op"`
+	ID     string `json:"id"`
+	Tenant string `json:"tenant"`
+	Origin string `json:"origin"`
+}
+
+// InstanceName names the database connections of this instance.
+func InstanceName() string {
+	return instanceName
+}
+
+// Local reports whether the change was made by this instance.
+func (c Change) Local() bool {
+	return c.Origin == instanceName
+}
+
+// ParseChange decodes the payload of a notification on ChangesChannel.
+func ParseChange(payload string) (Change, error) {
+	var c Change
+	err := json.Unmarshal([]byte(payload), &c)
+	return c, err
+}
+
+// ChangeListener listens for changes to the cats table, reconnecting whenever the
+// connection is lost.
+type ChangeListener struct {
+	listener *pq.Listener
+	changes  chan Change
+	done     chan struct{}
+}
+
+// ListenPostgres starts listening for changes to the cats table made by every instance.
+func ListenPostgres(config conf.Database) (*ChangeListener, error) {
+	listener := pq.NewListener(dataSource(config), listenerMinReconnect, listenerMaxReconnect,
+		func(event pq.ListenerEventType, err error) {
+			switch event {
+			case pq.ListenerEventDisconnected:
+				log.Warn().Msgf("lost connection listening for changes: %v", err)
+			case pq.ListenerEventReconnected:
+				log.Info().Msg("reconnected listening for changes")
+			case pq.ListenerEventConnectionAttemptFailed:
+				log.Warn().Msgf("unable to reconnect listening for changes: %v", err)
+			}
+		})
+	if err := listener.Listen(ChangesChannel); err != nil {
+		_ = listener.Close()
+		return nil, err
+	}
+
+	l := &ChangeListener{listener: listener, changes: make(chan Change, 64), done: make(chan struct{})}
+	go l.run()
+	return l, nil
+}
+
+// Changes receives every change, and is closed once the listener is.
+func (l *ChangeListener) Changes() <-chan Change {
+	return l.changes
+}
+
+// Ping checks the connection of the listener is alive.
+func (l *ChangeListener) Ping() error {
+	return l.listener.Ping()
+}
+
+// Close stops listening.
+func (l *ChangeListener) Close() error {
+	close(l.done)
+	return l.listener.Close()
+}
+
+func (l *ChangeListener) run() {
+	defer close(l.changes)
+	for {
+		select {
+		case <-l.done:
+			return
+		case n := <-l.listener.Notify:
+			change := Change{Op: ChangeResync}
+			// a nil notification follows a reconnection
+			if n != nil {
+				var err error
+				if change, err = ParseChange(n.Extra); err != nil {
+					log.Error().Msgf("invalid change notified on %s: %v", n.Channel, err)
+					continue
+				}
+			}
+			select {
+			case l.changes <- change:
+			case <-l.done:
+				return
+			}
+		case <-time.After(listenerPing):
+			go func() { _ = l.listener.Ping() }()
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package model
+
+import (
+	"database/sql"
+	"sort"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/lib/pq"
+)
+
+const createOutboxQuery string = `
+CREATE TABLE IF NOT EXISTS outbox (
+id uuid PRIMARY KEY DEFAULT uuid_generate_v4 (),
+type TEXT NOT NULL,
+cat_id uuid NOT NULL,
+data JSONB,
+created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp(),
+claimed_until TIMESTAMPTZ NOT NULL DEFAULT '-infinity',
+attempts INT NOT NULL DEFAULT 0,
+last_error TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS outbox_created ON outbox (created_at);`
+
+// execer is implemented by both the database and its transactions.
+type execer interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// writeChange records a change to a cat within the transaction making it, writing its event
+// to the outbox and queueing its delivery to webhooks, so neither is lost when the process
+// stops right after the change is committed.
+func (p *PostGres) writeChange(tx execer, eventType string, id string, cat *Cat) error {
+	if err := p.writeOutbox(tx, eventType, id, cat); err != nil {
+		return err
+	}
+	return p.queueDeliveries(tx, eventType, id, cat)
+}
+
+// writeOutbox writes the event of a change to the outbox when enabled, within the transaction
+// making the change. A nil cat writes no data.
+func (p *PostGres) writeOutbox(tx execer, eventType string, id string, cat *Cat) error {
+	if !p.outbox {
+		return nil
+	}
+	var data []byte
+	if cat != nil {
+		var err error
+		if data, err = json.Marshal(cat); err != nil {
+			return err
+		}
+	}
+	_, err := tx.Exec(`INSERT INTO outbox (type, cat_id, tenant_id, data) VALUES ($1, $2, $3, $4)`,
+		eventType, id, p.tenant, data)
+	return err
+}
+
+// ClaimOutbox claims the oldest events not claimed by now, skipping rows claimed concurrently.
+func (p *PostGres) ClaimOutbox(now time.Time, lease time.Duration, limit int) ([]OutboxEvent, error) {
+	rows, err := p.database.Query(`UPDATE outbox SET claimed_until=$2, attempts=attempts+1 WHERE id IN (
+SELECT id FROM outbox WHERE claimed_until <= $1
+ORDER BY created_at LIMIT $3 FOR UPDATE SKIP LOCKED)
+RETURNING id, type, cat_id, tenant_id, data, created_at, attempts, last_error`, now, now.Add(lease), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	events := []OutboxEvent{}
+	for rows.Next() {
+		var e OutboxEvent
+		var data []byte
+		if err := rows.Scan(&e.ID, &e.Type, &e.CatID, &e.TenantID, &data, &e.CreatedAt, &e.Attempts, &e.LastError); err != nil {
+			return nil, err
+		}
+		e.Data = data
+		events = append(events, e)
+	}
+	// RETURNING leaves rows in no particular order
+	sort.SliceStable(events, func(i, j int) bool { return events[i].CreatedAt.Before(events[j].CreatedAt) })
+	return events, rows.Err()
+}
+
+// DeleteOutbox deletes published events.
+func (p *PostGres) DeleteOutbox(ids []string) error {
+	_, err := p.database.Exec(`DELETE FROM outbox WHERE id = ANY($1::uuid[])`, pq.Array(ids))
+	return err
+}
+
+// FailOutbox records the error failing to publish events, extending their claim to retryAt.
+func (p *PostGres) FailOutbox(ids []string, reason string, retryAt time.Time) error {
+	_, err := p.database.Exec(`UPDATE outbox SET last_error=$2, claimed_until=$3 WHERE id = ANY($1::uuid[])`,
+		pq.Array(ids), reason, retryAt)
+	return err
+}
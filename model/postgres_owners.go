@@ -0,0 +1,180 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// createOwnersQuery creates owners, scoped to tenants the same way as cats, and lets cats
+// reference their owner. Owners are only deleted once they have no cats left.
+const createOwnersQuery string = `
+CREATE TABLE IF NOT EXISTS owners (
+id uuid PRIMARY KEY DEFAULT uuid_generate_v4 (),
+tenant_id uuid NOT NULL DEFAULT '` + DefaultTenant + `' REFERENCES tenants (id),
+name TEXT NOT NULL,
+email TEXT NOT NULL DEFAULT '',
+phone TEXT NOT NULL DEFAULT '',
+created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS owners_tenant ON owners (tenant_id, created_at);
+ALTER TABLE owners ENABLE ROW LEVEL SECURITY;
+ALTER TABLE owners FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS owners_tenant ON owners;
+CREATE POLICY owners_tenant ON owners
+USING (tenant_id::text = coalesce(nullif(current_setting('cats.tenant_id', true), ''), tenant_id::text));
+ALTER TABLE cats ADD COLUMN IF NOT EXISTS owner_id uuid REFERENCES owners (id) ON DELETE RESTRICT;
+CREATE INDEX IF NOT EXISTS cats_owner ON cats (owner_id);`
+
+const ownerColumns = `id, name, email, phone, created_at`
+
+// InsertOwner stores an owner of the tenant, returning its id.
+func (p *PostGres) InsertOwner(o Owner) (string, error) {
+	var id string
+	err := p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		return tx.QueryRow(`INSERT INTO owners (name, email, phone, tenant_id) VALUES ($1, $2, $3, $4) RETURNING id`,
+			o.Name, o.Email, o.Phone, p.tenant).Scan(&id)
+	})
+	return id, err
+}
+
+// SelectOwner selects an owner of the tenant.
+func (p *PostGres) SelectOwner(id string) (Owner, error) {
+	var o Owner
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		var err error
+		o, err = scanOwner(tx.QueryRow(`SELECT `+ownerColumns+` FROM owners WHERE id=$1 AND tenant_id=$2`, id, p.tenant))
+		return err
+	})
+	return o, err
+}
+
+// SelectOwners selects a page of the owners of the tenant, oldest first.
+func (p *PostGres) SelectOwners(count, start int) ([]Owner, error) {
+	owners := []Owner{}
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT `+ownerColumns+` FROM owners WHERE tenant_id=$3 ORDER BY created_at, id LIMIT $1 OFFSET $2`,
+			count, start, p.tenant)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			o, err := scanOwner(rows)
+			if err != nil {
+				return err
+			}
+			owners = append(owners, o)
+		}
+		return rows.Err()
+	})
+	return owners, err
+}
+
+// UpdateOwner updates the name and contact details of an owner of the tenant.
+func (p *PostGres) UpdateOwner(o Owner) error {
+	return p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`UPDATE owners SET name=$1, email=$2, phone=$3 WHERE id=$4 AND tenant_id=$5`,
+			o.Name, o.Email, o.Phone, o.ID, p.tenant)
+		return affected(result, err)
+	})
+}
+
+// DeleteOwner deletes an owner of the tenant, unless it still has cats.
+func (p *PostGres) DeleteOwner(id string) error {
+	return p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`DELETE FROM owners WHERE id=$1 AND tenant_id=$2`, id, p.tenant)
+		if e, ok := err.(*pq.Error); ok && e.Code == "23503" {
+			return ErrOwnerHasCats
+		}
+		return affected(result, err)
+	})
+}
+
+// SelectOwnerCats selects every cat assigned to an owner of the tenant.
+func (p *PostGres) SelectOwnerCats(id string) ([]Cat, error) {
+	cats := []Cat{}
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT `+catColumns+` FROM cats WHERE owner_id=$1 AND tenant_id=$2 ORDER BY id`, id, p.tenant)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			cat, err := scanCat(rows)
+			if err != nil {
+				return err
+			}
+			cats = append(cats, cat)
+		}
+		return rows.Err()
+	})
+	return cats, err
+}
+
+// AssignCat assigns a cat of the tenant to one of its owners, writing the change to the
+// outbox.
+func (p *PostGres) AssignCat(catID, ownerID string) error {
+	return p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		var owned bool
+		err := tx.QueryRow(`SELECT true FROM owners WHERE id=$1 AND tenant_id=$2`, ownerID, p.tenant).Scan(&owned)
+		if err != nil {
+			return err
+		}
+		return p.setOwner(tx, catID, `UPDATE cats SET owner_id=$3 WHERE id=$1 AND tenant_id=$2
+RETURNING `+catFields, ownerID)
+	})
+}
+
+// UnassignCat takes a cat of the tenant from its owner, writing the change to the outbox.
+// Cats pending adoption, or adopted, keep their owner.
+func (p *PostGres) UnassignCat(catID, ownerID string) error {
+	return p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		err := p.setOwner(tx, catID, `UPDATE cats SET owner_id=NULL WHERE id=$1 AND tenant_id=$2 AND owner_id=$3
+AND status NOT IN ('`+StatusPendingAdoption+`', '`+StatusAdopted+`') RETURNING `+catFields, ownerID)
+		if err != sql.ErrNoRows {
+			return err
+		}
+		var status string
+		err = tx.QueryRow(`SELECT status FROM cats WHERE id=$1 AND tenant_id=$2 AND owner_id=$3`,
+			catID, p.tenant, ownerID).Scan(&status)
+		switch {
+		case err != nil:
+			return err
+		case RequiresOwner(status):
+			return ErrCatInAdoption
+		}
+		return sql.ErrNoRows
+	})
+}
+
+func (p *PostGres) setOwner(tx *sql.Tx, catID string, query string, ownerID string) error {
+	cat := Cat{ID: catID}
+	if err := tx.QueryRow(query, catID, p.tenant, ownerID).Scan(catDest(&cat)...); err != nil {
+		return err
+	}
+	return p.writeChange(tx, CatUpdated, catID, &cat)
+}
+
+// affected returns sql.ErrNoRows when a statement changed no rows.
+func affected(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = sql.ErrNoRows
+		}
+		return err
+	}
+	return nil
+}
+
+func scanOwner(row scanner) (Owner, error) {
+	var o Owner
+	err := row.Scan(&o.ID, &o.Name, &o.Email, &o.Phone, &o.CreatedAt)
+	return o, err
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// createPhotosQuery creates the metadata of the photos of cats, which are deleted along
+// with their cat.
+const createPhotosQuery string = `
+CREATE TABLE IF NOT EXISTS photos (
+id uuid PRIMARY KEY,
+cat_id uuid NOT NULL REFERENCES cats (id) ON DELETE CASCADE,
+tenant_id uuid NOT NULL DEFAULT '` + DefaultTenant + `' REFERENCES tenants (id),
+content_type TEXT NOT NULL,
+size BIGINT NOT NULL,
+width INT NOT NULL,
+height INT NOT NULL,
+created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS photos_cat ON photos (cat_id, created_at);
+ALTER TABLE photos ENABLE ROW LEVEL SECURITY;
+ALTER TABLE photos FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS photos_tenant ON photos;
+CREATE POLICY photos_tenant ON photos
+USING (tenant_id::text = coalesce(nullif(current_setting('cats.tenant_id', true), ''), tenant_id::text));`
+
+const photoColumns = `id, cat_id, content_type, size, width, height, created_at`
+
+// InsertPhoto stores a photo of a cat of the tenant.
+func (p *PostGres) InsertPhoto(photo Photo) error {
+	return p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		result, err := tx.Exec(`INSERT INTO photos (id, cat_id, content_type, size, width, height, created_at, tenant_id)
+SELECT $1, id, $3, $4, $5, $6, $7, tenant_id FROM cats WHERE id=$2 AND tenant_id=$8`,
+			photo.ID, photo.CatID, photo.ContentType, photo.Size, photo.Width, photo.Height, photo.CreatedAt, p.tenant)
+		if e, ok := err.(*pq.Error); ok && e.Code == "23503" {
+			return sql.ErrNoRows
+		}
+		return affected(result, err)
+	})
+}
+
+// SelectPhoto selects a photo of a cat of the tenant.
+func (p *PostGres) SelectPhoto(catID, id string) (Photo, error) {
+	var photo Photo
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		var err error
+		photo, err = scanPhoto(tx.QueryRow(`SELECT `+photoColumns+` FROM photos WHERE id=$1 AND cat_id=$2 AND tenant_id=$3`,
+			id, catID, p.tenant))
+		return err
+	})
+	return photo, err
+}
+
+// SelectPhotos selects every photo of a cat of the tenant, oldest first.
+func (p *PostGres) SelectPhotos(catID string) ([]Photo, error) {
+	photos := []Photo{}
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT `+photoColumns+` FROM photos WHERE cat_id=$1 AND tenant_id=$2 ORDER BY created_at, id`,
+			catID, p.tenant)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			photo, err := scanPhoto(rows)
+			if err != nil {
+				return err
+			}
+			photos = append(photos, photo)
+		}
+		return rows.Err()
+	})
+	return photos, err
+}
+
+// DeletePhoto deletes a photo of a cat of the tenant.
+func (p *PostGres) DeletePhoto(catID, id string) error {
+	return p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		return affected(tx.Exec(`DELETE FROM photos WHERE id=$1 AND cat_id=$2 AND tenant_id=$3`, id, catID, p.tenant))
+	})
+}
+
+func scanPhoto(row scanner) (Photo, error) {
+	var photo Photo
+	err := row.Scan(&photo.ID, &photo.CatID, &photo.ContentType, &photo.Size, &photo.Width, &photo.Height, &photo.CreatedAt)
+	return photo, err
+}
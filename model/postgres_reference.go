@@ -0,0 +1,87 @@
+package model
+
+import (
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// createReferenceQuery creates the breeds and colors cats are normalised against, which are
+// shared by every tenant and seeded on startup.
+const createReferenceQuery string = `
+CREATE TABLE IF NOT EXISTS breeds (
+name TEXT PRIMARY KEY,
+aliases TEXT[] NOT NULL DEFAULT '{}'
+);
+CREATE TABLE IF NOT EXISTS colors (
+name TEXT PRIMARY KEY,
+hex TEXT NOT NULL,
+aliases TEXT[] NOT NULL DEFAULT '{}'
+);`
+
+// SeedReference stores the breeds and colors missing from the reference tables, keeping any
+// changes made to those already there.
+func (p *PostGres) SeedReference(breeds []Breed, colors []Color) error {
+	tx, err := p.database.Begin()
+	if err != nil {
+		return err
+	}
+	for _, b := range breeds {
+		if _, err := tx.Exec(`INSERT INTO breeds (name, aliases) VALUES ($1, $2) ON CONFLICT (name) DO NOTHING`,
+			b.Name, pq.Array(b.Aliases)); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	for _, c := range colors {
+		if _, err := tx.Exec(`INSERT INTO colors (name, hex, aliases) VALUES ($1, $2, $3) ON CONFLICT (name) DO NOTHING`,
+			c.Name, c.Hex, pq.Array(c.Aliases)); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// SelectBreeds selects every breed, by name.
+func (p *PostGres) SelectBreeds() ([]Breed, error) {
+	breeds := []Breed{}
+	err := p.queryReference(`SELECT name, aliases FROM breeds ORDER BY name`, func(rows *sql.Rows) error {
+		var b Breed
+		if err := rows.Scan(&b.Name, pq.Array(&b.Aliases)); err != nil {
+			return err
+		}
+		breeds = append(breeds, b)
+		return nil
+	})
+	return breeds, err
+}
+
+// SelectColors selects every color, by name.
+func (p *PostGres) SelectColors() ([]Color, error) {
+	colors := []Color{}
+	err := p.queryReference(`SELECT name, hex, aliases FROM colors ORDER BY name`, func(rows *sql.Rows) error {
+		var c Color
+		if err := rows.Scan(&c.Name, &c.Hex, pq.Array(&c.Aliases)); err != nil {
+			return err
+		}
+		colors = append(colors, c)
+		return nil
+	})
+	return colors, err
+}
+
+func (p *PostGres) queryReference(query string, scan func(*sql.Rows) error) error {
+	rows, err := p.database.Query(query)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
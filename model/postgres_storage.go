@@ -1,14 +1,16 @@
 package model
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/jmoiron/sqlx"
 
 	json "github.com/json-iterator/go"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 	"github.com/waikco/cats-v1/conf"
 )
@@ -35,52 +37,132 @@ color TEXT NOT NULL,
 age INT NOT NULL
 );`
 
+// createCatDetailsQuery describes cats in more detail. Microchips are unique to a tenant,
+// and cats without one have none rather than an empty one.
+const createCatDetailsQuery string = `
+ALTER TABLE cats ADD COLUMN IF NOT EXISTS breed TEXT NOT NULL DEFAULT '';
+ALTER TABLE cats ADD COLUMN IF NOT EXISTS birth_date DATE;
+ALTER TABLE cats ADD COLUMN IF NOT EXISTS sex TEXT NOT NULL DEFAULT '';
+ALTER TABLE cats ADD COLUMN IF NOT EXISTS neutered BOOLEAN;
+ALTER TABLE cats ADD COLUMN IF NOT EXISTS weight_kg DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE cats ADD COLUMN IF NOT EXISTS microchip TEXT;
+ALTER TABLE cats ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+CREATE UNIQUE INDEX IF NOT EXISTS cats_microchip ON cats (tenant_id, microchip);
+CREATE INDEX IF NOT EXISTS cats_breed ON cats (tenant_id, lower(breed));
+CREATE INDEX IF NOT EXISTS cats_tags ON cats USING GIN (tags);`
+
+// ageColumn computes the age of cats from their birth date, falling back on the age stored
+// for cats without one.
+const ageColumn = `coalesce(date_part('year', age(current_date, birth_date))::int, age)`
+
+// catFields are the columns of the fields of cats other than their id, in the order
+// scanned by catDest.
+const catFields = `name, color, ` + ageColumn + `, coalesce(owner_id::text, ''), breed,
+coalesce(to_char(birth_date, 'YYYY-MM-DD'), ''), sex, neutered, weight_kg, coalesce(microchip, ''), tags, status`
+
+// catColumns are the columns cats are scanned from, in order.
+const catColumns = `id, ` + catFields
+
+// catDest returns the destinations catFields are scanned into.
+func catDest(cat *Cat) []interface{} {
+	return []interface{}{&cat.Name, &cat.Color, &cat.Age, &cat.OwnerID, &cat.Breed,
+		&cat.BirthDate, &cat.Sex, &cat.Neutered, &cat.WeightKg, &cat.Microchip, pq.Array(&cat.Tags), &cat.Status}
+}
+
+func scanCat(row scanner) (Cat, error) {
+	var cat Cat
+	err := row.Scan(append([]interface{}{&cat.ID}, catDest(&cat)...)...)
+	return cat, err
+}
+
+// insertCatQuery inserts a cat with the arguments of catArgs and its tenant, returning its id.
+const insertCatQuery = `INSERT INTO cats (name, color, age, breed, birth_date, sex, neutered, weight_kg, microchip, tags, tenant_id)
+VALUES ($1, $2, $3, $4, nullif($5, '')::date, $6, $7, $8, nullif($9, ''), coalesce($10::text[], '{}'), $11) RETURNING id`
+
+// catArgs returns the arguments storing the fields of a cat, in the order of insertCatQuery.
+func catArgs(cat Cat) []interface{} {
+	return []interface{}{cat.Name, cat.Color, cat.Age, cat.Breed, cat.BirthDate, cat.Sex, cat.Neutered,
+		cat.WeightKg, cat.Microchip, pq.Array(cat.Tags)}
+}
+
+// catError maps the violation of the uniqueness of microchips onto ErrMicrochipExists.
+func catError(err error) error {
+	if e, ok := err.(*pq.Error); ok && e.Code == "23505" && e.Constraint == "cats_microchip" {
+		return ErrMicrochipExists
+	}
+	return err
+}
+
 type PostGres struct {
 	database *sqlx.DB
 	dbName   string
+	// outbox writes an event to the outbox with every change to a cat.
+	outbox bool
+	// tenant is the tenant every query is scoped to.
+	tenant string
 }
 
 func BootstrapPostgres(config conf.Database) (Storage, error) {
 	// connect to database
-	dbInfo := fmt.Sprintf(
-		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable",
-		config.Host, config.Port, config.User, config.Password, config.DatabaseName)
-	db, err := sqlx.Connect("postgres", dbInfo)
+	db, err := sqlx.Connect("postgres", dataSource(config))
 	if err != nil {
 		return nil, err
 	}
 
 	// return db connection
-	storage := &PostGres{db, config.DatabaseName}
-	_, err = storage.database.Exec(CreateTableQuery)
+	storage := &PostGres{database: db, dbName: config.DatabaseName, outbox: config.Outbox, tenant: DefaultTenant}
+	err = storage.Migrate()
 	if err != nil {
 		return storage, err
 	} else {
-		log.Debug().Msg("table presence confirmed")
+		log.Debug().Msg("schema migrations applied")
+	}
+	if bypass, err := storage.bypassesRowSecurity(); err == nil && bypass {
+		log.Warn().Msgf("database user %s bypasses row level security, connect as a role that is neither a superuser "+
+			"nor has BYPASSRLS for it to keep tenants apart", config.User)
 	}
 	return storage, nil
 }
 
+// dataSource returns the connection string of the database. Connections are named after
+// the instance, so changes notified by the database tell which instance made them.
+func dataSource(config conf.Database) string {
+	return fmt.Sprintf(
+		"host=%s port=%d user=%s password=%s dbname=%s sslmode=disable application_name=%s",
+		config.Host, config.Port, config.User, config.Password, config.DatabaseName, instanceName)
+}
+
 func (p *PostGres) Insert(b []byte) (string, error) {
 	var cat Cat
 	if err := json.Unmarshal(b, &cat); err != nil {
 		return "", err
 	}
 
+	cat.Normalize(time.Now())
+
 	var id string
-	query := `INSERT INTO cats (name,color,age) VALUES ($1,$2, $3) RETURNING id`
-	err := p.database.QueryRow(query, cat.Name, cat.Color, cat.Age).Scan(&id)
+	err := p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		if err := p.reserveQuota(tx, 1); err != nil {
+			return err
+		}
+		if err := tx.QueryRow(insertCatQuery, append(catArgs(cat), p.tenant)...).Scan(&id); err != nil {
+			return catError(err)
+		}
+		cat.ID, cat.Status = id, StatusIntake
+		return p.writeChange(tx, CatCreated, id, &cat)
+	})
 	if err != nil {
 		return "", err
 	}
-
 	return id, nil
 }
 
 func (p *PostGres) Select(id string) ([]byte, error) {
 	var cat Cat
-	query := `SELECT name, color, age FROM cats WHERE id=$1`
-	err := p.database.QueryRow(query, id).Scan(&cat.Name, &cat.Color, &cat.Age)
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		query := `SELECT ` + catFields + ` FROM cats WHERE id=$1 AND tenant_id=$2`
+		return tx.QueryRow(query, id, p.tenant).Scan(catDest(&cat)...)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -88,20 +170,26 @@ func (p *PostGres) Select(id string) ([]byte, error) {
 }
 
 func (p *PostGres) SelectAll(count int, start int) ([]byte, error) {
-	rows, err := p.database.Query(`SELECT id, name, color, age FROM cats LIMIT $1 OFFSET $2`, count, start)
-	if err != nil {
-		return nil, err
-	}
-	defer func() { _ = rows.Close() }()
-
 	var cats []Cat
-	for rows.Next() {
-		var cat Cat
-		err := rows.Scan(&cat.ID, &cat.Name, &cat.Color, &cat.Age)
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT `+catColumns+` FROM cats WHERE tenant_id=$3 LIMIT $1 OFFSET $2`,
+			count, start, p.tenant)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		cats = append(cats, cat)
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			cat, err := scanCat(rows)
+			if err != nil {
+				return err
+			}
+			cats = append(cats, cat)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	if len(cats) == 0 {
@@ -116,14 +204,28 @@ func (p *PostGres) Update(id string, b []byte) error {
 		return err
 	}
 
-	query := `UPDATE cats SET name=$1, color=$2, age=$3 WHERE id=$4`
-	_, err := p.database.Exec(query, cat.Name, cat.Color, cat.Age, id)
-	return err
+	cat.Normalize(time.Now())
+
+	return p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		query := `UPDATE cats SET name=$1, color=$2, age=$3, breed=$4, birth_date=nullif($5, '')::date, sex=$6,
+neutered=$7, weight_kg=$8, microchip=nullif($9, ''), tags=coalesce($10::text[], '{}') WHERE id=$11 AND tenant_id=$12`
+		result, err := tx.Exec(query, append(catArgs(cat), id, p.tenant)...)
+		if err := affected(result, catError(err)); err != nil {
+			return err
+		}
+		cat.ID = id
+		return p.writeChange(tx, CatUpdated, id, &cat)
+	})
 }
 
 func (p *PostGres) Delete(id string) error {
-	_, err := p.database.Exec("DELETE FROM cats where id=$1", id)
-	return err
+	return p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		result, err := tx.Exec("DELETE FROM cats where id=$1 AND tenant_id=$2", id, p.tenant)
+		if err := affected(result, err); err != nil {
+			return err
+		}
+		return p.writeChange(tx, CatDeleted, id, nil)
+	})
 }
 
 func (p *PostGres) Status() error {
@@ -134,6 +236,11 @@ func (p *PostGres) Status() error {
 	return nil
 }
 
+// StatusContext pings the database, giving up once ctx is done.
+func (p *PostGres) StatusContext(ctx context.Context) error {
+	return p.database.PingContext(ctx)
+}
+
 func (p *PostGres) Purge(table string) error {
 	if _, err := p.database.Exec(fmt.Sprintf("DELETE FROM %s", table)); err != nil {
 		return fmt.Errorf("Error purging %s table: %v", table, err)
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// streamBatchSize is the number of rows fetched from the cursor at a time.
+const streamBatchSize = 500
+
+// Stream calls fn for every cat selected by the filter, reading them in batches from a
+// server side cursor. Returning an error from fn stops the stream and returns that error.
+func (p *PostGres) Stream(ctx context.Context, f Filter, fn func(Cat) error) error {
+	return p.inTenant(ctx, &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		return p.stream(ctx, tx, f, fn)
+	})
+}
+
+func (p *PostGres) stream(ctx context.Context, tx *sql.Tx, f Filter, fn func(Cat) error) error {
+	where, args := f.where()
+	args = append(args, p.tenant)
+	if where == "" {
+		where = " WHERE "
+	} else {
+		where += " AND "
+	}
+	where += fmt.Sprintf("tenant_id = $%d", len(args))
+	declare := `DECLARE cats_stream NO SCROLL CURSOR FOR SELECT ` + catColumns + ` FROM cats` + where + ` ORDER BY id`
+	if _, err := tx.ExecContext(ctx, declare, args...); err != nil {
+		return err
+	}
+
+	fetch := fmt.Sprintf(`FETCH FORWARD %d FROM cats_stream`, streamBatchSize)
+	for {
+		rows, err := tx.QueryContext(ctx, fetch)
+		if err != nil {
+			return err
+		}
+
+		n := 0
+		for rows.Next() {
+			n++
+			cat, err := scanCat(rows)
+			if err != nil {
+				_ = rows.Close()
+				return err
+			}
+			if err := fn(cat); err != nil {
+				_ = rows.Close()
+				return err
+			}
+		}
+		if err := rows.Err(); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		_ = rows.Close()
+
+		if n < streamBatchSize {
+			return nil
+		}
+	}
+}
+
+// InsertBatch inserts every cat in a single transaction.
+func (p *PostGres) InsertBatch(cats []Cat) ([]string, error) {
+	ids := make([]string, 0, len(cats))
+	err := p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		if err := p.reserveQuota(tx, len(cats)); err != nil {
+			return err
+		}
+		stmt, err := tx.Prepare(insertCatQuery)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = stmt.Close() }()
+
+		now := time.Now()
+		for _, cat := range cats {
+			cat.Normalize(now)
+			var id string
+			if err := stmt.QueryRow(append(catArgs(cat), p.tenant)...).Scan(&id); err != nil {
+				return catError(err)
+			}
+			cat.ID = id
+			if err := p.writeChange(tx, CatCreated, id, &cat); err != nil {
+				return err
+			}
+			ids = append(ids, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SelectMany selects every cat with one of the ids, in a single query.
+func (p *PostGres) SelectMany(ids []string) ([]Cat, error) {
+	var cats []Cat
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT `+catColumns+` FROM cats WHERE id = ANY($1::uuid[]) AND tenant_id=$2`,
+			pq.Array(ids), p.tenant)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			cat, err := scanCat(rows)
+			if err != nil {
+				return err
+			}
+			cats = append(cats, cat)
+		}
+		return rows.Err()
+	})
+	return cats, err
+}
@@ -0,0 +1,163 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+)
+
+// createTenantsQuery creates tenants, assigns every existing cat to the default tenant and
+// restricts transactions scoped to a tenant to its cats, as a backstop to the tenant
+// conditions of every query. Superusers and roles with BYPASSRLS are not restricted by row
+// level security, forced or not, so the backstop only holds when connecting as another role.
+const createTenantsQuery string = `
+CREATE TABLE IF NOT EXISTS tenants (
+id uuid PRIMARY KEY DEFAULT uuid_generate_v4 (),
+name TEXT NOT NULL UNIQUE,
+max_cats INT NOT NULL DEFAULT 0,
+key_hash TEXT UNIQUE,
+created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+INSERT INTO tenants (id, name) VALUES ('` + DefaultTenant + `', 'default') ON CONFLICT DO NOTHING;
+ALTER TABLE cats ADD COLUMN IF NOT EXISTS tenant_id uuid NOT NULL DEFAULT '` + DefaultTenant + `' REFERENCES tenants (id);
+CREATE INDEX IF NOT EXISTS cats_tenant ON cats (tenant_id);
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS tenant_id uuid NOT NULL DEFAULT '` + DefaultTenant + `';
+ALTER TABLE cats ENABLE ROW LEVEL SECURITY;
+ALTER TABLE cats FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS cats_tenant ON cats;
+CREATE POLICY cats_tenant ON cats
+USING (tenant_id::text = coalesce(nullif(current_setting('cats.tenant_id', true), ''), tenant_id::text));
+CREATE OR REPLACE FUNCTION notify_cats_change() RETURNS trigger AS $$
+DECLARE
+changed cats%ROWTYPE;
+BEGIN
+IF TG_OP = 'DELETE' THEN
+changed := OLD;
+ELSE
+changed := NEW;
+END IF;
+PERFORM pg_notify('` + ChangesChannel + `', json_build_object(
+'op', TG_OP, 'id', changed.id, 'tenant', changed.tenant_id, 'origin', current_setting('application_name'))::text);
+RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;`
+
+const tenantColumns = `id, name, max_cats, created_at`
+
+// ForTenant returns storage scoped to the tenant, sharing the connections of p.
+func (p *PostGres) ForTenant(id string) Storage {
+	scoped := *p
+	scoped.tenant = id
+	return &scoped
+}
+
+// bypassesRowSecurity reports whether the role connected as is exempt from row level
+// security, leaving tenants apart only by the tenant conditions of queries.
+func (p *PostGres) bypassesRowSecurity() (bool, error) {
+	var bypass bool
+	err := p.database.QueryRow(`SELECT rolsuper OR rolbypassrls FROM pg_roles WHERE rolname = current_user`).Scan(&bypass)
+	return bypass, err
+}
+
+// inTenant runs fn in a transaction scoped to the tenant of the storage, which row level
+// security restricts to the cats of the tenant.
+func (p *PostGres) inTenant(ctx context.Context, opts *sql.TxOptions, fn func(tx *sql.Tx) error) error {
+	tx, err := p.database.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `SELECT set_config('cats.tenant_id', $1, true)`, p.tenant); err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// reserveQuota fails with a QuotaError unless n more cats fit in the quota of the tenant.
+// The tenant is locked until the transaction ends, so concurrent inserts are counted one
+// after the other.
+func (p *PostGres) reserveQuota(tx *sql.Tx, n int) error {
+	var maxCats int
+	if err := tx.QueryRow(`SELECT max_cats FROM tenants WHERE id=$1 FOR UPDATE`, p.tenant).Scan(&maxCats); err != nil {
+		return err
+	}
+	if maxCats <= 0 {
+		return nil
+	}
+	var count int
+	if err := tx.QueryRow(`SELECT count(*) FROM cats WHERE tenant_id=$1`, p.tenant).Scan(&count); err != nil {
+		return err
+	}
+	if count+n > maxCats {
+		return QuotaError{MaxCats: maxCats}
+	}
+	return nil
+}
+
+// InsertTenant stores a tenant authenticated by the api key with the given hash, returning
+// its id.
+func (p *PostGres) InsertTenant(t Tenant, keyHash string) (string, error) {
+	var id string
+	err := p.database.QueryRow(`INSERT INTO tenants (name, max_cats, key_hash) VALUES ($1, $2, $3) RETURNING id`,
+		t.Name, t.MaxCats, keyHash).Scan(&id)
+	if e, ok := err.(*pq.Error); ok && e.Code == "23505" {
+		return "", ErrTenantExists
+	}
+	return id, err
+}
+
+// SelectTenant selects a tenant, returning sql.ErrNoRows when there is none with the id.
+func (p *PostGres) SelectTenant(id string) (Tenant, error) {
+	return scanTenant(p.database.QueryRow(`SELECT `+tenantColumns+` FROM tenants WHERE id=$1`, id))
+}
+
+// SelectTenantByKey selects the tenant authenticated by the api key with the given hash.
+func (p *PostGres) SelectTenantByKey(keyHash string) (Tenant, error) {
+	return scanTenant(p.database.QueryRow(`SELECT `+tenantColumns+` FROM tenants WHERE key_hash=$1`, keyHash))
+}
+
+// SelectTenants selects every tenant, oldest first.
+func (p *PostGres) SelectTenants() ([]Tenant, error) {
+	rows, err := p.database.Query(`SELECT ` + tenantColumns + ` FROM tenants ORDER BY created_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	tenants := []Tenant{}
+	for rows.Next() {
+		t, err := scanTenant(rows)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, t)
+	}
+	return tenants, rows.Err()
+}
+
+// UpdateTenantQuota sets the quota of a tenant, returning sql.ErrNoRows when there is none
+// with the id.
+func (p *PostGres) UpdateTenantQuota(id string, maxCats int) error {
+	result, err := p.database.Exec(`UPDATE tenants SET max_cats=$2 WHERE id=$1`, id, maxCats)
+	if err != nil {
+		return err
+	}
+	if n, err := result.RowsAffected(); err != nil || n == 0 {
+		if err == nil {
+			err = sql.ErrNoRows
+		}
+		return err
+	}
+	return nil
+}
+
+func scanTenant(row scanner) (Tenant, error) {
+	var t Tenant
+	err := row.Scan(&t.ID, &t.Name, &t.MaxCats, &t.CreatedAt)
+	return t, err
+}
@@ -0,0 +1,233 @@
+package model
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/lib/pq"
+	uuid "github.com/satori/go.uuid"
+)
+
+const createWebhooksQuery string = `
+CREATE TABLE IF NOT EXISTS webhooks (
+id uuid PRIMARY KEY DEFAULT uuid_generate_v4 (),
+url TEXT NOT NULL,
+events TEXT[] NOT NULL,
+secret TEXT NOT NULL,
+created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+id uuid PRIMARY KEY DEFAULT uuid_generate_v4 (),
+webhook_id uuid NOT NULL REFERENCES webhooks (id) ON DELETE CASCADE,
+event_id TEXT NOT NULL,
+event_type TEXT NOT NULL,
+payload JSONB NOT NULL,
+status TEXT NOT NULL,
+attempts INT NOT NULL DEFAULT 0,
+next_attempt_at TIMESTAMPTZ NOT NULL,
+last_attempt_at TIMESTAMPTZ,
+last_status INT NOT NULL DEFAULT 0,
+last_error TEXT NOT NULL DEFAULT '',
+created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_due ON webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS webhook_deliveries_log ON webhook_deliveries (webhook_id, created_at);`
+
+// createWebhookTenantsQuery scopes webhooks and their deliveries to tenants the same way as
+// cats, assigning existing ones to the default tenant.
+const createWebhookTenantsQuery string = `
+ALTER TABLE webhooks ADD COLUMN IF NOT EXISTS tenant_id uuid NOT NULL DEFAULT '` + DefaultTenant + `' REFERENCES tenants (id);
+CREATE INDEX IF NOT EXISTS webhooks_tenant ON webhooks (tenant_id, created_at);
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS tenant_id uuid NOT NULL DEFAULT '` + DefaultTenant + `' REFERENCES tenants (id);
+ALTER TABLE webhooks ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhooks FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS webhooks_tenant ON webhooks;
+CREATE POLICY webhooks_tenant ON webhooks
+USING (tenant_id::text = coalesce(nullif(current_setting('cats.tenant_id', true), ''), tenant_id::text));
+ALTER TABLE webhook_deliveries ENABLE ROW LEVEL SECURITY;
+ALTER TABLE webhook_deliveries FORCE ROW LEVEL SECURITY;
+DROP POLICY IF EXISTS webhook_deliveries_tenant ON webhook_deliveries;
+CREATE POLICY webhook_deliveries_tenant ON webhook_deliveries
+USING (tenant_id::text = coalesce(nullif(current_setting('cats.tenant_id', true), ''), tenant_id::text));`
+
+const deliveryColumns = `id, tenant_id, webhook_id, event_id, event_type, payload, status, attempts, next_attempt_at,
+last_attempt_at, last_status, last_error, created_at`
+
+// webhookEvent is the payload of a delivery, shaped like the events published by the server.
+type webhookEvent struct {
+	ID     string    `json:"id"`
+	Type   string    `json:"type"`
+	CatID  string    `json:"catId"`
+	Tenant string    `json:"tenant,omitempty"`
+	Cat    *Cat      `json:"cat,omitempty"`
+	Time   time.Time `json:"time"`
+}
+
+// queueDeliveries queues a delivery of the event of a change to every webhook of the tenant
+// subscribed to its type, within the transaction making the change. A nil cat is sent for
+// deletions.
+func (p *PostGres) queueDeliveries(tx execer, eventType string, id string, cat *Cat) error {
+	now := time.Now().UTC()
+	e := webhookEvent{ID: uuid.NewV4().String(), Type: eventType, CatID: id, Tenant: p.tenant, Cat: cat, Time: now}
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec(`INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at, tenant_id)
+SELECT id, $1, $2, $3, $4, $5, tenant_id FROM webhooks WHERE tenant_id=$6 AND $2 = ANY(events)`,
+		e.ID, eventType, payload, DeliveryPending, now, p.tenant)
+	return err
+}
+
+// InsertWebhook stores a webhook of the tenant, returning its id.
+func (p *PostGres) InsertWebhook(w Webhook) (string, error) {
+	var id string
+	err := p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		return tx.QueryRow(`INSERT INTO webhooks (url, events, secret, tenant_id) VALUES ($1, $2, $3, $4) RETURNING id`,
+			w.URL, pq.Array(w.Events), w.Secret, p.tenant).Scan(&id)
+	})
+	return id, err
+}
+
+// SelectWebhook selects a webhook of the tenant, returning sql.ErrNoRows when there is none
+// with the id.
+func (p *PostGres) SelectWebhook(id string) (Webhook, error) {
+	w := Webhook{ID: id}
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		return tx.QueryRow(`SELECT url, events, secret, created_at FROM webhooks WHERE id=$1 AND tenant_id=$2`, id, p.tenant).
+			Scan(&w.URL, pq.Array(&w.Events), &w.Secret, &w.CreatedAt)
+	})
+	return w, err
+}
+
+// SelectWebhooks selects every webhook of the tenant, oldest first.
+func (p *PostGres) SelectWebhooks() ([]Webhook, error) {
+	webhooks := []Webhook{}
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		rows, err := tx.Query(`SELECT id, url, events, secret, created_at FROM webhooks WHERE tenant_id=$1 ORDER BY created_at`,
+			p.tenant)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = rows.Close() }()
+
+		for rows.Next() {
+			var w Webhook
+			if err := rows.Scan(&w.ID, &w.URL, pq.Array(&w.Events), &w.Secret, &w.CreatedAt); err != nil {
+				return err
+			}
+			webhooks = append(webhooks, w)
+		}
+		return rows.Err()
+	})
+	return webhooks, err
+}
+
+// DeleteWebhook deletes a webhook of the tenant along with its deliveries.
+func (p *PostGres) DeleteWebhook(id string) error {
+	return p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`DELETE FROM webhooks WHERE id=$1 AND tenant_id=$2`, id, p.tenant)
+		return err
+	})
+}
+
+// InsertDeliveries queues deliveries to webhooks of the tenant in a single transaction.
+func (p *PostGres) InsertDeliveries(deliveries []Delivery) error {
+	return p.inTenant(context.Background(), nil, func(tx *sql.Tx) error {
+		stmt, err := tx.Prepare(`INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, status, next_attempt_at, tenant_id)
+SELECT id, $2, $3, $4, $5, $6, tenant_id FROM webhooks WHERE id=$1 AND tenant_id=$7 RETURNING id, tenant_id, created_at`)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = stmt.Close() }()
+
+		for i, d := range deliveries {
+			if err := stmt.QueryRow(d.WebhookID, d.EventID, d.EventType, []byte(d.Payload), d.Status, d.NextAttempt, p.tenant).
+				Scan(&deliveries[i].ID, &deliveries[i].TenantID, &deliveries[i].CreatedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// SelectDelivery selects a delivery of the tenant, returning sql.ErrNoRows when there is none
+// with the id.
+func (p *PostGres) SelectDelivery(id string) (Delivery, error) {
+	var d Delivery
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		var err error
+		d, err = scanDelivery(tx.QueryRow(`SELECT `+deliveryColumns+` FROM webhook_deliveries WHERE id=$1 AND tenant_id=$2`,
+			id, p.tenant))
+		return err
+	})
+	return d, err
+}
+
+// SelectDeliveries selects the latest deliveries of a webhook of the tenant, newest first.
+func (p *PostGres) SelectDeliveries(webhookID string, limit int) ([]Delivery, error) {
+	var deliveries []Delivery
+	err := p.inTenant(context.Background(), &sql.TxOptions{ReadOnly: true}, func(tx *sql.Tx) error {
+		var err error
+		deliveries, err = queryDeliveries(tx, `SELECT `+deliveryColumns+` FROM webhook_deliveries
+WHERE webhook_id=$1 AND tenant_id=$3 ORDER BY created_at DESC LIMIT $2`, webhookID, limit, p.tenant)
+		return err
+	})
+	return deliveries, err
+}
+
+// ClaimDeliveries postpones the next attempt of the pending deliveries due by now by lease,
+// skipping rows claimed concurrently, and returns them. Deliveries are claimed whatever
+// their tenant, as a single dispatcher attempts them all.
+func (p *PostGres) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]Delivery, error) {
+	return queryDeliveries(p.database, `UPDATE webhook_deliveries SET next_attempt_at=$2 WHERE id IN (
+SELECT id FROM webhook_deliveries WHERE status='pending' AND next_attempt_at <= $1
+ORDER BY next_attempt_at LIMIT $3 FOR UPDATE SKIP LOCKED)
+RETURNING `+deliveryColumns, now, now.Add(lease), limit)
+}
+
+// UpdateDelivery records the outcome of the latest attempt of a delivery, whatever its tenant.
+func (p *PostGres) UpdateDelivery(d Delivery) error {
+	_, err := p.database.Exec(`UPDATE webhook_deliveries SET status=$2, attempts=$3, next_attempt_at=$4,
+last_attempt_at=$5, last_status=$6, last_error=$7 WHERE id=$1`,
+		d.ID, d.Status, d.Attempts, d.NextAttempt, d.LastAttempt, d.LastStatus, d.LastError)
+	return err
+}
+
+// querier is implemented by both the database and its transactions.
+type querier interface {
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+}
+
+func queryDeliveries(q querier, query string, args ...interface{}) ([]Delivery, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	deliveries := []Delivery{}
+	for rows.Next() {
+		d, err := scanDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	return deliveries, rows.Err()
+}
+
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanDelivery(row scanner) (Delivery, error) {
+	var d Delivery
+	var payload []byte
+	err := row.Scan(&d.ID, &d.TenantID, &d.WebhookID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts,
+		&d.NextAttempt, &d.LastAttempt, &d.LastStatus, &d.LastError, &d.CreatedAt)
+	d.Payload = payload
+	return d, err
+}
@@ -0,0 +1,28 @@
+package model
+
+// Breed is a breed of cat known to the reference data, by its canonical name and the other
+// names it is written as.
+type Breed struct {
+	Name    string   `json:"name" xml:"name"`
+	Aliases []string `json:"aliases" xml:"alias"`
+}
+
+// Color is a color of the palette known to the reference data, by its canonical name, the
+// other names it is written as and a hex code to display it with.
+type Color struct {
+	Name    string   `json:"name" xml:"name"`
+	Hex     string   `json:"hex" xml:"hex"`
+	Aliases []string `json:"aliases" xml:"alias"`
+}
+
+// ReferenceStore is implemented by storage backends able to persist the reference data cats
+// are normalised against, which is shared by every tenant.
+type ReferenceStore interface {
+	// SeedReference stores the breeds and colors not stored yet, leaving those already
+	// stored as they are.
+	SeedReference(breeds []Breed, colors []Color) error
+	// SelectBreeds selects every breed, by name.
+	SelectBreeds() ([]Breed, error)
+	// SelectColors selects every color, by name.
+	SelectColors() ([]Color, error)
+}
@@ -0,0 +1,77 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: model/reference.go
+
+// Package model is a generated GoMock package.
+package model
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockReferenceStore is a mock of ReferenceStore interface
+type MockReferenceStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockReferenceStoreMockRecorder
+}
+
+// MockReferenceStoreMockRecorder is the mock recorder for MockReferenceStore
+type MockReferenceStoreMockRecorder struct {
+	mock *MockReferenceStore
+}
+
+// NewMockReferenceStore creates a new mock instance
+func NewMockReferenceStore(ctrl *gomock.Controller) *MockReferenceStore {
+	mock := &MockReferenceStore{ctrl: ctrl}
+	mock.recorder = &MockReferenceStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockReferenceStore) EXPECT() *MockReferenceStoreMockRecorder {
+	return m.recorder
+}
+
+// SeedReference mocks base method
+func (m *MockReferenceStore) SeedReference(breeds []Breed, colors []Color) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SeedReference", breeds, colors)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SeedReference indicates an expected call of SeedReference
+func (mr *MockReferenceStoreMockRecorder) SeedReference(breeds, colors interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SeedReference", reflect.TypeOf((*MockReferenceStore)(nil).SeedReference), breeds, colors)
+}
+
+// SelectBreeds mocks base method
+func (m *MockReferenceStore) SelectBreeds() ([]Breed, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectBreeds")
+	ret0, _ := ret[0].([]Breed)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectBreeds indicates an expected call of SelectBreeds
+func (mr *MockReferenceStoreMockRecorder) SelectBreeds() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectBreeds", reflect.TypeOf((*MockReferenceStore)(nil).SelectBreeds))
+}
+
+// SelectColors mocks base method
+func (m *MockReferenceStore) SelectColors() ([]Color, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectColors")
+	ret0, _ := ret[0].([]Color)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectColors indicates an expected call of SelectColors
+func (mr *MockReferenceStoreMockRecorder) SelectColors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectColors", reflect.TypeOf((*MockReferenceStore)(nil).SelectColors))
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultTenant owns every cat stored without a tenant, such as those stored before tenants
+// were introduced, or while tenancy is disabled.
+const DefaultTenant = "00000000-0000-0000-0000-000000000000"
+
+// ErrTenantExists is returned when creating a tenant with the name of another.
+var ErrTenantExists = errors.New("a tenant with the same name already exists")
+
+// QuotaError is returned when storing cats would take a tenant over its quota.
+type QuotaError struct {
+	MaxCats int
+}
+
+func (e QuotaError) Error() string {
+	return fmt.Sprintf("the tenant's quota of %d cats is reached", e.MaxCats)
+}
+
+// Tenant is an organisation, such as a shelter, whose cats are kept apart from those of
+// every other tenant.
+type Tenant struct {
+	ID   string `json:"id,omitempty"`
+	Name string `json:"name"`
+	// MaxCats is the most cats the tenant may have, 0 meaning there is no limit.
+	MaxCats int `json:"maxCats"`
+	// APIKey authenticates requests made on behalf of the tenant, and is only ever returned
+	// when the tenant is created.
+	APIKey    string    `json:"apiKey,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TenantStore is implemented by storage backends able to persist tenants. Only the hashes
+// of api keys are stored.
+type TenantStore interface {
+	InsertTenant(t Tenant, keyHash string) (string, error)
+	SelectTenant(id string) (Tenant, error)
+	SelectTenantByKey(keyHash string) (Tenant, error)
+	SelectTenants() ([]Tenant, error)
+	UpdateTenantQuota(id string, maxCats int) error
+}
+
+// Tenanted is implemented by storage backends able to scope every query to a tenant.
+type Tenanted interface {
+	ForTenant(id string) Storage
+}
@@ -0,0 +1,144 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: model/tenants.go
+
+// Package model is a generated GoMock package.
+package model
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+)
+
+// MockTenantStore is a mock of TenantStore interface
+type MockTenantStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantStoreMockRecorder
+}
+
+// MockTenantStoreMockRecorder is the mock recorder for MockTenantStore
+type MockTenantStoreMockRecorder struct {
+	mock *MockTenantStore
+}
+
+// NewMockTenantStore creates a new mock instance
+func NewMockTenantStore(ctrl *gomock.Controller) *MockTenantStore {
+	mock := &MockTenantStore{ctrl: ctrl}
+	mock.recorder = &MockTenantStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTenantStore) EXPECT() *MockTenantStoreMockRecorder {
+	return m.recorder
+}
+
+// InsertTenant mocks base method
+func (m *MockTenantStore) InsertTenant(t Tenant, keyHash string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertTenant", t, keyHash)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertTenant indicates an expected call of InsertTenant
+func (mr *MockTenantStoreMockRecorder) InsertTenant(t, keyHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertTenant", reflect.TypeOf((*MockTenantStore)(nil).InsertTenant), t, keyHash)
+}
+
+// SelectTenant mocks base method
+func (m *MockTenantStore) SelectTenant(id string) (Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectTenant", id)
+	ret0, _ := ret[0].(Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectTenant indicates an expected call of SelectTenant
+func (mr *MockTenantStoreMockRecorder) SelectTenant(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectTenant", reflect.TypeOf((*MockTenantStore)(nil).SelectTenant), id)
+}
+
+// SelectTenantByKey mocks base method
+func (m *MockTenantStore) SelectTenantByKey(keyHash string) (Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectTenantByKey", keyHash)
+	ret0, _ := ret[0].(Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectTenantByKey indicates an expected call of SelectTenantByKey
+func (mr *MockTenantStoreMockRecorder) SelectTenantByKey(keyHash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectTenantByKey", reflect.TypeOf((*MockTenantStore)(nil).SelectTenantByKey), keyHash)
+}
+
+// SelectTenants mocks base method
+func (m *MockTenantStore) SelectTenants() ([]Tenant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectTenants")
+	ret0, _ := ret[0].([]Tenant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectTenants indicates an expected call of SelectTenants
+func (mr *MockTenantStoreMockRecorder) SelectTenants() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectTenants", reflect.TypeOf((*MockTenantStore)(nil).SelectTenants))
+}
+
+// UpdateTenantQuota mocks base method
+func (m *MockTenantStore) UpdateTenantQuota(id string, maxCats int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTenantQuota", id, maxCats)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTenantQuota indicates an expected call of UpdateTenantQuota
+func (mr *MockTenantStoreMockRecorder) UpdateTenantQuota(id, maxCats interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTenantQuota", reflect.TypeOf((*MockTenantStore)(nil).UpdateTenantQuota), id, maxCats)
+}
+
+// MockTenanted is a mock of Tenanted interface
+type MockTenanted struct {
+	ctrl     *gomock.Controller
+	recorder *MockTenantedMockRecorder
+}
+
+// MockTenantedMockRecorder is the mock recorder for MockTenanted
+type MockTenantedMockRecorder struct {
+	mock *MockTenanted
+}
+
+// NewMockTenanted creates a new mock instance
+func NewMockTenanted(ctrl *gomock.Controller) *MockTenanted {
+	mock := &MockTenanted{ctrl: ctrl}
+	mock.recorder = &MockTenantedMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockTenanted) EXPECT() *MockTenantedMockRecorder {
+	return m.recorder
+}
+
+// ForTenant mocks base method
+func (m *MockTenanted) ForTenant(id string) Storage {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForTenant", id)
+	ret0, _ := ret[0].(Storage)
+	return ret0
+}
+
+// ForTenant indicates an expected call of ForTenant
+func (mr *MockTenantedMockRecorder) ForTenant(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForTenant", reflect.TypeOf((*MockTenanted)(nil).ForTenant), id)
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Limits of the fields of cats.
+const (
+	MaxBreedLength = 64
+	MaxWeightKg    = 30
+	MaxTags        = 20
+	MaxTagLength   = 32
+)
+
+// microchipPattern matches the ids of microchips, 15 digits for iso microchips and 9 or 10
+// letters and digits for older ones.
+var microchipPattern = regexp.MustCompile(`^[0-9A-Za-z]{9,15}$`)
+
+// FieldError describes a single invalid field of a cat.
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+}
+
+// ValidationError lists every invalid field of a cat.
+type ValidationError []FieldError
+
+func (v ValidationError) Error() string {
+	return "invalid cat: " + v.reasons()
+}
+
+func (v ValidationError) reasons() string {
+	reasons := make([]string, len(v))
+	for i, e := range v {
+		reasons[i] = fmt.Sprintf("%s %s", e.Field, e.Reason)
+	}
+	return strings.Join(reasons, ", ")
+}
+
+// Validate checks that the cat can be stored, returning a ValidationError listing every invalid field.
+func (c Cat) Validate() error {
+	var errs ValidationError
+	if strings.TrimSpace(c.Name) == "" {
+		errs = append(errs, FieldError{Field: "name", Reason: "is required"})
+	}
+	if strings.TrimSpace(c.Color) == "" {
+		errs = append(errs, FieldError{Field: "color", Reason: "is required"})
+	}
+	if c.Age < 0 {
+		errs = append(errs, FieldError{Field: "age", Reason: "must not be negative"})
+	}
+	if len(c.Breed) > MaxBreedLength {
+		errs = append(errs, FieldError{Field: "breed", Reason: fmt.Sprintf("must be at most %d characters", MaxBreedLength)})
+	}
+	if c.BirthDate != "" {
+		if born, err := time.Parse(DateLayout, c.BirthDate); err != nil {
+			errs = append(errs, FieldError{Field: "birthDate", Reason: "must be a date such as 2019-04-01"})
+		} else if born.After(time.Now()) {
+			errs = append(errs, FieldError{Field: "birthDate", Reason: "must not be in the future"})
+		}
+	}
+	switch strings.ToLower(c.Sex) {
+	case "", SexFemale, SexMale:
+	default:
+		errs = append(errs, FieldError{Field: "sex", Reason: "must be female or male"})
+	}
+	if c.WeightKg < 0 || c.WeightKg > MaxWeightKg {
+		errs = append(errs, FieldError{Field: "weightKg", Reason: fmt.Sprintf("must be between 0 and %d", MaxWeightKg)})
+	}
+	if c.Microchip != "" && !microchipPattern.MatchString(c.Microchip) {
+		errs = append(errs, FieldError{Field: "microchip", Reason: "must be 9 to 15 letters or digits"})
+	}
+	if len(c.Tags) > MaxTags {
+		errs = append(errs, FieldError{Field: "tags", Reason: fmt.Sprintf("must be at most %d tags", MaxTags)})
+	}
+	for _, tag := range c.Tags {
+		if len(tag) > MaxTagLength {
+			errs = append(errs, FieldError{Field: "tags", Reason: fmt.Sprintf("must each be at most %d characters", MaxTagLength)})
+			break
+		}
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package model
+
+import (
+	"time"
+
+	json "github.com/json-iterator/go"
+)
+
+// Delivery statuses. Pending deliveries are attempted until they succeed, or are dead
+// lettered once they run out of attempts.
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryDead      = "dead"
+)
+
+// Webhook is a subscription delivering the events of the listed types to a url.
+type Webhook struct {
+	ID     string   `json:"id,omitempty" xml:"id,omitempty"`
+	URL    string   `json:"url" xml:"url"`
+	Events []string `json:"events" xml:"event"`
+	// Secret signs every delivery, and is only ever returned when the webhook is created.
+	Secret    string    `json:"secret,omitempty" xml:"secret,omitempty"`
+	CreatedAt time.Time `json:"createdAt" xml:"createdAt"`
+}
+
+// Delivery is an event queued for, or delivered to, a webhook, along with the outcome
+// of its latest attempt.
+type Delivery struct {
+	ID          string          `json:"id" xml:"id"`
+	WebhookID   string          `json:"webhookId" xml:"webhookId"`
+	EventID     string          `json:"eventId" xml:"eventId"`
+	EventType   string          `json:"eventType" xml:"eventType"`
+	Payload     json.RawMessage `json:"payload" xml:"payload"`
+	Status      string          `json:"status" xml:"status"`
+	Attempts    int             `json:"attempts" xml:"attempts"`
+	NextAttempt time.Time       `json:"nextAttemptAt" xml:"nextAttemptAt"`
+	LastAttempt *time.Time      `json:"lastAttemptAt,omitempty" xml:"lastAttemptAt,omitempty"`
+	// LastStatus is the http status of the latest response, 0 when there was none.
+	LastStatus int       `json:"lastStatus,omitempty" xml:"lastStatus,omitempty"`
+	LastError  string    `json:"lastError,omitempty" xml:"lastError,omitempty"`
+	CreatedAt  time.Time `json:"createdAt" xml:"createdAt"`
+	// TenantID is the tenant owning the webhook.
+	TenantID string `json:"-" xml:"-"`
+}
+
+// WebhookStore is implemented by storage backends able to persist webhooks and their
+// deliveries, so deliveries survive restarts. Storage scoped to a tenant only sees its
+// webhooks, but claims and updates deliveries whatever their tenant.
+type WebhookStore interface {
+	InsertWebhook(Webhook) (string, error)
+	SelectWebhook(id string) (Webhook, error)
+	SelectWebhooks() ([]Webhook, error)
+	DeleteWebhook(id string) error
+	// InsertDeliveries queues deliveries, such as replays, assigning their ids. Deliveries
+	// of changes to cats are queued by the storage itself, in the transaction making the
+	// change, so none is lost when the process stops right after the change.
+	InsertDeliveries([]Delivery) error
+	SelectDelivery(id string) (Delivery, error)
+	// SelectDeliveries returns the latest deliveries of a webhook, newest first.
+	SelectDeliveries(webhookID string, limit int) ([]Delivery, error)
+	// ClaimDeliveries returns pending deliveries due by now, postponing their next attempt
+	// by lease so no other dispatcher claims them while they are attempted.
+	ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]Delivery, error)
+	// UpdateDelivery records the outcome of an attempt.
+	UpdateDelivery(Delivery) error
+}
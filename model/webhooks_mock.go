@@ -0,0 +1,166 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: model/webhooks.go
+
+// Package model is a generated GoMock package.
+package model
+
+import (
+	gomock "github.com/golang/mock/gomock"
+	reflect "reflect"
+	time "time"
+)
+
+// MockWebhookStore is a mock of WebhookStore interface
+type MockWebhookStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockWebhookStoreMockRecorder
+}
+
+// MockWebhookStoreMockRecorder is the mock recorder for MockWebhookStore
+type MockWebhookStoreMockRecorder struct {
+	mock *MockWebhookStore
+}
+
+// NewMockWebhookStore creates a new mock instance
+func NewMockWebhookStore(ctrl *gomock.Controller) *MockWebhookStore {
+	mock := &MockWebhookStore{ctrl: ctrl}
+	mock.recorder = &MockWebhookStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use
+func (m *MockWebhookStore) EXPECT() *MockWebhookStoreMockRecorder {
+	return m.recorder
+}
+
+// InsertWebhook mocks base method
+func (m *MockWebhookStore) InsertWebhook(arg0 Webhook) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertWebhook", arg0)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InsertWebhook indicates an expected call of InsertWebhook
+func (mr *MockWebhookStoreMockRecorder) InsertWebhook(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertWebhook", reflect.TypeOf((*MockWebhookStore)(nil).InsertWebhook), arg0)
+}
+
+// SelectWebhook mocks base method
+func (m *MockWebhookStore) SelectWebhook(id string) (Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectWebhook", id)
+	ret0, _ := ret[0].(Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectWebhook indicates an expected call of SelectWebhook
+func (mr *MockWebhookStoreMockRecorder) SelectWebhook(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectWebhook", reflect.TypeOf((*MockWebhookStore)(nil).SelectWebhook), id)
+}
+
+// SelectWebhooks mocks base method
+func (m *MockWebhookStore) SelectWebhooks() ([]Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectWebhooks")
+	ret0, _ := ret[0].([]Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectWebhooks indicates an expected call of SelectWebhooks
+func (mr *MockWebhookStoreMockRecorder) SelectWebhooks() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectWebhooks", reflect.TypeOf((*MockWebhookStore)(nil).SelectWebhooks))
+}
+
+// DeleteWebhook mocks base method
+func (m *MockWebhookStore) DeleteWebhook(id string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook
+func (mr *MockWebhookStoreMockRecorder) DeleteWebhook(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockWebhookStore)(nil).DeleteWebhook), id)
+}
+
+// InsertDeliveries mocks base method
+func (m *MockWebhookStore) InsertDeliveries(arg0 []Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InsertDeliveries", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// InsertDeliveries indicates an expected call of InsertDeliveries
+func (mr *MockWebhookStoreMockRecorder) InsertDeliveries(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InsertDeliveries", reflect.TypeOf((*MockWebhookStore)(nil).InsertDeliveries), arg0)
+}
+
+// SelectDelivery mocks base method
+func (m *MockWebhookStore) SelectDelivery(id string) (Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDelivery", id)
+	ret0, _ := ret[0].(Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectDelivery indicates an expected call of SelectDelivery
+func (mr *MockWebhookStoreMockRecorder) SelectDelivery(id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDelivery", reflect.TypeOf((*MockWebhookStore)(nil).SelectDelivery), id)
+}
+
+// SelectDeliveries mocks base method
+func (m *MockWebhookStore) SelectDeliveries(webhookID string, limit int) ([]Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SelectDeliveries", webhookID, limit)
+	ret0, _ := ret[0].([]Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SelectDeliveries indicates an expected call of SelectDeliveries
+func (mr *MockWebhookStoreMockRecorder) SelectDeliveries(webhookID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SelectDeliveries", reflect.TypeOf((*MockWebhookStore)(nil).SelectDeliveries), webhookID, limit)
+}
+
+// ClaimDeliveries mocks base method
+func (m *MockWebhookStore) ClaimDeliveries(now time.Time, lease time.Duration, limit int) ([]Delivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimDeliveries", now, lease, limit)
+	ret0, _ := ret[0].([]Delivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimDeliveries indicates an expected call of ClaimDeliveries
+func (mr *MockWebhookStoreMockRecorder) ClaimDeliveries(now, lease, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimDeliveries", reflect.TypeOf((*MockWebhookStore)(nil).ClaimDeliveries), now, lease, limit)
+}
+
+// UpdateDelivery mocks base method
+func (m *MockWebhookStore) UpdateDelivery(arg0 Delivery) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateDelivery", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateDelivery indicates an expected call of UpdateDelivery
+func (mr *MockWebhookStoreMockRecorder) UpdateDelivery(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateDelivery", reflect.TypeOf((*MockWebhookStore)(nil).UpdateDelivery), arg0)
+}
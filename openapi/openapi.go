@@ -0,0 +1,52 @@
+package openapi
+
+import (
+	"context"
+	"embed"
+	"path"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+// spec is the OpenAPI 3 document describing every route served by cmd/server.
+//
+//go:embed openapi.json
+var spec []byte
+
+// docs is a documentation page rendering spec with swagger-ui.
+//
+//go:embed docs.html
+var docs []byte
+
+// assets are the swagger-ui scripts and styles loaded by docs, served alongside it rather
+// than from a cdn so the page works offline and under a strict content security policy.
+//
+//go:embed swagger-ui/*.js swagger-ui/*.css
+var assets embed.FS
+
+// Spec returns the OpenAPI 3 document as json.
+func Spec() []byte {
+	return spec
+}
+
+// Docs returns the html documentation page.
+func Docs() []byte {
+	return docs
+}
+
+// Asset returns a file loaded by the documentation page, such as swagger-ui.css.
+func Asset(name string) ([]byte, error) {
+	return assets.ReadFile(path.Join("swagger-ui", name))
+}
+
+// Load parses and validates the OpenAPI 3 document.
+func Load() (*openapi3.T, error) {
+	doc, err := openapi3.NewLoader().LoadFromData(spec)
+	if err != nil {
+		return nil, err
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
@@ -0,0 +1,7 @@
+window.onload = function () {
+  window.ui = SwaggerUIBundle({
+    url: "/cats/v1/openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true
+  });
+};
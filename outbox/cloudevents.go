@@ -0,0 +1,53 @@
+package outbox
+
+import (
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/waikco/cats-v1/model"
+)
+
+const (
+	// SpecVersion is the version of the cloudevents specification events conform to.
+	SpecVersion = "1.0"
+	// ContentType is the media type of cloudevents in the structured json encoding.
+	ContentType = "application/cloudevents+json"
+)
+
+// CloudEvent is a cat event in the cloudevents structured json encoding. The subject is the
+// id of the cat, and the data the cat after the change, which is left out for deletions.
+// The tenant owning the cat is given in the tenantid extension attribute.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Subject         string          `json:"subject"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype,omitempty"`
+	Data            json.RawMessage `json:"data,omitempty"`
+	TenantID        string          `json:"tenantid,omitempty"`
+}
+
+// NewCloudEvent describes an outbox event as a cloudevent from source.
+func NewCloudEvent(source string, e model.OutboxEvent) CloudEvent {
+	ce := CloudEvent{
+		SpecVersion: SpecVersion,
+		ID:          e.ID,
+		Source:      source,
+		Type:        e.Type,
+		Subject:     e.CatID,
+		Time:        e.CreatedAt.UTC(),
+		TenantID:    e.TenantID,
+	}
+	if len(e.Data) > 0 {
+		ce.DataContentType = "application/json"
+		ce.Data = e.Data
+	}
+	return ce
+}
+
+// Encode encodes the event in the structured json encoding.
+func (e CloudEvent) Encode() ([]byte, error) {
+	return json.Marshal(e)
+}
@@ -0,0 +1,62 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// producer produces records to kafka, as implemented by kgo.Client.
+type producer interface {
+	ProduceSync(ctx context.Context, rs ...*kgo.Record) kgo.ProduceResults
+	Close()
+}
+
+// Kafka produces events to a topic, keyed by the id of their cat so events about a cat are
+// kept in order within a partition.
+type Kafka struct {
+	producer producer
+	topic    string
+}
+
+// NewKafka creates a publisher producing to the topic on the kafka cluster the brokers
+// belong to, waiting for every in-sync replica to acknowledge the events.
+func NewKafka(brokers []string, topic string) (*Kafka, error) {
+	if len(brokers) == 0 || topic == "" {
+		return nil, fmt.Errorf("the kafka publisher needs brokers and a topic")
+	}
+	client, err := kgo.NewClient(
+		kgo.SeedBrokers(brokers...),
+		kgo.ClientID("cats-v1-outbox"),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &Kafka{producer: client, topic: topic}, nil
+}
+
+// Publish produces every event, failing unless all were acknowledged.
+func (p *Kafka) Publish(ctx context.Context, events []CloudEvent) error {
+	records := make([]*kgo.Record, len(events))
+	for i, e := range events {
+		b, err := e.Encode()
+		if err != nil {
+			return err
+		}
+		records[i] = &kgo.Record{
+			Topic:   p.topic,
+			Key:     []byte(e.Subject),
+			Value:   b,
+			Headers: []kgo.RecordHeader{{Key: "content-type", Value: []byte(ContentType)}},
+		}
+	}
+	return p.producer.ProduceSync(ctx, records...).FirstErr()
+}
+
+// Close flushes and closes the client.
+func (p *Kafka) Close() error {
+	p.producer.Close()
+	return nil
+}
@@ -0,0 +1,50 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// standInProducer stands in for a kafka cluster, acknowledging records unless failing with err.
+type standInProducer struct {
+	records []*kgo.Record
+	err     error
+}
+
+func (p *standInProducer) ProduceSync(ctx context.Context, rs ...*kgo.Record) kgo.ProduceResults {
+	results := make(kgo.ProduceResults, len(rs))
+	for i, r := range rs {
+		results[i] = kgo.ProduceResult{Record: r, Err: p.err}
+		if p.err == nil {
+			p.records = append(p.records, r)
+		}
+	}
+	return results
+}
+
+func (p *standInProducer) Close() {}
+
+func TestKafka_Publish(t *testing.T) {
+	producer := &standInProducer{}
+	p := &Kafka{producer: producer, topic: "cats"}
+	if err := p.Publish(context.Background(), testEvents()); err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	if len(producer.records) != 2 {
+		t.Fatalf("unxpected number of records: got %d, expected 2", len(producer.records))
+	}
+	r := producer.records[0]
+	expected, _ := testEvents()[0].Encode()
+	if r.Topic != "cats" || string(r.Key) != "c1" || string(r.Value) != string(expected) ||
+		len(r.Headers) != 1 || string(r.Headers[0].Value) != ContentType {
+		t.Errorf("unxpected record: %+v", r)
+	}
+
+	producer.err = errors.New("not enough replicas")
+	if err := p.Publish(context.Background(), testEvents()); err == nil {
+		t.Errorf("unxpected success publishing unacknowledged records")
+	}
+}
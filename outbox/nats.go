@@ -0,0 +1,48 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATS publishes events to a subject suffixed with their type, such as cats.cat.created.
+type NATS struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// NewNATS connects to the nats server at url, reconnecting whenever the connection is lost.
+func NewNATS(url string, subject string) (*NATS, error) {
+	if subject == "" {
+		return nil, fmt.Errorf("the nats publisher needs a subject")
+	}
+	conn, err := nats.Connect(url, nats.Name("cats-v1-outbox"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+	return &NATS{conn: conn, subject: subject}, nil
+}
+
+// Publish publishes every event, then waits for the server to have received them.
+func (p *NATS) Publish(ctx context.Context, events []CloudEvent) error {
+	for _, e := range events {
+		b, err := e.Encode()
+		if err != nil {
+			return err
+		}
+		msg := nats.NewMsg(p.subject + "." + e.Type)
+		msg.Header.Set("Content-Type", ContentType)
+		msg.Data = b
+		if err := p.conn.PublishMsg(msg); err != nil {
+			return err
+		}
+	}
+	return p.conn.FlushWithContext(ctx)
+}
+
+// Close drains the connection.
+func (p *NATS) Close() error {
+	return p.conn.Drain()
+}
@@ -0,0 +1,63 @@
+package outbox
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+// runNATS runs a nats server in process on a random port.
+func runNATS(t *testing.T) *server.Server {
+	t.Helper()
+	s, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: server.RANDOM_PORT, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	go s.Start()
+	if !s.ReadyForConnections(5 * time.Second) {
+		t.Fatalf("unxpected failure to start nats server")
+	}
+	return s
+}
+
+func TestNATS_Publish(t *testing.T) {
+	s := runNATS(t)
+	defer s.Shutdown()
+
+	conn, err := nats.Connect(s.ClientURL())
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	defer conn.Close()
+	sub, err := conn.SubscribeSync("cats.>")
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	_ = conn.Flush()
+
+	p, err := NewNATS(s.ClientURL(), "cats")
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	defer func() { _ = p.Close() }()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := p.Publish(ctx, testEvents()); err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+
+	for _, e := range testEvents() {
+		msg, err := sub.NextMsg(5 * time.Second)
+		if err != nil {
+			t.Fatalf("unxpected error: %v", err)
+		}
+		expected, _ := e.Encode()
+		if msg.Subject != "cats."+e.Type || string(msg.Data) != string(expected) ||
+			msg.Header.Get("Content-Type") != ContentType {
+			t.Errorf("unxpected message on %s: %s", msg.Subject, msg.Data)
+		}
+	}
+}
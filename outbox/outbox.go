@@ -0,0 +1,155 @@
+// Package outbox relays the cat events written to the outbox to a message broker. Events are
+// only deleted from the outbox once published, so every event is published at least once.
+package outbox
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/waikco/cats-v1/model"
+)
+
+const (
+	DefaultSource       = "/cats/v1"
+	DefaultBatchSize    = 100
+	DefaultPollInterval = time.Second
+	DefaultTimeout      = 10 * time.Second
+	DefaultMinBackoff   = time.Second
+	DefaultMaxBackoff   = 5 * time.Minute
+)
+
+// Options configures the relay, values of 0 using the defaults.
+type Options struct {
+	// Source is the source of the published cloudevents.
+	Source string
+	// BatchSize is the number of events published at once.
+	BatchSize int
+	// PollInterval is how often the outbox is looked at once emptied.
+	PollInterval time.Duration
+	// Timeout bounds the publishing of every batch.
+	Timeout time.Duration
+	// MinBackoff and MaxBackoff bound the exponential backoff between attempts to publish.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.Source == "" {
+		o.Source = DefaultSource
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = DefaultBatchSize
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultPollInterval
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = DefaultMinBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultMaxBackoff
+	}
+	return o
+}
+
+// Relay publishes the events in the outbox, oldest first. Events failing to publish are
+// retried with exponential backoff, and are published again should the relay stop between
+// publishing and deleting them, so consumers must tell duplicates apart by their id.
+type Relay struct {
+	store     model.OutboxStore
+	publisher Publisher
+	opts      Options
+	now       func() time.Time
+}
+
+// NewRelay creates a relay publishing the events in store with publisher.
+func NewRelay(store model.OutboxStore, publisher Publisher, opts Options) *Relay {
+	return &Relay{store: store, publisher: publisher, opts: opts.withDefaults(), now: time.Now}
+}
+
+// Run publishes the events in the outbox every poll interval until the context is done,
+// then closes the publisher.
+func (r *Relay) Run(ctx context.Context) {
+	defer func() {
+		if err := r.publisher.Close(); err != nil {
+			log.Error().Msgf("unable to close outbox publisher: %v", err)
+		}
+	}()
+	ticker := time.NewTicker(r.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.Flush(ctx); err != nil {
+			log.Error().Msgf("unable to publish outbox events: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush publishes batches of events until the outbox is empty, or a batch fails to
+// publish, returning how many events were published.
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	n := 0
+	for {
+		// claimed events are leased for long enough to publish and delete them
+		batch, err := r.store.ClaimOutbox(r.now(), r.opts.Timeout+time.Minute, r.opts.BatchSize)
+		if err != nil || len(batch) == 0 {
+			return n, err
+		}
+		if err := r.publish(ctx, batch); err != nil {
+			return n, err
+		}
+		n += len(batch)
+		if len(batch) < r.opts.BatchSize {
+			return n, nil
+		}
+	}
+}
+
+// publish publishes a batch and deletes it from the outbox, postponing it with backoff after
+// a failure.
+func (r *Relay) publish(ctx context.Context, batch []model.OutboxEvent) error {
+	ids := make([]string, len(batch))
+	events := make([]CloudEvent, len(batch))
+	attempts := 0
+	for i, e := range batch {
+		ids[i] = e.ID
+		events[i] = NewCloudEvent(r.opts.Source, e)
+		if e.Attempts > attempts {
+			attempts = e.Attempts
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.opts.Timeout)
+	defer cancel()
+	if err := r.publisher.Publish(ctx, events); err != nil {
+		retryAt := r.now().Add(r.backoff(attempts))
+		log.Warn().Msgf("unable to publish %d outbox events, retrying at %s: %v", len(batch), retryAt, err)
+		if err := r.store.FailOutbox(ids, err.Error(), retryAt); err != nil {
+			log.Error().Msgf("unable to record failure to publish outbox events: %v", err)
+		}
+		return err
+	}
+	return r.store.DeleteOutbox(ids)
+}
+
+// backoff returns how long to wait after the given number of attempts, doubling with every
+// attempt, with jitter between half and all of it.
+func (r *Relay) backoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	b := r.opts.MinBackoff << uint(attempts-1)
+	if b <= 0 || b > r.opts.MaxBackoff {
+		b = r.opts.MaxBackoff
+	}
+	return b/2 + time.Duration(rand.Int63n(int64(b/2)+1))
+}
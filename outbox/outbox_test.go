@@ -0,0 +1,107 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/model"
+)
+
+var now = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+// recorder is a publisher recording the events it publishes, or failing with err.
+type recorder struct {
+	published []CloudEvent
+	err       error
+	closed    bool
+}
+
+func (r *recorder) Publish(ctx context.Context, events []CloudEvent) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.published = append(r.published, events...)
+	return nil
+}
+
+func (r *recorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+func newRelay(store model.OutboxStore, publisher Publisher) *Relay {
+	r := NewRelay(store, publisher, Options{BatchSize: 2, MinBackoff: time.Minute, MaxBackoff: 10 * time.Minute})
+	r.now = func() time.Time { return now }
+	return r
+}
+
+func TestRelay_Flush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := model.NewMockOutboxStore(ctrl)
+	gomock.InOrder(
+		store.EXPECT().ClaimOutbox(now, gomock.Any(), 2).Return([]model.OutboxEvent{
+			{ID: "e1", Type: model.CatCreated, CatID: "c1", Data: []byte(`{"id":"c1"}`), CreatedAt: now},
+			{ID: "e2", Type: model.CatUpdated, CatID: "c1", Data: []byte(`{"id":"c1"}`), CreatedAt: now},
+		}, nil),
+		store.EXPECT().DeleteOutbox([]string{"e1", "e2"}).Return(nil),
+		store.EXPECT().ClaimOutbox(now, gomock.Any(), 2).Return([]model.OutboxEvent{
+			{ID: "e3", Type: model.CatDeleted, CatID: "c1", CreatedAt: now},
+		}, nil),
+		store.EXPECT().DeleteOutbox([]string{"e3"}).Return(nil),
+	)
+
+	publisher := &recorder{}
+	n, err := newRelay(store, publisher).Flush(context.Background())
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	if n != 3 || len(publisher.published) != 3 {
+		t.Fatalf("unxpected number of published events: got %d and %d, expected 3", n, len(publisher.published))
+	}
+	if e := publisher.published[2]; e.ID != "e3" || e.Type != model.CatDeleted || e.Subject != "c1" ||
+		e.Source != DefaultSource || e.Data != nil || e.DataContentType != "" {
+		t.Errorf("unxpected event: %+v", e)
+	}
+}
+
+func TestRelay_Flush_Failure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := model.NewMockOutboxStore(ctrl)
+	store.EXPECT().ClaimOutbox(now, gomock.Any(), 2).Return([]model.OutboxEvent{
+		{ID: "e1", Type: model.CatCreated, CatID: "c1", CreatedAt: now, Attempts: 1},
+		{ID: "e2", Type: model.CatUpdated, CatID: "c1", CreatedAt: now, Attempts: 2},
+	}, nil)
+	store.EXPECT().FailOutbox([]string{"e1", "e2"}, "unavailable", gomock.Any()).DoAndReturn(
+		func(ids []string, reason string, retryAt time.Time) error {
+			// the second attempt backs off between half and all of twice the min backoff
+			if retry := retryAt.Sub(now); retry < time.Minute || retry > 2*time.Minute {
+				t.Errorf("unxpected retry in %s", retry)
+			}
+			return nil
+		})
+
+	n, err := newRelay(store, &recorder{err: errors.New("unavailable")}).Flush(context.Background())
+	if err == nil || n != 0 {
+		t.Errorf("unxpected result: got %d, %v, expected the failure to publish", n, err)
+	}
+}
+
+func TestRelay_Run_ClosesPublisher(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := model.NewMockOutboxStore(ctrl)
+	store.EXPECT().ClaimOutbox(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	publisher := &recorder{}
+	newRelay(store, publisher).Run(ctx)
+	if !publisher.closed {
+		t.Errorf("unxpected open publisher")
+	}
+}
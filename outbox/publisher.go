@@ -0,0 +1,100 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/waikco/cats-v1/conf"
+)
+
+// Publishers that may be configured.
+const (
+	PublisherStdout = "stdout"
+	PublisherFile   = "file"
+	PublisherNATS   = "nats"
+	PublisherKafka  = "kafka"
+)
+
+// Publisher publishes events to a message broker. Publish only returns once every event
+// has been accepted by the broker, or fails, in which case some of them may still have
+// been published.
+type Publisher interface {
+	Publish(ctx context.Context, events []CloudEvent) error
+	Close() error
+}
+
+// NewPublisher creates the configured publisher.
+func NewPublisher(config conf.Outbox) (Publisher, error) {
+	var p Publisher
+	var err error
+	switch config.Publisher {
+	case PublisherStdout:
+		p = NewWriter(os.Stdout)
+	case PublisherFile:
+		p, err = NewFile(config.File)
+	case PublisherNATS:
+		p, err = NewNATS(config.NATS.URL, config.NATS.Subject)
+	case PublisherKafka:
+		p, err = NewKafka(config.Kafka.Brokers, config.Kafka.Topic)
+	default:
+		err = fmt.Errorf("unknown outbox publisher %q, use stdout, file, nats or kafka", config.Publisher)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Writer publishes events as lines of json.
+type Writer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriter creates a publisher writing events to w.
+func NewWriter(w io.Writer) *Writer {
+	return &Writer{w: w}
+}
+
+// Publish writes a line for every event.
+func (p *Writer) Publish(ctx context.Context, events []CloudEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, e := range events {
+		b, err := e.Encode()
+		if err != nil {
+			return err
+		}
+		if _, err := p.w.Write(append(b, '\n')); err != nil {
+			return err
+		}
+	}
+	if f, ok := p.w.(*os.File); ok && f != os.Stdout {
+		return f.Sync()
+	}
+	return nil
+}
+
+// Close closes the underlying writer, unless it is stdout.
+func (p *Writer) Close() error {
+	if c, ok := p.w.(io.Closer); ok && p.w != os.Stdout {
+		return c.Close()
+	}
+	return nil
+}
+
+// NewFile creates a publisher appending events to the file at path, synced to disk after
+// every batch.
+func NewFile(path string) (*Writer, error) {
+	if path == "" {
+		return nil, fmt.Errorf("the file publisher needs a file")
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriter(f), nil
+}
@@ -0,0 +1,83 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/waikco/cats-v1/conf"
+	"github.com/waikco/cats-v1/model"
+)
+
+func testEvents() []CloudEvent {
+	created := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	return []CloudEvent{
+		NewCloudEvent("/cats/v1", model.OutboxEvent{ID: "e1", Type: model.CatCreated, CatID: "c1",
+			Data: []byte(`{"id":"c1","name":"tom"}`), CreatedAt: created}),
+		NewCloudEvent("/cats/v1", model.OutboxEvent{ID: "e2", Type: model.CatDeleted, CatID: "c1", CreatedAt: created}),
+	}
+}
+
+const testEventLines = `{"specversion":"1.0","id":"e1","source":"/cats/v1","type":"cat.created","subject":"c1",` +
+	`"time":"2020-01-02T03:04:05Z","datacontenttype":"application/json","data":{"id":"c1","name":"tom"}}
+{"specversion":"1.0","id":"e2","source":"/cats/v1","type":"cat.deleted","subject":"c1","time":"2020-01-02T03:04:05Z"}
+`
+
+func TestWriter_Publish(t *testing.T) {
+	var b bytes.Buffer
+	if err := NewWriter(&b).Publish(context.Background(), testEvents()); err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	if b.String() != testEventLines {
+		t.Errorf("unxpected events: got %s, expected %s", b.String(), testEventLines)
+	}
+}
+
+func TestFile_Publish(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	for i := 0; i < 2; i++ {
+		p, err := NewFile(path)
+		if err != nil {
+			t.Fatalf("unxpected error: %v", err)
+		}
+		if err := p.Publish(context.Background(), testEvents()); err != nil {
+			t.Fatalf("unxpected error: %v", err)
+		}
+		if err := p.Close(); err != nil {
+			t.Fatalf("unxpected error: %v", err)
+		}
+	}
+
+	b, _ := ioutil.ReadFile(path)
+	if string(b) != testEventLines+testEventLines {
+		t.Errorf("unxpected file: got %s, expected the events appended twice", b)
+	}
+}
+
+func TestNewPublisher(t *testing.T) {
+	tests := []struct {
+		description string
+		config      conf.Outbox
+		expectedErr bool
+	}{
+		{description: "stdout", config: conf.Outbox{Publisher: PublisherStdout}},
+		{description: "file without a file", config: conf.Outbox{Publisher: PublisherFile}, expectedErr: true},
+		{description: "kafka without a topic", config: conf.Outbox{Publisher: PublisherKafka,
+			Kafka: conf.Kafka{Brokers: []string{"127.0.0.1:9092"}}}, expectedErr: true},
+		{description: "unknown", config: conf.Outbox{Publisher: "amqp"}, expectedErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			p, err := NewPublisher(tt.config)
+			if (err != nil) != tt.expectedErr {
+				t.Errorf("unxpected error: %v", err)
+			}
+			if p != nil {
+				_ = p.Close()
+			}
+		})
+	}
+}
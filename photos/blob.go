@@ -0,0 +1,57 @@
+package photos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/waikco/cats-v1/conf"
+)
+
+// Blob stores that may be configured.
+const (
+	BlobStoreFS = "fs"
+	BlobStoreS3 = "s3"
+)
+
+// ErrNotFound is returned for blobs that are not stored.
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore stores the files of photos by key. Keys are paths of segments separated by
+// slashes, such as cats/{id}/photos/{id}/original.
+type BlobStore interface {
+	// Put stores size bytes read from r under key, replacing any blob already stored there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Get opens the blob stored under key, which the caller must close.
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete deletes the blob stored under key, succeeding when there is none.
+	Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStore creates the configured blob store.
+func NewBlobStore(config conf.Blob) (BlobStore, error) {
+	switch config.Store {
+	case BlobStoreFS:
+		return NewFS(config.Dir)
+	case BlobStoreS3:
+		return NewS3(config.S3)
+	default:
+		return nil, fmt.Errorf("unknown blob store %q, use fs or s3", config.Store)
+	}
+}
+
+// validKey reports whether key is a relative path of non-empty segments, none of which
+// refer to a parent.
+func validKey(key string) bool {
+	if key == "" || strings.ContainsAny(key, "\\\x00") {
+		return false
+	}
+	for _, segment := range strings.Split(key, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return false
+		}
+	}
+	return true
+}
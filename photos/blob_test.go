@@ -0,0 +1,92 @@
+package photos
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johannesboyne/gofakes3"
+	"github.com/johannesboyne/gofakes3/backend/s3mem"
+	"github.com/waikco/cats-v1/conf"
+)
+
+// testBlobStore checks a blob store stores, replaces and deletes blobs.
+func testBlobStore(t *testing.T, s BlobStore) {
+	t.Helper()
+	ctx := context.Background()
+	const key = "cats/1/photos/2"
+
+	if _, err := s.Get(ctx, key); err != ErrNotFound {
+		t.Errorf("unxpected error getting a missing blob: got %v, expected %v", err, ErrNotFound)
+	}
+	for _, content := range []string{"first", "second"} {
+		if err := s.Put(ctx, key, strings.NewReader(content), int64(len(content)), "image/png"); err != nil {
+			t.Fatalf("unxpected error: %v", err)
+		}
+		r, err := s.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("unxpected error: %v", err)
+		}
+		b, err := ioutil.ReadAll(r)
+		_ = r.Close()
+		if err != nil || string(b) != content {
+			t.Errorf("unxpected blob: got %q (%v), expected %q", b, err, content)
+		}
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	if _, err := s.Get(ctx, key); err != ErrNotFound {
+		t.Errorf("unxpected error getting a deleted blob: got %v, expected %v", err, ErrNotFound)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("unxpected error deleting a missing blob: %v", err)
+	}
+	for _, key := range []string{"", "/cats", "cats/../../etc", "cats//1", `cats\1`} {
+		if err := s.Put(ctx, key, bytes.NewReader(nil), 0, "image/png"); err == nil {
+			t.Errorf("unxpected success putting invalid key %q", key)
+		}
+	}
+}
+
+func TestFS(t *testing.T) {
+	s, err := NewFS(t.TempDir())
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	testBlobStore(t, s)
+}
+
+func TestS3(t *testing.T) {
+	backend := s3mem.New()
+	if err := backend.CreateBucket("cats"); err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	server := httptest.NewServer(gofakes3.New(backend).Server())
+	defer server.Close()
+
+	s, err := NewBlobStore(conf.Blob{
+		Store: BlobStoreS3,
+		S3: conf.S3{
+			Endpoint:  strings.TrimPrefix(server.URL, "http://"),
+			Bucket:    "cats",
+			Region:    "us-east-1",
+			AccessKey: "key",
+			SecretKey: "secret",
+			PathStyle: true,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	testBlobStore(t, s)
+}
+
+func TestNewBlobStore_Unknown(t *testing.T) {
+	if _, err := NewBlobStore(conf.Blob{Store: "ftp"}); err == nil {
+		t.Errorf("unxpected success creating an unknown blob store")
+	}
+}
@@ -0,0 +1,71 @@
+package photos
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif" // registers the decoder of gif photos
+	"image/jpeg"
+	_ "image/png" // registers the decoder of png photos
+	"io"
+	"net/http"
+
+	"golang.org/x/image/draw"
+)
+
+// ContentTypes lists the content types of the photos that may be uploaded.
+var ContentTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+}
+
+// ThumbnailContentType is the content type of every thumbnail.
+const ThumbnailContentType = "image/jpeg"
+
+// MaxPixels is the most pixels a photo may have, which bounds the memory needed to decode it.
+const MaxPixels = 50_000_000
+
+// Sniff returns the content type of a photo from its first bytes, whatever its name or the
+// content type it was uploaded with claim.
+func Sniff(b []byte) string {
+	return http.DetectContentType(b)
+}
+
+// Decode decodes a photo of one of ContentTypes, after checking its dimensions are within
+// MaxPixels.
+func Decode(b []byte) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, fmt.Errorf("photo of %dx%d pixels is over the limit of %d pixels", cfg.Width, cfg.Height, MaxPixels)
+	}
+	img, _, err := image.Decode(bytes.NewReader(b))
+	return img, err
+}
+
+// Thumbnail scales an image down to fit within size by size pixels, keeping its aspect
+// ratio. Images already small enough are copied as they are.
+func Thumbnail(img image.Image, size int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(1, h*size/w)
+		} else {
+			w, h = max(1, w*size/h), size
+		}
+	}
+	thumb := image.NewRGBA(image.Rect(0, 0, w, h))
+	// photos with transparency get a white background, jpeg having no alpha channel
+	draw.Draw(thumb, thumb.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(thumb, thumb.Bounds(), img, b, draw.Over, nil)
+	return thumb
+}
+
+// EncodeThumbnail encodes a thumbnail as a jpeg.
+func EncodeThumbnail(w io.Writer, thumb image.Image) error {
+	return jpeg.Encode(w, thumb, &jpeg.Options{Quality: 85})
+}
@@ -0,0 +1,84 @@
+package photos
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for x := 0; x < w; x++ {
+		for y := 0; y < h; y++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var b bytes.Buffer
+	if err := png.Encode(&b, img); err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	return b.Bytes()
+}
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		description string
+		b           []byte
+		expected    string
+	}{
+		{description: "png", b: testPNG(t, 1, 1), expected: "image/png"},
+		{description: "gif", b: []byte("GIF89a..."), expected: "image/gif"},
+		{description: "text", b: []byte("<html></html>"), expected: "text/html; charset=utf-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			if got := Sniff(tt.b); got != tt.expected {
+				t.Errorf("unxpected content type: got %s, expected %s", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestThumbnail(t *testing.T) {
+	tests := []struct {
+		description    string
+		width, height  int
+		expectedWidth  int
+		expectedHeight int
+	}{
+		{description: "landscape", width: 200, height: 100, expectedWidth: 64, expectedHeight: 32},
+		{description: "portrait", width: 100, height: 200, expectedWidth: 32, expectedHeight: 64},
+		{description: "small", width: 10, height: 20, expectedWidth: 10, expectedHeight: 20},
+		{description: "thin", width: 1000, height: 1, expectedWidth: 64, expectedHeight: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			img, err := Decode(testPNG(t, tt.width, tt.height))
+			if err != nil {
+				t.Fatalf("unxpected error: %v", err)
+			}
+			var b bytes.Buffer
+			if err := EncodeThumbnail(&b, Thumbnail(img, 64)); err != nil {
+				t.Fatalf("unxpected error: %v", err)
+			}
+			cfg, err := jpeg.DecodeConfig(&b)
+			if err != nil {
+				t.Fatalf("unxpected error: %v", err)
+			}
+			if cfg.Width != tt.expectedWidth || cfg.Height != tt.expectedHeight {
+				t.Errorf("unxpected thumbnail size: got %dx%d, expected %dx%d",
+					cfg.Width, cfg.Height, tt.expectedWidth, tt.expectedHeight)
+			}
+		})
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	if _, err := Decode([]byte("not a photo")); err == nil {
+		t.Errorf("unxpected success decoding an invalid photo")
+	}
+}
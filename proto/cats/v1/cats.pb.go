@@ -0,0 +1,823 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: cats/v1/cats.proto
+
+package catsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CatEvent_Type int32
+
+const (
+	CatEvent_TYPE_UNSPECIFIED CatEvent_Type = 0
+	CatEvent_TYPE_CREATED     CatEvent_Type = 1
+	CatEvent_TYPE_UPDATED     CatEvent_Type = 2
+	CatEvent_TYPE_DELETED     CatEvent_Type = 3
+)
+
+// Enum value maps for CatEvent_Type.
+var (
+	CatEvent_Type_name = map[int32]string{
+		0: "TYPE_UNSPECIFIED",
+		1: "TYPE_CREATED",
+		2: "TYPE_UPDATED",
+		3: "TYPE_DELETED",
+	}
+	CatEvent_Type_value = map[string]int32{
+		"TYPE_UNSPECIFIED": 0,
+		"TYPE_CREATED":     1,
+		"TYPE_UPDATED":     2,
+		"TYPE_DELETED":     3,
+	}
+)
+
+func (x CatEvent_Type) Enum() *CatEvent_Type {
+	p := new(CatEvent_Type)
+	*p = x
+	return p
+}
+
+func (x CatEvent_Type) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (CatEvent_Type) Descriptor() protoreflect.EnumDescriptor {
+	return file_cats_v1_cats_proto_enumTypes[0].Descriptor()
+}
+
+func (CatEvent_Type) Type() protoreflect.EnumType {
+	return &file_cats_v1_cats_proto_enumTypes[0]
+}
+
+func (x CatEvent_Type) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use CatEvent_Type.Descriptor instead.
+func (CatEvent_Type) EnumDescriptor() ([]byte, []int) {
+	return file_cats_v1_cats_proto_rawDescGZIP(), []int{8, 0}
+}
+
+type Cat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Assigned by the server, ignored when creating or updating a cat.
+	Id    string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Color string `protobuf:"bytes,3,opt,name=color,proto3" json:"color,omitempty"`
+	// Computed from the birth date of cats with one.
+	Age   int32  `protobuf:"varint,4,opt,name=age,proto3" json:"age,omitempty"`
+	Breed string `protobuf:"bytes,5,opt,name=breed,proto3" json:"breed,omitempty"`
+	// Date the cat was born, such as 2019-04-01.
+	BirthDate string `protobuf:"bytes,6,opt,name=birth_date,json=birthDate,proto3" json:"birth_date,omitempty"`
+	// female or male, empty when unknown.
+	Sex string `protobuf:"bytes,7,opt,name=sex,proto3" json:"sex,omitempty"`
+	// Unset when it is unknown whether the cat is neutered.
+	Neutered *bool   `protobuf:"varint,8,opt,name=neutered,proto3,oneof" json:"neutered,omitempty"`
+	WeightKg float64 `protobuf:"fixed64,9,opt,name=weight_kg,json=weightKg,proto3" json:"weight_kg,omitempty"`
+	// Unique to the tenant.
+	Microchip string   `protobuf:"bytes,10,opt,name=microchip,proto3" json:"microchip,omitempty"`
+	Tags      []string `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Where the cat is in its adoption, ignored when creating or updating a cat.
+	Status        string `protobuf:"bytes,12,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Cat) Reset() {
+	*x = Cat{}
+	mi := &file_cats_v1_cats_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Cat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Cat) ProtoMessage() {}
+
+func (x *Cat) ProtoReflect() protoreflect.Message {
+	mi := &file_cats_v1_cats_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Cat.ProtoReflect.Descriptor instead.
+func (*Cat) Descriptor() ([]byte, []int) {
+	return file_cats_v1_cats_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Cat) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Cat) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Cat) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *Cat) GetAge() int32 {
+	if x != nil {
+		return x.Age
+	}
+	return 0
+}
+
+func (x *Cat) GetBreed() string {
+	if x != nil {
+		return x.Breed
+	}
+	return ""
+}
+
+func (x *Cat) GetBirthDate() string {
+	if x != nil {
+		return x.BirthDate
+	}
+	return ""
+}
+
+func (x *Cat) GetSex() string {
+	if x != nil {
+		return x.Sex
+	}
+	return ""
+}
+
+func (x *Cat) GetNeutered() bool {
+	if x != nil && x.Neutered != nil {
+		return *x.Neutered
+	}
+	return false
+}
+
+func (x *Cat) GetWeightKg() float64 {
+	if x != nil {
+		return x.WeightKg
+	}
+	return 0
+}
+
+func (x *Cat) GetMicrochip() string {
+	if x != nil {
+		return x.Microchip
+	}
+	return ""
+}
+
+func (x *Cat) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Cat) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type GetCatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCatRequest) Reset() {
+	*x = GetCatRequest{}
+	mi := &file_cats_v1_cats_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCatRequest) ProtoMessage() {}
+
+func (x *GetCatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cats_v1_cats_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCatRequest.ProtoReflect.Descriptor instead.
+func (*GetCatRequest) Descriptor() ([]byte, []int) {
+	return file_cats_v1_cats_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetCatRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// ListCatsRequest filters the cats listed, empty fields matching every cat.
+type ListCatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Only list cats with this name, ignoring case.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Only list cats with this color, ignoring case.
+	Color string `protobuf:"bytes,2,opt,name=color,proto3" json:"color,omitempty"`
+	// Only list cats at least this old.
+	MinAge *int32 `protobuf:"varint,3,opt,name=min_age,json=minAge,proto3,oneof" json:"min_age,omitempty"`
+	// Only list cats at most this old.
+	MaxAge *int32 `protobuf:"varint,4,opt,name=max_age,json=maxAge,proto3,oneof" json:"max_age,omitempty"`
+	// Only list cats of this breed, ignoring case.
+	Breed string `protobuf:"bytes,5,opt,name=breed,proto3" json:"breed,omitempty"`
+	// Only list cats of this sex.
+	Sex string `protobuf:"bytes,6,opt,name=sex,proto3" json:"sex,omitempty"`
+	// Only list cats that are, or are not, neutered.
+	Neutered *bool `protobuf:"varint,7,opt,name=neutered,proto3,oneof" json:"neutered,omitempty"`
+	// Only list the cat with this microchip.
+	Microchip string `protobuf:"bytes,8,opt,name=microchip,proto3" json:"microchip,omitempty"`
+	// Only list cats with every one of these tags.
+	Tags []string `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Only list cats with this adoption status.
+	Status        string `protobuf:"bytes,10,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCatsRequest) Reset() {
+	*x = ListCatsRequest{}
+	mi := &file_cats_v1_cats_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCatsRequest) ProtoMessage() {}
+
+func (x *ListCatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cats_v1_cats_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCatsRequest.ProtoReflect.Descriptor instead.
+func (*ListCatsRequest) Descriptor() ([]byte, []int) {
+	return file_cats_v1_cats_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ListCatsRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ListCatsRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *ListCatsRequest) GetMinAge() int32 {
+	if x != nil && x.MinAge != nil {
+		return *x.MinAge
+	}
+	return 0
+}
+
+func (x *ListCatsRequest) GetMaxAge() int32 {
+	if x != nil && x.MaxAge != nil {
+		return *x.MaxAge
+	}
+	return 0
+}
+
+func (x *ListCatsRequest) GetBreed() string {
+	if x != nil {
+		return x.Breed
+	}
+	return ""
+}
+
+func (x *ListCatsRequest) GetSex() string {
+	if x != nil {
+		return x.Sex
+	}
+	return ""
+}
+
+func (x *ListCatsRequest) GetNeutered() bool {
+	if x != nil && x.Neutered != nil {
+		return *x.Neutered
+	}
+	return false
+}
+
+func (x *ListCatsRequest) GetMicrochip() string {
+	if x != nil {
+		return x.Microchip
+	}
+	return ""
+}
+
+func (x *ListCatsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *ListCatsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type CreateCatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Cat           *Cat                   `protobuf:"bytes,1,opt,name=cat,proto3" json:"cat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCatRequest) Reset() {
+	*x = CreateCatRequest{}
+	mi := &file_cats_v1_cats_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCatRequest) ProtoMessage() {}
+
+func (x *CreateCatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cats_v1_cats_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCatRequest.ProtoReflect.Descriptor instead.
+func (*CreateCatRequest) Descriptor() ([]byte, []int) {
+	return file_cats_v1_cats_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CreateCatRequest) GetCat() *Cat {
+	if x != nil {
+		return x.Cat
+	}
+	return nil
+}
+
+type UpdateCatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Cat           *Cat                   `protobuf:"bytes,2,opt,name=cat,proto3" json:"cat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCatRequest) Reset() {
+	*x = UpdateCatRequest{}
+	mi := &file_cats_v1_cats_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCatRequest) ProtoMessage() {}
+
+func (x *UpdateCatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cats_v1_cats_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCatRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCatRequest) Descriptor() ([]byte, []int) {
+	return file_cats_v1_cats_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UpdateCatRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateCatRequest) GetCat() *Cat {
+	if x != nil {
+		return x.Cat
+	}
+	return nil
+}
+
+type DeleteCatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCatRequest) Reset() {
+	*x = DeleteCatRequest{}
+	mi := &file_cats_v1_cats_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCatRequest) ProtoMessage() {}
+
+func (x *DeleteCatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cats_v1_cats_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCatRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCatRequest) Descriptor() ([]byte, []int) {
+	return file_cats_v1_cats_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteCatRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DeleteCatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteCatResponse) Reset() {
+	*x = DeleteCatResponse{}
+	mi := &file_cats_v1_cats_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCatResponse) ProtoMessage() {}
+
+func (x *DeleteCatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_cats_v1_cats_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCatResponse.ProtoReflect.Descriptor instead.
+func (*DeleteCatResponse) Descriptor() ([]byte, []int) {
+	return file_cats_v1_cats_proto_rawDescGZIP(), []int{6}
+}
+
+type WatchCatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchCatsRequest) Reset() {
+	*x = WatchCatsRequest{}
+	mi := &file_cats_v1_cats_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchCatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchCatsRequest) ProtoMessage() {}
+
+func (x *WatchCatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_cats_v1_cats_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchCatsRequest.ProtoReflect.Descriptor instead.
+func (*WatchCatsRequest) Descriptor() ([]byte, []int) {
+	return file_cats_v1_cats_proto_rawDescGZIP(), []int{7}
+}
+
+// CatEvent describes a change to a cat.
+type CatEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique id of the event.
+	Id    string        `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type  CatEvent_Type `protobuf:"varint,2,opt,name=type,proto3,enum=cats.v1.CatEvent_Type" json:"type,omitempty"`
+	CatId string        `protobuf:"bytes,3,opt,name=cat_id,json=catId,proto3" json:"cat_id,omitempty"`
+	// The cat after the change, unset for deletions.
+	Cat           *Cat                   `protobuf:"bytes,4,opt,name=cat,proto3" json:"cat,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CatEvent) Reset() {
+	*x = CatEvent{}
+	mi := &file_cats_v1_cats_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CatEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CatEvent) ProtoMessage() {}
+
+func (x *CatEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_cats_v1_cats_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CatEvent.ProtoReflect.Descriptor instead.
+func (*CatEvent) Descriptor() ([]byte, []int) {
+	return file_cats_v1_cats_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *CatEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CatEvent) GetType() CatEvent_Type {
+	if x != nil {
+		return x.Type
+	}
+	return CatEvent_TYPE_UNSPECIFIED
+}
+
+func (x *CatEvent) GetCatId() string {
+	if x != nil {
+		return x.CatId
+	}
+	return ""
+}
+
+func (x *CatEvent) GetCat() *Cat {
+	if x != nil {
+		return x.Cat
+	}
+	return nil
+}
+
+func (x *CatEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+var File_cats_v1_cats_proto protoreflect.FileDescriptor
+
+const file_cats_v1_cats_proto_rawDesc = "" +
+	"\n" +
+	"\x12cats/v1/cats.proto\x12\acats.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xad\x02\n" +
+	"\x03Cat\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x03 \x01(\tR\x05color\x12\x10\n" +
+	"\x03age\x18\x04 \x01(\x05R\x03age\x12\x14\n" +
+	"\x05breed\x18\x05 \x01(\tR\x05breed\x12\x1d\n" +
+	"\n" +
+	"birth_date\x18\x06 \x01(\tR\tbirthDate\x12\x10\n" +
+	"\x03sex\x18\a \x01(\tR\x03sex\x12\x1f\n" +
+	"\bneutered\x18\b \x01(\bH\x00R\bneutered\x88\x01\x01\x12\x1b\n" +
+	"\tweight_kg\x18\t \x01(\x01R\bweightKg\x12\x1c\n" +
+	"\tmicrochip\x18\n" +
+	" \x01(\tR\tmicrochip\x12\x12\n" +
+	"\x04tags\x18\v \x03(\tR\x04tags\x12\x16\n" +
+	"\x06status\x18\f \x01(\tR\x06statusB\v\n" +
+	"\t_neutered\"\x1f\n" +
+	"\rGetCatRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xaf\x02\n" +
+	"\x0fListCatsRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05color\x18\x02 \x01(\tR\x05color\x12\x1c\n" +
+	"\amin_age\x18\x03 \x01(\x05H\x00R\x06minAge\x88\x01\x01\x12\x1c\n" +
+	"\amax_age\x18\x04 \x01(\x05H\x01R\x06maxAge\x88\x01\x01\x12\x14\n" +
+	"\x05breed\x18\x05 \x01(\tR\x05breed\x12\x10\n" +
+	"\x03sex\x18\x06 \x01(\tR\x03sex\x12\x1f\n" +
+	"\bneutered\x18\a \x01(\bH\x02R\bneutered\x88\x01\x01\x12\x1c\n" +
+	"\tmicrochip\x18\b \x01(\tR\tmicrochip\x12\x12\n" +
+	"\x04tags\x18\t \x03(\tR\x04tags\x12\x16\n" +
+	"\x06status\x18\n" +
+	" \x01(\tR\x06statusB\n" +
+	"\n" +
+	"\b_min_ageB\n" +
+	"\n" +
+	"\b_max_ageB\v\n" +
+	"\t_neutered\"2\n" +
+	"\x10CreateCatRequest\x12\x1e\n" +
+	"\x03cat\x18\x01 \x01(\v2\f.cats.v1.CatR\x03cat\"B\n" +
+	"\x10UpdateCatRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1e\n" +
+	"\x03cat\x18\x02 \x01(\v2\f.cats.v1.CatR\x03cat\"\"\n" +
+	"\x10DeleteCatRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x13\n" +
+	"\x11DeleteCatResponse\"\x12\n" +
+	"\x10WatchCatsRequest\"\x81\x02\n" +
+	"\bCatEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12*\n" +
+	"\x04type\x18\x02 \x01(\x0e2\x16.cats.v1.CatEvent.TypeR\x04type\x12\x15\n" +
+	"\x06cat_id\x18\x03 \x01(\tR\x05catId\x12\x1e\n" +
+	"\x03cat\x18\x04 \x01(\v2\f.cats.v1.CatR\x03cat\x12.\n" +
+	"\x04time\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"R\n" +
+	"\x04Type\x12\x14\n" +
+	"\x10TYPE_UNSPECIFIED\x10\x00\x12\x10\n" +
+	"\fTYPE_CREATED\x10\x01\x12\x10\n" +
+	"\fTYPE_UPDATED\x10\x02\x12\x10\n" +
+	"\fTYPE_DELETED\x10\x032\xe0\x02\n" +
+	"\vCatsService\x12.\n" +
+	"\x06GetCat\x12\x16.cats.v1.GetCatRequest\x1a\f.cats.v1.Cat\x124\n" +
+	"\bListCats\x12\x18.cats.v1.ListCatsRequest\x1a\f.cats.v1.Cat0\x01\x124\n" +
+	"\tCreateCat\x12\x19.cats.v1.CreateCatRequest\x1a\f.cats.v1.Cat\x124\n" +
+	"\tUpdateCat\x12\x19.cats.v1.UpdateCatRequest\x1a\f.cats.v1.Cat\x12B\n" +
+	"\tDeleteCat\x12\x19.cats.v1.DeleteCatRequest\x1a\x1a.cats.v1.DeleteCatResponse\x12;\n" +
+	"\tWatchCats\x12\x19.cats.v1.WatchCatsRequest\x1a\x11.cats.v1.CatEvent0\x01B0Z.github.com/waikco/cats-v1/proto/cats/v1;catsv1b\x06proto3"
+
+var (
+	file_cats_v1_cats_proto_rawDescOnce sync.Once
+	file_cats_v1_cats_proto_rawDescData []byte
+)
+
+func file_cats_v1_cats_proto_rawDescGZIP() []byte {
+	file_cats_v1_cats_proto_rawDescOnce.Do(func() {
+		file_cats_v1_cats_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_cats_v1_cats_proto_rawDesc), len(file_cats_v1_cats_proto_rawDesc)))
+	})
+	return file_cats_v1_cats_proto_rawDescData
+}
+
+var file_cats_v1_cats_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_cats_v1_cats_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_cats_v1_cats_proto_goTypes = []any{
+	(CatEvent_Type)(0),            // 0: cats.v1.CatEvent.Type
+	(*Cat)(nil),                   // 1: cats.v1.Cat
+	(*GetCatRequest)(nil),         // 2: cats.v1.GetCatRequest
+	(*ListCatsRequest)(nil),       // 3: cats.v1.ListCatsRequest
+	(*CreateCatRequest)(nil),      // 4: cats.v1.CreateCatRequest
+	(*UpdateCatRequest)(nil),      // 5: cats.v1.UpdateCatRequest
+	(*DeleteCatRequest)(nil),      // 6: cats.v1.DeleteCatRequest
+	(*DeleteCatResponse)(nil),     // 7: cats.v1.DeleteCatResponse
+	(*WatchCatsRequest)(nil),      // 8: cats.v1.WatchCatsRequest
+	(*CatEvent)(nil),              // 9: cats.v1.CatEvent
+	(*timestamppb.Timestamp)(nil), // 10: google.protobuf.Timestamp
+}
+var file_cats_v1_cats_proto_depIdxs = []int32{
+	1,  // 0: cats.v1.CreateCatRequest.cat:type_name -> cats.v1.Cat
+	1,  // 1: cats.v1.UpdateCatRequest.cat:type_name -> cats.v1.Cat
+	0,  // 2: cats.v1.CatEvent.type:type_name -> cats.v1.CatEvent.Type
+	1,  // 3: cats.v1.CatEvent.cat:type_name -> cats.v1.Cat
+	10, // 4: cats.v1.CatEvent.time:type_name -> google.protobuf.Timestamp
+	2,  // 5: cats.v1.CatsService.GetCat:input_type -> cats.v1.GetCatRequest
+	3,  // 6: cats.v1.CatsService.ListCats:input_type -> cats.v1.ListCatsRequest
+	4,  // 7: cats.v1.CatsService.CreateCat:input_type -> cats.v1.CreateCatRequest
+	5,  // 8: cats.v1.CatsService.UpdateCat:input_type -> cats.v1.UpdateCatRequest
+	6,  // 9: cats.v1.CatsService.DeleteCat:input_type -> cats.v1.DeleteCatRequest
+	8,  // 10: cats.v1.CatsService.WatchCats:input_type -> cats.v1.WatchCatsRequest
+	1,  // 11: cats.v1.CatsService.GetCat:output_type -> cats.v1.Cat
+	1,  // 12: cats.v1.CatsService.ListCats:output_type -> cats.v1.Cat
+	1,  // 13: cats.v1.CatsService.CreateCat:output_type -> cats.v1.Cat
+	1,  // 14: cats.v1.CatsService.UpdateCat:output_type -> cats.v1.Cat
+	7,  // 15: cats.v1.CatsService.DeleteCat:output_type -> cats.v1.DeleteCatResponse
+	9,  // 16: cats.v1.CatsService.WatchCats:output_type -> cats.v1.CatEvent
+	11, // [11:17] is the sub-list for method output_type
+	5,  // [5:11] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_cats_v1_cats_proto_init() }
+func file_cats_v1_cats_proto_init() {
+	if File_cats_v1_cats_proto != nil {
+		return
+	}
+	file_cats_v1_cats_proto_msgTypes[0].OneofWrappers = []any{}
+	file_cats_v1_cats_proto_msgTypes[2].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_cats_v1_cats_proto_rawDesc), len(file_cats_v1_cats_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_cats_v1_cats_proto_goTypes,
+		DependencyIndexes: file_cats_v1_cats_proto_depIdxs,
+		EnumInfos:         file_cats_v1_cats_proto_enumTypes,
+		MessageInfos:      file_cats_v1_cats_proto_msgTypes,
+	}.Build()
+	File_cats_v1_cats_proto = out.File
+	file_cats_v1_cats_proto_goTypes = nil
+	file_cats_v1_cats_proto_depIdxs = nil
+}
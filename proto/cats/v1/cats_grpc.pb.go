@@ -0,0 +1,334 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             (unknown)
+// source: cats/v1/cats.proto
+
+package catsv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	CatsService_GetCat_FullMethodName    = "/cats.v1.CatsService/GetCat"
+	CatsService_ListCats_FullMethodName  = "/cats.v1.CatsService/ListCats"
+	CatsService_CreateCat_FullMethodName = "/cats.v1.CatsService/CreateCat"
+	CatsService_UpdateCat_FullMethodName = "/cats.v1.CatsService/UpdateCat"
+	CatsService_DeleteCat_FullMethodName = "/cats.v1.CatsService/DeleteCat"
+	CatsService_WatchCats_FullMethodName = "/cats.v1.CatsService/WatchCats"
+)
+
+// CatsServiceClient is the client API for CatsService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// CatsService serves the cats api over grpc, backed by the same storage as the rest api.
+type CatsServiceClient interface {
+	// GetCat retrieves a single cat, failing with NOT_FOUND when there is none with the id.
+	GetCat(ctx context.Context, in *GetCatRequest, opts ...grpc.CallOption) (*Cat, error)
+	// ListCats streams every cat matching the filter, in id order.
+	ListCats(ctx context.Context, in *ListCatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Cat], error)
+	// CreateCat stores a new cat, returning it along with its assigned id.
+	CreateCat(ctx context.Context, in *CreateCatRequest, opts ...grpc.CallOption) (*Cat, error)
+	// UpdateCat replaces the cat with the given id.
+	UpdateCat(ctx context.Context, in *UpdateCatRequest, opts ...grpc.CallOption) (*Cat, error)
+	// DeleteCat deletes the cat with the given id.
+	DeleteCat(ctx context.Context, in *DeleteCatRequest, opts ...grpc.CallOption) (*DeleteCatResponse, error)
+	// WatchCats streams an event for every cat created, updated or deleted after the call starts.
+	WatchCats(ctx context.Context, in *WatchCatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CatEvent], error)
+}
+
+type catsServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCatsServiceClient(cc grpc.ClientConnInterface) CatsServiceClient {
+	return &catsServiceClient{cc}
+}
+
+func (c *catsServiceClient) GetCat(ctx context.Context, in *GetCatRequest, opts ...grpc.CallOption) (*Cat, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Cat)
+	err := c.cc.Invoke(ctx, CatsService_GetCat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catsServiceClient) ListCats(ctx context.Context, in *ListCatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Cat], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CatsService_ServiceDesc.Streams[0], CatsService_ListCats_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ListCatsRequest, Cat]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CatsService_ListCatsClient = grpc.ServerStreamingClient[Cat]
+
+func (c *catsServiceClient) CreateCat(ctx context.Context, in *CreateCatRequest, opts ...grpc.CallOption) (*Cat, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Cat)
+	err := c.cc.Invoke(ctx, CatsService_CreateCat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catsServiceClient) UpdateCat(ctx context.Context, in *UpdateCatRequest, opts ...grpc.CallOption) (*Cat, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Cat)
+	err := c.cc.Invoke(ctx, CatsService_UpdateCat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catsServiceClient) DeleteCat(ctx context.Context, in *DeleteCatRequest, opts ...grpc.CallOption) (*DeleteCatResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteCatResponse)
+	err := c.cc.Invoke(ctx, CatsService_DeleteCat_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *catsServiceClient) WatchCats(ctx context.Context, in *WatchCatsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CatEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &CatsService_ServiceDesc.Streams[1], CatsService_WatchCats_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchCatsRequest, CatEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CatsService_WatchCatsClient = grpc.ServerStreamingClient[CatEvent]
+
+// CatsServiceServer is the server API for CatsService service.
+// All implementations must embed UnimplementedCatsServiceServer
+// for forward compatibility.
+//
+// CatsService serves the cats api over grpc, backed by the same storage as the rest api.
+type CatsServiceServer interface {
+	// GetCat retrieves a single cat, failing with NOT_FOUND when there is none with the id.
+	GetCat(context.Context, *GetCatRequest) (*Cat, error)
+	// ListCats streams every cat matching the filter, in id order.
+	ListCats(*ListCatsRequest, grpc.ServerStreamingServer[Cat]) error
+	// CreateCat stores a new cat, returning it along with its assigned id.
+	CreateCat(context.Context, *CreateCatRequest) (*Cat, error)
+	// UpdateCat replaces the cat with the given id.
+	UpdateCat(context.Context, *UpdateCatRequest) (*Cat, error)
+	// DeleteCat deletes the cat with the given id.
+	DeleteCat(context.Context, *DeleteCatRequest) (*DeleteCatResponse, error)
+	// WatchCats streams an event for every cat created, updated or deleted after the call starts.
+	WatchCats(*WatchCatsRequest, grpc.ServerStreamingServer[CatEvent]) error
+	mustEmbedUnimplementedCatsServiceServer()
+}
+
+// UnimplementedCatsServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedCatsServiceServer struct{}
+
+func (UnimplementedCatsServiceServer) GetCat(context.Context, *GetCatRequest) (*Cat, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetCat not implemented")
+}
+func (UnimplementedCatsServiceServer) ListCats(*ListCatsRequest, grpc.ServerStreamingServer[Cat]) error {
+	return status.Error(codes.Unimplemented, "method ListCats not implemented")
+}
+func (UnimplementedCatsServiceServer) CreateCat(context.Context, *CreateCatRequest) (*Cat, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateCat not implemented")
+}
+func (UnimplementedCatsServiceServer) UpdateCat(context.Context, *UpdateCatRequest) (*Cat, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateCat not implemented")
+}
+func (UnimplementedCatsServiceServer) DeleteCat(context.Context, *DeleteCatRequest) (*DeleteCatResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteCat not implemented")
+}
+func (UnimplementedCatsServiceServer) WatchCats(*WatchCatsRequest, grpc.ServerStreamingServer[CatEvent]) error {
+	return status.Error(codes.Unimplemented, "method WatchCats not implemented")
+}
+func (UnimplementedCatsServiceServer) mustEmbedUnimplementedCatsServiceServer() {}
+func (UnimplementedCatsServiceServer) testEmbeddedByValue()                     {}
+
+// UnsafeCatsServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CatsServiceServer will
+// result in compilation errors.
+type UnsafeCatsServiceServer interface {
+	mustEmbedUnimplementedCatsServiceServer()
+}
+
+func RegisterCatsServiceServer(s grpc.ServiceRegistrar, srv CatsServiceServer) {
+	// If the following call panics, it indicates UnimplementedCatsServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&CatsService_ServiceDesc, srv)
+}
+
+func _CatsService_GetCat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatsServiceServer).GetCat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatsService_GetCat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatsServiceServer).GetCat(ctx, req.(*GetCatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatsService_ListCats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListCatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatsServiceServer).ListCats(m, &grpc.GenericServerStream[ListCatsRequest, Cat]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CatsService_ListCatsServer = grpc.ServerStreamingServer[Cat]
+
+func _CatsService_CreateCat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatsServiceServer).CreateCat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatsService_CreateCat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatsServiceServer).CreateCat(ctx, req.(*CreateCatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatsService_UpdateCat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatsServiceServer).UpdateCat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatsService_UpdateCat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatsServiceServer).UpdateCat(ctx, req.(*UpdateCatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatsService_DeleteCat_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCatRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CatsServiceServer).DeleteCat(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CatsService_DeleteCat_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CatsServiceServer).DeleteCat(ctx, req.(*DeleteCatRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CatsService_WatchCats_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchCatsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(CatsServiceServer).WatchCats(m, &grpc.GenericServerStream[WatchCatsRequest, CatEvent]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type CatsService_WatchCatsServer = grpc.ServerStreamingServer[CatEvent]
+
+// CatsService_ServiceDesc is the grpc.ServiceDesc for CatsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CatsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "cats.v1.CatsService",
+	HandlerType: (*CatsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetCat",
+			Handler:    _CatsService_GetCat_Handler,
+		},
+		{
+			MethodName: "CreateCat",
+			Handler:    _CatsService_CreateCat_Handler,
+		},
+		{
+			MethodName: "UpdateCat",
+			Handler:    _CatsService_UpdateCat_Handler,
+		},
+		{
+			MethodName: "DeleteCat",
+			Handler:    _CatsService_DeleteCat_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListCats",
+			Handler:       _CatsService_ListCats_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "WatchCats",
+			Handler:       _CatsService_WatchCats_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "cats/v1/cats.proto",
+}
@@ -0,0 +1,175 @@
+// Package reference holds the breeds and colors cats are normalised against, seeded from an
+// embedded dataset.
+package reference
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+
+	json "github.com/json-iterator/go"
+	"github.com/waikco/cats-v1/model"
+)
+
+var (
+	//go:embed breeds.json
+	breedsJSON []byte
+	//go:embed colors.json
+	colorsJSON []byte
+)
+
+// Breeds returns the embedded breeds.
+func Breeds() []model.Breed {
+	var breeds []model.Breed
+	if err := json.Unmarshal(breedsJSON, &breeds); err != nil {
+		panic(fmt.Sprintf("invalid embedded breeds: %v", err))
+	}
+	return breeds
+}
+
+// Colors returns the embedded colors.
+func Colors() []model.Color {
+	var colors []model.Color
+	if err := json.Unmarshal(colorsJSON, &colors); err != nil {
+		panic(fmt.Sprintf("invalid embedded colors: %v", err))
+	}
+	return colors
+}
+
+// Catalog looks breeds and colors up by their names and aliases. A nil catalog knows of
+// none, leaving cats as they are.
+type Catalog struct {
+	breeds      []model.Breed
+	colors      []model.Color
+	breedByName map[string]int
+	colorByName map[string]int
+	// strict rejects cats of unknown breeds and colors rather than keeping them as given.
+	strict bool
+}
+
+// New creates a catalog of breeds and colors, rejecting unknown ones when strict.
+func New(breeds []model.Breed, colors []model.Color, strict bool) *Catalog {
+	c := &Catalog{
+		breeds:      breeds,
+		colors:      colors,
+		breedByName: map[string]int{},
+		colorByName: map[string]int{},
+		strict:      strict,
+	}
+	for i, b := range breeds {
+		index(c.breedByName, i, b.Name, b.Aliases)
+	}
+	for i, col := range colors {
+		index(c.colorByName, i, col.Name, col.Aliases)
+	}
+	return c
+}
+
+// Default creates a catalog of the embedded breeds and colors.
+func Default(strict bool) *Catalog {
+	return New(Breeds(), Colors(), strict)
+}
+
+// Load creates a catalog of the breeds and colors of storage, seeding them from the embedded
+// dataset first, or of the embedded ones when storage has no reference data.
+func Load(storage model.Storage, strict bool) (*Catalog, error) {
+	store, ok := storage.(model.ReferenceStore)
+	if !ok {
+		return Default(strict), nil
+	}
+	if err := store.SeedReference(Breeds(), Colors()); err != nil {
+		return nil, fmt.Errorf("seeding reference data: %v", err)
+	}
+	breeds, err := store.SelectBreeds()
+	if err != nil {
+		return nil, fmt.Errorf("loading breeds: %v", err)
+	}
+	colors, err := store.SelectColors()
+	if err != nil {
+		return nil, fmt.Errorf("loading colors: %v", err)
+	}
+	return New(breeds, colors, strict), nil
+}
+
+// index adds a name and its aliases to the lookup of an entry, the first entry with a
+// name winning.
+func index(byName map[string]int, i int, name string, aliases []string) {
+	for _, n := range append([]string{name}, aliases...) {
+		if _, ok := byName[key(n)]; !ok {
+			byName[key(n)] = i
+		}
+	}
+}
+
+// key is the form names are looked up by, ignoring case, surrounding spaces and the
+// separators between words.
+func key(name string) string {
+	name = strings.NewReplacer("-", " ", "_", " ").Replace(strings.ToLower(name))
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// Breeds returns every breed of the catalog.
+func (c *Catalog) Breeds() []model.Breed {
+	if c == nil {
+		return []model.Breed{}
+	}
+	return c.breeds
+}
+
+// Colors returns every color of the catalog.
+func (c *Catalog) Colors() []model.Color {
+	if c == nil {
+		return []model.Color{}
+	}
+	return c.colors
+}
+
+// Breed looks a breed up by its name or one of its aliases.
+func (c *Catalog) Breed(name string) (model.Breed, bool) {
+	if c == nil {
+		return model.Breed{}, false
+	}
+	i, ok := c.breedByName[key(name)]
+	if !ok {
+		return model.Breed{}, false
+	}
+	return c.breeds[i], true
+}
+
+// Color looks a color up by its name or one of its aliases.
+func (c *Catalog) Color(name string) (model.Color, bool) {
+	if c == nil {
+		return model.Color{}, false
+	}
+	i, ok := c.colorByName[key(name)]
+	if !ok {
+		return model.Color{}, false
+	}
+	return c.colors[i], true
+}
+
+// Normalize replaces the color and breed of a cat with their canonical names. Unknown ones
+// are kept trimmed, unless the catalog is strict, when a model.ValidationError listing
+// them is returned.
+func (c *Catalog) Normalize(cat *model.Cat) error {
+	cat.Color = strings.TrimSpace(cat.Color)
+	cat.Breed = strings.TrimSpace(cat.Breed)
+	if c == nil {
+		return nil
+	}
+	var errs model.ValidationError
+	if color, ok := c.Color(cat.Color); ok {
+		cat.Color = color.Name
+	} else if c.strict && cat.Color != "" {
+		errs = append(errs, model.FieldError{Field: "color", Reason: "must be a known color"})
+	}
+	if breed, ok := c.Breed(cat.Breed); ok {
+		cat.Breed = breed.Name
+	} else if c.strict && cat.Breed != "" {
+		errs = append(errs, model.FieldError{Field: "breed", Reason: "must be a known breed"})
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package reference
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/model"
+)
+
+func TestEmbedded(t *testing.T) {
+	c := Default(false)
+	for _, b := range Breeds() {
+		if got, ok := c.Breed(b.Name); !ok || got.Name != b.Name {
+			t.Errorf("unxpected breed for %s: got %v", b.Name, got)
+		}
+	}
+	for _, col := range Colors() {
+		if got, ok := c.Color(col.Name); !ok || got.Name != col.Name {
+			t.Errorf("unxpected color for %s: got %v", col.Name, got)
+		}
+		if len(col.Hex) != 7 || col.Hex[0] != '#' {
+			t.Errorf("unxpected hex code for %s: %s", col.Name, col.Hex)
+		}
+	}
+}
+
+func TestCatalog_Normalize(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		strict bool
+		cat    model.Cat
+		// then
+		expectedCat   model.Cat
+		expectedError error
+	}{
+		{
+			description: "canonical names",
+			cat:         model.Cat{Color: "black", Breed: "Siamese"},
+			expectedCat: model.Cat{Color: "black", Breed: "Siamese"},
+		},
+		{
+			description: "case and spaces",
+			cat:         model.Cat{Color: "Black ", Breed: " maine  COON"},
+			expectedCat: model.Cat{Color: "black", Breed: "Maine Coon"},
+		},
+		{
+			description: "aliases",
+			cat:         model.Cat{Color: "blk", Breed: "DSH"},
+			expectedCat: model.Cat{Color: "black", Breed: "Domestic Shorthair"},
+		},
+		{
+			description: "separators",
+			cat:         model.Cat{Color: "tri-color", Breed: "norwegian_forest"},
+			expectedCat: model.Cat{Color: "calico", Breed: "Norwegian Forest Cat"},
+		},
+		{
+			description: "unknown values are kept",
+			cat:         model.Cat{Color: " sparkly ", Breed: "unicorn"},
+			expectedCat: model.Cat{Color: "sparkly", Breed: "unicorn"},
+		},
+		{
+			description: "unknown values are rejected when strict",
+			strict:      true,
+			cat:         model.Cat{Color: "sparkly", Breed: "unicorn"},
+			expectedCat: model.Cat{Color: "sparkly", Breed: "unicorn"},
+			expectedError: model.ValidationError{
+				{Field: "color", Reason: "must be a known color"},
+				{Field: "breed", Reason: "must be a known breed"},
+			},
+		},
+		{
+			description: "no breed is accepted when strict",
+			strict:      true,
+			cat:         model.Cat{Color: "gray"},
+			expectedCat: model.Cat{Color: "grey"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			cat := tt.cat
+			err := Default(tt.strict).Normalize(&cat)
+
+			if !reflect.DeepEqual(err, tt.expectedError) {
+				t.Errorf("unxpected error: got %v, expected %v", err, tt.expectedError)
+			}
+			if !reflect.DeepEqual(cat, tt.expectedCat) {
+				t.Errorf("unxpected cat: got %+v, expected %+v", cat, tt.expectedCat)
+			}
+		})
+	}
+}
+
+func TestCatalog_NormalizeNil(t *testing.T) {
+	var c *Catalog
+	cat := model.Cat{Color: " blk "}
+	if err := c.Normalize(&cat); err != nil || cat.Color != "blk" {
+		t.Errorf("unxpected result: got %q, %v", cat.Color, err)
+	}
+}
+
+// referenceStorage is storage holding reference data.
+type referenceStorage struct {
+	*model.MockStorage
+	*model.MockReferenceStore
+}
+
+func TestLoad(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockReferenceStore(ctrl)
+	s.EXPECT().SeedReference(Breeds(), Colors()).Return(nil)
+	s.EXPECT().SelectBreeds().Return([]model.Breed{{Name: "Moon Cat", Aliases: []string{"mooncat"}}}, nil)
+	s.EXPECT().SelectColors().Return([]model.Color{{Name: "black", Hex: "#000000"}}, nil)
+
+	c, err := Load(referenceStorage{model.NewMockStorage(ctrl), s}, true)
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	cat := model.Cat{Color: "BLACK", Breed: "mooncat"}
+	if err := c.Normalize(&cat); err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	if cat.Breed != "Moon Cat" || cat.Color != "black" {
+		t.Errorf("unxpected cat: %+v", cat)
+	}
+}
+
+func TestLoad_Error(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	s := model.NewMockReferenceStore(ctrl)
+	s.EXPECT().SeedReference(gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+
+	if _, err := Load(referenceStorage{model.NewMockStorage(ctrl), s}, false); err == nil {
+		t.Error("expected an error seeding reference data")
+	}
+}
+
+func TestLoad_Unsupported(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	c, err := Load(model.NewMockStorage(ctrl), false)
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	if len(c.Breeds()) != len(Breeds()) || len(c.Colors()) != len(Colors()) {
+		t.Errorf("unxpected catalog: got %d breeds and %d colors", len(c.Breeds()), len(c.Colors()))
+	}
+}
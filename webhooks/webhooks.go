@@ -0,0 +1,252 @@
+// Package webhooks delivers cat events to the webhooks subscribed to them, signing every
+// delivery and retrying failed ones with exponential backoff until they are dead lettered.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/waikco/cats-v1/buildinfo"
+	"github.com/waikco/cats-v1/model"
+)
+
+// Headers sent with every delivery.
+const (
+	EventHeader     = "X-Cats-Event"
+	DeliveryHeader  = "X-Cats-Delivery"
+	TimestampHeader = "X-Cats-Timestamp"
+	SignatureHeader = "X-Cats-Signature"
+)
+
+const (
+	DefaultMaxAttempts  = 8
+	DefaultMinBackoff   = 5 * time.Second
+	DefaultMaxBackoff   = time.Hour
+	DefaultTimeout      = 10 * time.Second
+	DefaultPollInterval = time.Second
+
+	// claimBatchSize is the number of due deliveries attempted per poll.
+	claimBatchSize = 50
+	// maxErrorLength bounds the response body kept as the error of a failed attempt.
+	maxErrorLength = 512
+)
+
+// Sign returns the signature of a delivery, the hex encoded HMAC-SHA256 of the timestamp,
+// a dot and the body, keyed with the webhook's secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	_, _ = mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify reports whether signature is the signature of a delivery, in constant time.
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// Options configures how deliveries are attempted, values of 0 using the defaults.
+type Options struct {
+	// MaxAttempts is the number of attempts made before a delivery is dead lettered.
+	MaxAttempts int
+	// MinBackoff and MaxBackoff bound the exponential backoff between attempts.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+	// Timeout bounds every attempt.
+	Timeout time.Duration
+	// PollInterval is how often due deliveries are looked for.
+	PollInterval time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = DefaultMaxAttempts
+	}
+	if o.MinBackoff <= 0 {
+		o.MinBackoff = DefaultMinBackoff
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = DefaultMaxBackoff
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = DefaultTimeout
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = DefaultPollInterval
+	}
+	return o
+}
+
+// Dispatcher attempts the deliveries queued in the store, which queues the deliveries of
+// every change to a cat in the transaction making it. Deliveries are persisted before being
+// attempted, so any still pending are resumed after a restart.
+type Dispatcher struct {
+	store     model.WebhookStore
+	client    *http.Client
+	opts      Options
+	userAgent string
+	now       func() time.Time
+}
+
+// NewDispatcher creates a dispatcher for the webhooks in store.
+func NewDispatcher(store model.WebhookStore, opts Options) *Dispatcher {
+	opts = opts.withDefaults()
+	return &Dispatcher{
+		store:     store,
+		client:    &http.Client{Timeout: opts.Timeout},
+		opts:      opts,
+		userAgent: "cats-v1-webhooks/" + buildinfo.Get().Version,
+		now:       time.Now,
+	}
+}
+
+// Replay queues a new delivery of the event sent by an earlier one, which is left as is
+// in the delivery log.
+func (d *Dispatcher) Replay(previous model.Delivery) (model.Delivery, error) {
+	replay := model.Delivery{
+		WebhookID:   previous.WebhookID,
+		EventID:     previous.EventID,
+		EventType:   previous.EventType,
+		Payload:     previous.Payload,
+		Status:      model.DeliveryPending,
+		NextAttempt: d.now(),
+	}
+	deliveries := []model.Delivery{replay}
+	if err := d.storeFor(previous.TenantID).InsertDeliveries(deliveries); err != nil {
+		return model.Delivery{}, err
+	}
+	return deliveries[0], nil
+}
+
+// storeFor returns the store of the webhooks of a tenant, or the dispatcher's store when the
+// tenant is empty or the store is unable to scope queries.
+func (d *Dispatcher) storeFor(tenant string) model.WebhookStore {
+	if t, ok := d.store.(model.Tenanted); ok && tenant != "" {
+		if store, ok := t.ForTenant(tenant).(model.WebhookStore); ok {
+			return store
+		}
+	}
+	return d.store
+}
+
+// Run attempts due deliveries every poll interval until the context is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.Dispatch(ctx); err != nil {
+			log.Error().Msgf("unable to dispatch webhook deliveries: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Dispatch attempts every due delivery, returning how many were attempted.
+func (d *Dispatcher) Dispatch(ctx context.Context) (int, error) {
+	n := 0
+	for {
+		// claimed deliveries are leased for long enough to attempt them all
+		lease := d.opts.Timeout*claimBatchSize + time.Minute
+		deliveries, err := d.store.ClaimDeliveries(d.now(), lease, claimBatchSize)
+		if err != nil {
+			return n, err
+		}
+		for _, delivery := range deliveries {
+			if err := ctx.Err(); err != nil {
+				return n, err
+			}
+			d.attempt(ctx, delivery)
+			n++
+		}
+		if len(deliveries) < claimBatchSize {
+			return n, nil
+		}
+	}
+}
+
+// attempt sends a delivery and records the outcome, scheduling a retry or dead lettering
+// it after a failure.
+func (d *Dispatcher) attempt(ctx context.Context, delivery model.Delivery) {
+	now := d.now()
+	delivery.Attempts++
+	delivery.LastAttempt = &now
+	delivery.LastStatus, delivery.LastError = 0, ""
+
+	webhook, err := d.storeFor(delivery.TenantID).SelectWebhook(delivery.WebhookID)
+	if err == nil {
+		delivery.LastStatus, err = d.send(ctx, webhook, delivery)
+	} else if err == sql.ErrNoRows {
+		err = fmt.Errorf("webhook %s no longer exists", delivery.WebhookID)
+		delivery.Attempts = d.opts.MaxAttempts
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = model.DeliverySucceeded
+	case delivery.Attempts >= d.opts.MaxAttempts:
+		delivery.Status = model.DeliveryDead
+		delivery.LastError = err.Error()
+		log.Warn().Msgf("webhook delivery %s dead lettered after %d attempts: %v", delivery.ID, delivery.Attempts, err)
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextAttempt = now.Add(d.backoff(delivery.Attempts))
+		log.Debug().Msgf("webhook delivery %s failed, retrying at %s: %v", delivery.ID, delivery.NextAttempt, err)
+	}
+
+	if err := d.store.UpdateDelivery(delivery); err != nil {
+		log.Error().Msgf("unable to record attempt of webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+// send posts a delivery to its webhook, failing unless the response is a 2xx.
+func (d *Dispatcher) send(ctx context.Context, w model.Webhook, delivery model.Delivery) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, w.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req = req.WithContext(ctx)
+	timestamp := d.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", d.userAgent)
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(w.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, _ := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorLength))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response %d: %s", resp.StatusCode, body)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns how long to wait after the given number of attempts, doubling with every
+// attempt, with jitter between half and all of it.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	b := d.opts.MinBackoff << uint(attempts-1)
+	if b <= 0 || b > d.opts.MaxBackoff {
+		b = d.opts.MaxBackoff
+	}
+	return b/2 + time.Duration(rand.Int63n(int64(b/2)+1))
+}
@@ -0,0 +1,197 @@
+package webhooks
+
+import (
+	"context"
+	"database/sql"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/waikco/cats-v1/model"
+)
+
+var now = time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+
+func newDispatcher(store model.WebhookStore) *Dispatcher {
+	d := NewDispatcher(store, Options{MaxAttempts: 3, MinBackoff: time.Minute, MaxBackoff: 10 * time.Minute})
+	d.now = func() time.Time { return now }
+	return d
+}
+
+func TestSign(t *testing.T) {
+	signature := Sign("secret", 1577934245, []byte(`{"id":"1"}`))
+	if signature != "sha256=6c077e6a16a005b81bde1fdcab258e9b681af1a107beb336b9a2497aa22f6107" {
+		t.Errorf("unxpected signature: %s", signature)
+	}
+	if !Verify("secret", 1577934245, []byte(`{"id":"1"}`), signature) {
+		t.Errorf("unxpected failure to verify signature %s", signature)
+	}
+	if Verify("other", 1577934245, []byte(`{"id":"1"}`), signature) {
+		t.Errorf("unxpected verification of signature with another secret")
+	}
+	if Verify("secret", 1577934246, []byte(`{"id":"1"}`), signature) {
+		t.Errorf("unxpected verification of signature with another timestamp")
+	}
+}
+
+func TestDispatcher_Dispatch(t *testing.T) {
+	tests := []struct {
+		description string
+		// given
+		attempts int
+		status   int
+		webhook  error
+		// then
+		expectedStatus   string
+		expectedAttempts int
+		expectedRetry    bool
+	}{
+		{
+			description:      "delivered",
+			status:           http.StatusNoContent,
+			expectedStatus:   model.DeliverySucceeded,
+			expectedAttempts: 1,
+		},
+		{
+			description:      "retried after a failure",
+			attempts:         1,
+			status:           http.StatusInternalServerError,
+			expectedStatus:   model.DeliveryPending,
+			expectedAttempts: 2,
+			expectedRetry:    true,
+		},
+		{
+			description:      "dead lettered after the last attempt",
+			attempts:         2,
+			status:           http.StatusBadGateway,
+			expectedStatus:   model.DeliveryDead,
+			expectedAttempts: 3,
+		},
+		{
+			description:      "dead lettered when the webhook is gone",
+			webhook:          sql.ErrNoRows,
+			expectedStatus:   model.DeliveryDead,
+			expectedAttempts: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.description, func(t *testing.T) {
+			payload := []byte(`{"id":"e1","type":"cat.created"}`)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := ioutil.ReadAll(r.Body)
+				timestamp, _ := strconv.ParseInt(r.Header.Get(TimestampHeader), 10, 64)
+				if !Verify("secret", timestamp, body, r.Header.Get(SignatureHeader)) {
+					t.Errorf("unxpected signature %s", r.Header.Get(SignatureHeader))
+				}
+				if r.Header.Get(EventHeader) != "cat.created" || r.Header.Get(DeliveryHeader) != "d1" {
+					t.Errorf("unxpected headers: %v", r.Header)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			store := model.NewMockWebhookStore(ctrl)
+			store.EXPECT().ClaimDeliveries(now, gomock.Any(), claimBatchSize).Return([]model.Delivery{
+				{ID: "d1", WebhookID: "w1", EventType: "cat.created", Payload: payload,
+					Status: model.DeliveryPending, Attempts: tt.attempts},
+			}, nil)
+			store.EXPECT().SelectWebhook("w1").Return(model.Webhook{ID: "w1", URL: server.URL, Secret: "secret"}, tt.webhook)
+			store.EXPECT().UpdateDelivery(gomock.Any()).DoAndReturn(func(d model.Delivery) error {
+				if d.Status != tt.expectedStatus {
+					t.Errorf("unxpected status: got %s, expected %s", d.Status, tt.expectedStatus)
+				}
+				if d.Attempts != tt.expectedAttempts {
+					t.Errorf("unxpected attempts: got %d, expected %d", d.Attempts, tt.expectedAttempts)
+				}
+				if d.LastAttempt == nil || !d.LastAttempt.Equal(now) {
+					t.Errorf("unxpected last attempt: %v", d.LastAttempt)
+				}
+				if d.LastStatus != tt.status {
+					t.Errorf("unxpected last status: got %d, expected %d", d.LastStatus, tt.status)
+				}
+				if (tt.expectedStatus == model.DeliverySucceeded) != (d.LastError == "") {
+					t.Errorf("unxpected last error: %q", d.LastError)
+				}
+				// the second attempt backs off between half and all of twice the min backoff
+				if retry := d.NextAttempt.Sub(now); tt.expectedRetry && (retry < time.Minute || retry > 2*time.Minute) {
+					t.Errorf("unxpected next attempt in %s", retry)
+				}
+				return nil
+			})
+
+			n, err := newDispatcher(store).Dispatch(context.Background())
+			if err != nil {
+				t.Errorf("unxpected error: %v", err)
+			}
+			if n != 1 {
+				t.Errorf("unxpected number of attempts: got %d, expected 1", n)
+			}
+		})
+	}
+}
+
+func TestDispatcher_Replay(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := model.NewMockWebhookStore(ctrl)
+	store.EXPECT().InsertDeliveries(gomock.Any()).DoAndReturn(func(deliveries []model.Delivery) error {
+		deliveries[0].ID = "d2"
+		return nil
+	})
+
+	replay, err := newDispatcher(store).Replay(model.Delivery{
+		ID: "d1", WebhookID: "w1", EventID: "e1", EventType: "cat.created", Payload: []byte(`{}`),
+		Status: model.DeliveryDead, Attempts: 3, LastError: "unexpected response 500",
+	})
+	if err != nil {
+		t.Fatalf("unxpected error: %v", err)
+	}
+	if replay.ID != "d2" || replay.WebhookID != "w1" || replay.EventID != "e1" || replay.Status != model.DeliveryPending ||
+		replay.Attempts != 0 || replay.LastError != "" || !replay.NextAttempt.Equal(now) {
+		t.Errorf("unxpected replay: %+v", replay)
+	}
+}
+
+// tenantedStore is a webhook store able to scope queries to tenants.
+type tenantedStore struct {
+	*model.MockWebhookStore
+	tenants map[string]model.Storage
+}
+
+func (s tenantedStore) ForTenant(id string) model.Storage {
+	return s.tenants[id]
+}
+
+func TestDispatcher_DispatchScopesWebhooksToTenants(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := model.NewMockWebhookStore(ctrl)
+	tenant := model.NewMockWebhookStore(ctrl)
+	store.EXPECT().ClaimDeliveries(now, gomock.Any(), claimBatchSize).Return([]model.Delivery{
+		{ID: "d1", TenantID: "t1", WebhookID: "w1", EventType: "cat.created", Payload: []byte(`{}`),
+			Status: model.DeliveryPending},
+	}, nil)
+	tenant.EXPECT().SelectWebhook("w1").Return(model.Webhook{ID: "w1", URL: server.URL, Secret: "secret"}, nil)
+	store.EXPECT().UpdateDelivery(gomock.Any()).DoAndReturn(func(d model.Delivery) error {
+		if d.Status != model.DeliverySucceeded {
+			t.Errorf("unxpected delivery: %+v", d)
+		}
+		return nil
+	})
+
+	tenants := map[string]model.Storage{"t1": struct {
+		*model.MockStorage
+		*model.MockWebhookStore
+	}{model.NewMockStorage(ctrl), tenant}}
+	if _, err := newDispatcher(tenantedStore{store, tenants}).Dispatch(context.Background()); err != nil {
+		t.Errorf("unxpected error: %v", err)
+	}
+}